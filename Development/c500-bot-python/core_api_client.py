@@ -6,8 +6,9 @@ class CoreAPIClient:
     
     def __init__(self):
         self.base_url = config.CORE_API_URL
-        # We use a shared session for performance
-        self.session = aiohttp.ClientSession() 
+        # We use a shared session for performance. Every request carries the
+        # internal API key: the Core only trusts the IDs we send if it's there.
+        self.session = aiohttp.ClientSession(headers={"X-Internal-API-Key": config.INTERNAL_API_KEY})
 
     async def close(self):
         await self.session.close()
@@ -31,3 +32,12 @@ class CoreAPIClient:
         endpoint = f"{self.base_url}/api/internal/drops/create"
         # ... similar async POST logic ...
       
+
+    async def record_drop_post(self, drop_id: str, guild_id: str, channel_id: str, message_id: str):
+        """Tells the Core where a drop embed was posted, so it is edited
+        (Reserved / SOLD / back in stock) whenever the drop's status changes."""
+        endpoint = f"{self.base_url}/api/v1/drops/{drop_id}/posts"
+        payload = {"guild_id": guild_id, "channel_id": channel_id, "message_id": message_id}
+
+        async with self.session.post(endpoint, json=payload) as resp:
+            resp.raise_for_status()
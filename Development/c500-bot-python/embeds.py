@@ -1,6 +1,6 @@
 import discord
 from datetime import datetime
-from typing import Optional
+from typing import List, Optional
 
 # ==========================================
 # C500 Brand Color Palette (Pastel/Cozy Aesthetic)
@@ -26,11 +26,15 @@ def create_marketplace_drop_embed(
     seller_name: str,
     drop_id: str,
     image_url: Optional[str] = None,
-    drop_type: str = "rts"
+    drop_type: str = "rts",
+    spec_display: Optional[List[dict]] = None
 ) -> discord.Embed:
     """
     Creates the main, highly visible embed for a new item listing in the #marketplace channel.
     This is the "storefront window" for an item.
+
+    `spec_display` is the ordered list of {"label", "value"} pairs the Core API returns
+    with every drop. We render it as-is so specs read the same here and on the website.
     """
     # Choose an emoji based on drop type
     type_emoji = "📦" if drop_type == "rts" else "🎨"
@@ -57,6 +61,14 @@ def create_marketplace_drop_embed(
         inline=True
     )
 
+    # Typed specs (Layout, Switches, PCB, ...) rendered in the Core's order
+    for field in spec_display or []:
+        embed.add_field(
+            name=field["label"],
+            value=field["value"],
+            inline=True
+        )
+
     # Drop ID (small, for reference)
     embed.add_field(
         name="🆔 Drop ID",
@@ -0,0 +1,34 @@
+package domain
+
+import "time"
+
+// Analytics metrics. Each is a counter in AnalyticsDay.Counts and, where it
+// concerns one drop, in AnalyticsDay.Drops[dropID] too.
+const (
+	MetricProfileViews       = "profile_views"       // c500.store/builder/<slug> page loads
+	MetricDropViews          = "drop_views"          // Product page loads
+	MetricVisitors           = "visitors"            // Unique visitors that day, across all their pages
+	MetricDiscordImpressions = "discord_impressions" // Drop embeds posted in Discord
+	MetricDiscordClicks      = "discord_clicks"      // Buttons clicked on those embeds
+	MetricCheckoutStarts     = "checkout_starts"     // Buyers sent to Stripe (drop -> pending)
+	MetricConversions        = "conversions"         // Paid checkouts (drop -> sold)
+)
+
+// AnalyticsDay is one builder's rolled-up counters for one UTC day.
+// Only counts are stored: no IPs, user agents, cookies or visitor IDs.
+//
+// Stored in the "analytics_daily" collection, keyed by "<builderID>_<day>".
+type AnalyticsDay struct {
+	BuilderID string `json:"builder_id" firestore:"builder_id"`
+	// Day is "2006-01-02" in UTC, so range queries sort correctly as strings.
+	Day string `json:"day" firestore:"day"`
+
+	Counts map[string]int64 `json:"counts" firestore:"counts"`
+	// Drops breaks the drop-level metrics down by drop ID.
+	Drops map[string]map[string]int64 `json:"drops,omitempty" firestore:"drops,omitempty"`
+
+	UpdatedAt time.Time `json:"updated_at" firestore:"updated_at"`
+}
+
+// AnalyticsDayFormat is the layout of AnalyticsDay.Day.
+const AnalyticsDayFormat = "2006-01-02"
@@ -0,0 +1,150 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"c500-core-go/internal/service"
+)
+
+// AnalyticsHandler takes page views from the web server and Discord events
+// from the bots, and serves builders their numbers.
+type AnalyticsHandler struct {
+	analyticsService service.AnalyticsService
+}
+
+// NewAnalyticsHandler is the constructor.
+func NewAnalyticsHandler(as service.AnalyticsService) *AnalyticsHandler {
+	return &AnalyticsHandler{
+		analyticsService: as,
+	}
+}
+
+// RegisterRoutes connects the HTTP URLs to the handler functions.
+// This is called in main.go.
+func (h *AnalyticsHandler) RegisterRoutes(router *gin.RouterGroup) {
+	// The hit counter is shown on the public profile page.
+	router.GET("/builders/:discordID/analytics/hits", h.HitCount)
+}
+
+// RegisterInternalRoutes connects recording (which trusts the client IP the
+// web server forwards) and a builder's private summary. main.go puts them
+// behind the internal API key.
+func (h *AnalyticsHandler) RegisterInternalRoutes(router *gin.RouterGroup) {
+	router.POST("/analytics/views", h.RecordView)
+	router.POST("/analytics/events", h.RecordEvent)
+
+	router.GET("/builders/:discordID/analytics", h.Summary)
+}
+
+// ==========================================
+// Request/Response Structs (Data Contracts)
+// ==========================================
+
+// recordViewRequest is sent by the web server for every page it renders.
+// The IP and user agent are hashed with a salt that is thrown away after the
+// day, never stored.
+type recordViewRequest struct {
+	BuilderID string `json:"builder_id"`
+	DropID    string `json:"drop_id"`
+	IP        string `json:"ip" binding:"required"`
+	UserAgent string `json:"user_agent"`
+}
+
+// recordEventRequest is sent by the bots. Checkout starts and conversions are
+// counted by the Core itself, so only Discord metrics are accepted here.
+type recordEventRequest struct {
+	BuilderID string `json:"builder_id"`
+	DropID    string `json:"drop_id"`
+	Metric    string `json:"metric" binding:"required,oneof=discord_impressions discord_clicks"`
+}
+
+// ==========================================
+// Handler Functions
+// ==========================================
+
+// RecordView handles POST /api/v1/analytics/views
+func (h *AnalyticsHandler) RecordView(c *gin.Context) {
+	var req recordViewRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if req.BuilderID == "" && req.DropID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "builder_id or drop_id is required"})
+		return
+	}
+
+	err := h.analyticsService.RecordView(c.Request.Context(), service.PageView{
+		BuilderID: req.BuilderID,
+		DropID:    req.DropID,
+		IP:        req.IP,
+		UserAgent: req.UserAgent,
+	})
+	if err != nil {
+		h.writeError(c, err, "Failed to record view")
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// RecordEvent handles POST /api/v1/analytics/events
+func (h *AnalyticsHandler) RecordEvent(c *gin.Context) {
+	var req recordEventRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if req.BuilderID == "" && req.DropID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "builder_id or drop_id is required"})
+		return
+	}
+
+	if err := h.analyticsService.RecordEvent(c.Request.Context(), req.BuilderID, req.DropID, req.Metric); err != nil {
+		h.writeError(c, err, "Failed to record event")
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// Summary handles GET /api/v1/builders/:discordID/analytics?days=30
+func (h *AnalyticsHandler) Summary(c *gin.Context) {
+	days, err := strconv.Atoi(c.DefaultQuery("days", "30"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": service.ErrAnalyticsRange.Error()})
+		return
+	}
+
+	summary, err := h.analyticsService.Summary(c.Request.Context(), c.Param("discordID"), days)
+	if err != nil {
+		h.writeError(c, err, "Failed to load analytics")
+		return
+	}
+	c.JSON(http.StatusOK, summary)
+}
+
+// HitCount handles GET /api/v1/builders/:discordID/analytics/hits
+// It backs the [[hit counter]] profile widget.
+func (h *AnalyticsHandler) HitCount(c *gin.Context) {
+	hits, err := h.analyticsService.HitCount(c.Request.Context(), c.Param("discordID"))
+	if err != nil {
+		h.writeError(c, err, "Failed to load hit count")
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"profile_views": hits})
+}
+
+// writeError maps service errors to HTTP status codes.
+func (h *AnalyticsHandler) writeError(c *gin.Context, err error, fallback string) {
+	switch {
+	case errors.Is(err, service.ErrDropNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Drop not found"})
+	case errors.Is(err, service.ErrUnknownMetric), errors.Is(err, service.ErrAnalyticsRange):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": fallback})
+	}
+}
@@ -0,0 +1,390 @@
+package service
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"regexp"
+	"sync"
+	"time"
+
+	"c500-core-go/internal/domain"
+)
+
+var (
+	ErrUnknownMetric  = errors.New("unknown analytics metric")
+	ErrAnalyticsRange = errors.New("days must be between 1 and 90")
+)
+
+const (
+	// Counters are rolled up in memory and written at most this often, so a
+	// popular page costs one Firestore write per flush instead of one per view.
+	analyticsFlushInterval = 15 * time.Second
+
+	maxAnalyticsDays = 90
+
+	// Visitor markers and daily salts are deleted by a Firestore TTL policy on
+	// expires_at once the day is over, so a visitor hash can't be recomputed
+	// or linked to the next day's.
+	analyticsVisitorTTL = 48 * time.Hour
+)
+
+// recordableMetrics are the metrics RecordEvent accepts.
+var recordableMetrics = map[string]bool{
+	domain.MetricDiscordImpressions: true,
+	domain.MetricDiscordClicks:      true,
+	domain.MetricCheckoutStarts:     true,
+	domain.MetricConversions:        true,
+}
+
+// botUserAgent matches crawlers, link unfurlers (Discord, Slack, iMessage...),
+// uptime monitors and scripted clients. Their page loads aren't counted.
+var botUserAgent = regexp.MustCompile(`(?i)(bot|crawl|spider|slurp|archiver|preview|facebookexternalhit|` +
+	`embedly|headless|lighthouse|curl|wget|python|go-http-client|java/|okhttp|node-fetch|axios|` +
+	`scrapy|httpclient|monitor|uptime|pingdom)`)
+
+// AnalyticsRepository persists rolled-up counters and the daily visitor markers.
+// Implemented in internal/database/firestore.go
+type AnalyticsRepository interface {
+	// IncrementAnalytics adds delta's counters to the builder's day and to their lifetime totals.
+	IncrementAnalytics(ctx context.Context, delta *domain.AnalyticsDay) error
+	// ListAnalyticsDays returns the builder's days in [from, to], oldest first.
+	ListAnalyticsDays(ctx context.Context, builderID, from, to string) ([]domain.AnalyticsDay, error)
+	GetAnalyticsTotals(ctx context.Context, builderID string) (map[string]int64, error)
+	// MarkVisitor stores a visitor marker, returning false if it already existed.
+	MarkVisitor(ctx context.Context, key string, expires time.Time) (bool, error)
+	// DailySalt returns the day's random salt, creating it on first use.
+	DailySalt(ctx context.Context, day string, expires time.Time) ([]byte, error)
+}
+
+// PageView is one page load reported by the web server. IP and UserAgent are
+// only used to tell visitors apart for the day; neither is stored.
+type PageView struct {
+	BuilderID string
+	DropID    string // Empty for the profile page
+	IP        string
+	UserAgent string
+}
+
+// AnalyticsSummary is what a builder sees for a date range.
+type AnalyticsSummary struct {
+	BuilderID string `json:"builder_id"`
+	From      string `json:"from"`
+	To        string `json:"to"`
+
+	// Totals sums every metric over the range. "visitors" is the sum of each
+	// day's unique visitors: visitors can't be recognized across days by design.
+	Totals map[string]int64 `json:"totals"`
+
+	// CheckoutRate is checkout starts per visitor; ConversionRate is paid
+	// checkouts per checkout start.
+	CheckoutRate   float64 `json:"checkout_rate"`
+	ConversionRate float64 `json:"conversion_rate"`
+
+	// Days has one entry per day in the range, including empty days.
+	Days []domain.AnalyticsDay `json:"days"`
+	// Drops sums the per-drop metrics over the range.
+	Drops map[string]map[string]int64 `json:"drops"`
+}
+
+// AnalyticsService defines the methods handlers and other services use.
+type AnalyticsService interface {
+	RecordView(ctx context.Context, view PageView) error
+	// RecordEvent counts a non-page metric. builderID may be empty if dropID is set.
+	RecordEvent(ctx context.Context, builderID, dropID, metric string) error
+	Summary(ctx context.Context, builderID string, days int) (*AnalyticsSummary, error)
+	// HitCount is the builder's all-time profile views, for the profile hit counter.
+	HitCount(ctx context.Context, builderID string) (int64, error)
+}
+
+// analyticsService is the concrete implementation.
+type analyticsService struct {
+	repo  AnalyticsRepository
+	drops DropRepository
+
+	mu      sync.Mutex
+	pending map[string]*domain.AnalyticsDay // keyed by "<builderID>_<day>"
+	saltDay string
+	salt    []byte
+}
+
+// NewAnalyticsService constructor used in main.go. Start Run in a goroutine
+// or counters are never written.
+func NewAnalyticsService(repo AnalyticsRepository, drops DropRepository) *analyticsService {
+	return &analyticsService{
+		repo:    repo,
+		drops:   drops,
+		pending: map[string]*domain.AnalyticsDay{},
+	}
+}
+
+// ==========================================
+// Recording
+// ==========================================
+
+// RecordView counts a page load, and a visitor if it's their first load of
+// any of this builder's pages today. Bots are ignored.
+func (s *analyticsService) RecordView(ctx context.Context, view PageView) error {
+	if view.UserAgent == "" || botUserAgent.MatchString(view.UserAgent) {
+		return nil
+	}
+	if view.BuilderID == "" {
+		builderID, err := s.sellerOf(ctx, view.DropID)
+		if err != nil {
+			return err
+		}
+		view.BuilderID = builderID
+	}
+
+	now := time.Now().UTC()
+	metric := domain.MetricProfileViews
+	if view.DropID != "" {
+		metric = domain.MetricDropViews
+	}
+	s.add(view.BuilderID, view.DropID, metric, now)
+
+	newVisitor, err := s.markVisitor(ctx, view, now)
+	if err != nil {
+		// The view is counted; only the unique count misses it.
+		return fmt.Errorf("failed to mark visitor: %w", err)
+	}
+	if newVisitor {
+		s.add(view.BuilderID, "", domain.MetricVisitors, now)
+	}
+	return nil
+}
+
+// RecordEvent counts a Discord impression/click or a checkout step.
+func (s *analyticsService) RecordEvent(ctx context.Context, builderID, dropID, metric string) error {
+	if !recordableMetrics[metric] {
+		return ErrUnknownMetric
+	}
+	if builderID == "" {
+		var err error
+		if builderID, err = s.sellerOf(ctx, dropID); err != nil {
+			return err
+		}
+	}
+	s.add(builderID, dropID, metric, time.Now().UTC())
+	return nil
+}
+
+func (s *analyticsService) sellerOf(ctx context.Context, dropID string) (string, error) {
+	if dropID == "" {
+		return "", errors.New("analytics: builder or drop ID required")
+	}
+	drop, err := s.drops.GetDropByID(ctx, dropID)
+	if err != nil {
+		return "", err
+	}
+	return drop.SellerDiscordID, nil
+}
+
+// markVisitor hashes the visitor with the day's secret salt. The same person
+// gets the same hash all day on one builder's pages, and an unrelated one on
+// other builders' pages and on other days.
+func (s *analyticsService) markVisitor(ctx context.Context, view PageView, now time.Time) (bool, error) {
+	day := now.Format(domain.AnalyticsDayFormat)
+	salt, err := s.dailySalt(ctx, day, now)
+	if err != nil {
+		return false, err
+	}
+	mac := hmac.New(sha256.New, salt)
+	mac.Write([]byte(view.BuilderID + "|" + view.IP + "|" + view.UserAgent))
+	key := day + "_" + hex.EncodeToString(mac.Sum(nil)[:16])
+	return s.repo.MarkVisitor(ctx, key, now.Add(analyticsVisitorTTL))
+}
+
+func (s *analyticsService) dailySalt(ctx context.Context, day string, now time.Time) ([]byte, error) {
+	s.mu.Lock()
+	if s.saltDay == day {
+		salt := s.salt
+		s.mu.Unlock()
+		return salt, nil
+	}
+	s.mu.Unlock()
+
+	// Shared through Firestore so every Core instance hashes the same way.
+	salt, err := s.repo.DailySalt(ctx, day, now.Add(analyticsVisitorTTL))
+	if err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	s.saltDay, s.salt = day, salt
+	s.mu.Unlock()
+	return salt, nil
+}
+
+// add bumps a pending counter; Run writes it out.
+func (s *analyticsService) add(builderID, dropID, metric string, now time.Time) {
+	day := now.Format(domain.AnalyticsDayFormat)
+	key := builderID + "_" + day
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	d, ok := s.pending[key]
+	if !ok {
+		d = &domain.AnalyticsDay{BuilderID: builderID, Day: day, Counts: map[string]int64{}}
+		s.pending[key] = d
+	}
+	d.Counts[metric]++
+	if dropID != "" {
+		if d.Drops == nil {
+			d.Drops = map[string]map[string]int64{}
+		}
+		if d.Drops[dropID] == nil {
+			d.Drops[dropID] = map[string]int64{}
+		}
+		d.Drops[dropID][metric]++
+	}
+}
+
+// Run flushes the rolled-up counters until ctx is cancelled, then once more.
+// Start it in a goroutine from main.go.
+func (s *analyticsService) Run(ctx context.Context) {
+	ticker := time.NewTicker(analyticsFlushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			flushCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			s.flush(flushCtx)
+			cancel()
+			return
+		case <-ticker.C:
+			s.flush(ctx)
+		}
+	}
+}
+
+// flush writes every pending day. A failed write loses those counts rather
+// than retrying forever: analytics are allowed to be slightly off.
+func (s *analyticsService) flush(ctx context.Context) {
+	s.mu.Lock()
+	batch := s.pending
+	s.pending = map[string]*domain.AnalyticsDay{}
+	s.mu.Unlock()
+
+	for key, delta := range batch {
+		if err := s.repo.IncrementAnalytics(ctx, delta); err != nil {
+			log.Printf("analytics: failed to flush %s: %v", key, err)
+		}
+	}
+}
+
+// ==========================================
+// Reporting
+// ==========================================
+
+// Summary returns the last `days` days (today included) of a builder's analytics.
+func (s *analyticsService) Summary(ctx context.Context, builderID string, days int) (*AnalyticsSummary, error) {
+	if days < 1 || days > maxAnalyticsDays {
+		return nil, ErrAnalyticsRange
+	}
+	today := time.Now().UTC()
+	from := today.AddDate(0, 0, -(days - 1)).Format(domain.AnalyticsDayFormat)
+	to := today.Format(domain.AnalyticsDayFormat)
+
+	stored, err := s.repo.ListAnalyticsDays(ctx, builderID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	byDay := make(map[string]domain.AnalyticsDay, len(stored))
+	for _, d := range stored {
+		byDay[d.Day] = d
+	}
+
+	summary := &AnalyticsSummary{
+		BuilderID: builderID,
+		From:      from,
+		To:        to,
+		Totals:    map[string]int64{},
+		Days:      make([]domain.AnalyticsDay, 0, days),
+		Drops:     map[string]map[string]int64{},
+	}
+	for i := days - 1; i >= 0; i-- {
+		day := today.AddDate(0, 0, -i).Format(domain.AnalyticsDayFormat)
+		d, ok := byDay[day]
+		if !ok {
+			d = domain.AnalyticsDay{BuilderID: builderID, Day: day, Counts: map[string]int64{}}
+		}
+		for metric, n := range d.Counts {
+			summary.Totals[metric] += n
+		}
+		for dropID, counts := range d.Drops {
+			if summary.Drops[dropID] == nil {
+				summary.Drops[dropID] = map[string]int64{}
+			}
+			for metric, n := range counts {
+				summary.Drops[dropID][metric] += n
+			}
+		}
+		summary.Days = append(summary.Days, d)
+	}
+
+	summary.CheckoutRate = ratio(summary.Totals[domain.MetricCheckoutStarts], summary.Totals[domain.MetricVisitors])
+	summary.ConversionRate = ratio(summary.Totals[domain.MetricConversions], summary.Totals[domain.MetricCheckoutStarts])
+	return summary, nil
+}
+
+// HitCount returns all-time profile views. Views from the last flush
+// interval aren't included yet.
+func (s *analyticsService) HitCount(ctx context.Context, builderID string) (int64, error) {
+	totals, err := s.repo.GetAnalyticsTotals(ctx, builderID)
+	if err != nil {
+		return 0, err
+	}
+	return totals[domain.MetricProfileViews], nil
+}
+
+func ratio(n, d int64) float64 {
+	if d == 0 {
+		return 0
+	}
+	return float64(n) / float64(d)
+}
+
+// ==========================================
+// Checkout tracking
+// ==========================================
+
+// analyticsDropRepository wraps a DropRepository so every status change a
+// checkout makes is counted: pending is a checkout start, sold a conversion.
+type analyticsDropRepository struct {
+	DropRepository
+	analytics AnalyticsService
+}
+
+// NewAnalyticsDropRepository constructor used in main.go. Give the result to
+// any service that changes drop status instead of the bare Firestore client.
+func NewAnalyticsDropRepository(repo DropRepository, analytics AnalyticsService) DropRepository {
+	return &analyticsDropRepository{
+		DropRepository: repo,
+		analytics:      analytics,
+	}
+}
+
+// UpdateDropStatus saves the new status, then counts it.
+func (r *analyticsDropRepository) UpdateDropStatus(ctx context.Context, dropID string, newStatus domain.DropStatus) error {
+	if err := r.DropRepository.UpdateDropStatus(ctx, dropID, newStatus); err != nil {
+		return err
+	}
+	metric := ""
+	switch newStatus {
+	case domain.StatusPending:
+		metric = domain.MetricCheckoutStarts
+	case domain.StatusSold:
+		metric = domain.MetricConversions
+	}
+	if metric != "" {
+		if err := r.analytics.RecordEvent(ctx, "", dropID, metric); err != nil {
+			log.Printf("analytics: drop %s is now %s but wasn't counted: %v", dropID, newStatus, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,142 @@
+package media
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image/gif"
+	"net/http"
+)
+
+// Animated GIFs are decoded frame by frame, so a long animation is a much
+// bigger decompression bomb than a single image of the same dimensions. Both
+// limits are checked from the file structure before anything is decoded.
+const (
+	maxAnimationFrames = 500
+	maxAnimationPixels = 150_000_000 // Summed over all frames
+)
+
+var ErrUnsupportedFont = errors.New("unsupported font type (use WOFF2, WOFF, TTF or OTF)")
+
+// fontTypes are the sniffed content types accepted by SniffFont.
+var fontTypes = map[string]bool{
+	"font/woff2": true,
+	"font/woff":  true,
+	"font/ttf":   true,
+	"font/otf":   true,
+}
+
+// ProcessAnimatedGIF validates a GIF and re-encodes it with every frame kept.
+//
+// Process keeps only a GIF's first frame, which is right for product photos
+// but not for profile decorations. Re-encoding still drops comment and
+// application extensions (other than the loop count), so nothing but the
+// frames themselves survives.
+func ProcessAnimatedGIF(data []byte) (*Rendition, error) {
+	if len(data) > MaxUploadBytes {
+		return nil, ErrTooLarge
+	}
+	if ct := http.DetectContentType(data); ct != "image/gif" {
+		return nil, fmt.Errorf("%w: got %s", ErrUnsupportedType, ct)
+	}
+
+	// 1. Check dimensions and frame count BEFORE decoding.
+	cfg, err := gif.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrCorruptImage, err)
+	}
+	frames, err := countGIFFrames(data)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrCorruptImage, err)
+	}
+	if frames > maxAnimationFrames || cfg.Width*cfg.Height*frames > maxAnimationPixels || cfg.Width*cfg.Height > maxPixels {
+		return nil, fmt.Errorf("%w: %dx%d with %d frames exceeds the animation limit", ErrTooLarge, cfg.Width, cfg.Height, frames)
+	}
+
+	// 2. Decode and re-encode every frame.
+	g, err := gif.DecodeAll(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrCorruptImage, err)
+	}
+	var buf bytes.Buffer
+	if err := gif.EncodeAll(&buf, g); err != nil {
+		return nil, fmt.Errorf("failed to encode gif: %w", err)
+	}
+	return &Rendition{
+		Name:        "original",
+		Data:        buf.Bytes(),
+		ContentType: "image/gif",
+		Width:       cfg.Width,
+		Height:      cfg.Height,
+	}, nil
+}
+
+// SniffFont returns the content type of a web font, trusting the bytes only.
+// Fonts can't be re-encoded like images; browsers run them through their own
+// font sanitizer (OTS) before use, so the type check is what matters here.
+func SniffFont(data []byte) (string, error) {
+	if len(data) > MaxUploadBytes {
+		return "", ErrTooLarge
+	}
+	ct := http.DetectContentType(data)
+	if !fontTypes[ct] {
+		return "", fmt.Errorf("%w: got %s", ErrUnsupportedFont, ct)
+	}
+	return ct, nil
+}
+
+// countGIFFrames walks the GIF block structure without decompressing anything.
+func countGIFFrames(data []byte) (int, error) {
+	if len(data) < 13 {
+		return 0, errors.New("truncated header")
+	}
+	pos := 13 // "GIF89a" + logical screen descriptor
+	if flags := data[10]; flags&0x80 != 0 {
+		pos += 3 << ((flags & 0x07) + 1) // Global color table
+	}
+
+	frames := 0
+	for pos < len(data) {
+		switch data[pos] {
+		case 0x21: // Extension: label, then sub-blocks
+			var err error
+			if pos, err = skipSubBlocks(data, pos+2); err != nil {
+				return 0, err
+			}
+		case 0x2C: // Image descriptor
+			if pos+10 > len(data) {
+				return 0, errors.New("truncated image descriptor")
+			}
+			frames++
+			flags := data[pos+9]
+			pos += 10
+			if flags&0x80 != 0 {
+				pos += 3 << ((flags & 0x07) + 1) // Local color table
+			}
+			var err error
+			if pos, err = skipSubBlocks(data, pos+1); err != nil { // +1: LZW minimum code size
+				return 0, err
+			}
+		case 0x3B: // Trailer
+			return frames, nil
+		default:
+			return 0, fmt.Errorf("unknown block 0x%02x", data[pos])
+		}
+	}
+	return frames, nil // Missing trailer; the decoder tolerates it too.
+}
+
+// skipSubBlocks moves past a chain of length-prefixed sub-blocks ending in a zero length.
+func skipSubBlocks(data []byte, pos int) (int, error) {
+	for {
+		if pos >= len(data) {
+			return 0, errors.New("truncated data sub-blocks")
+		}
+		n := int(data[pos])
+		pos++
+		if n == 0 {
+			return pos, nil
+		}
+		pos += n
+	}
+}
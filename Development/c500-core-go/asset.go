@@ -0,0 +1,44 @@
+package domain
+
+import "time"
+
+// Image variant names. Every uploaded image is stored in all three sizes.
+const (
+	VariantOriginal = "original" // Re-encoded full image (max 2048px), metadata stripped
+	VariantEmbed    = "embed"    // 1200px wide, used for Discord embeds and Stripe checkout
+	VariantThumb    = "thumb"    // 320px square crop, used in shop grids
+)
+
+// AssetVariant is one stored rendition of an uploaded image.
+type AssetVariant struct {
+	// StorageKey is the path inside the storage backend, e.g. "images/ab12.../embed.jpg".
+	StorageKey  string `json:"-" firestore:"storage_key"`
+	URL         string `json:"url" firestore:"url"`
+	ContentType string `json:"content_type" firestore:"content_type"`
+	Width       int    `json:"width" firestore:"width"`
+	Height      int    `json:"height" firestore:"height"`
+	SizeBytes   int    `json:"size_bytes" firestore:"size_bytes"`
+}
+
+// ImageAsset is a photo uploaded through the Core and hosted by us,
+// replacing pasted Discord CDN links that expire.
+// Stored in the "assets" collection.
+type ImageAsset struct {
+	ID string `json:"id" firestore:"id"`
+
+	// OwnerDiscordID is the builder who uploaded it. Only the owner may attach it to a drop.
+	OwnerDiscordID string `json:"owner_discord_id" firestore:"owner_discord_id"`
+
+	// Variants is keyed by VariantOriginal / VariantEmbed / VariantThumb.
+	Variants map[string]AssetVariant `json:"variants" firestore:"variants"`
+
+	CreatedAt time.Time `json:"created_at" firestore:"created_at"`
+}
+
+// URL returns the public URL of a variant, falling back to the original.
+func (a *ImageAsset) URL(variant string) string {
+	if v, ok := a.Variants[variant]; ok {
+		return v.URL
+	}
+	return a.Variants[VariantOriginal].URL
+}
@@ -0,0 +1,98 @@
+package http
+
+import (
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"c500-core-go/internal/media"
+	"c500-core-go/internal/service"
+)
+
+// AssetHandler handles image uploads from the bot and the seller dashboard.
+type AssetHandler struct {
+	assetService service.AssetService
+}
+
+// NewAssetHandler is the constructor.
+func NewAssetHandler(as service.AssetService) *AssetHandler {
+	return &AssetHandler{
+		assetService: as,
+	}
+}
+
+// RegisterRoutes connects the HTTP URLs to the handler functions.
+// This is called in main.go, on the internal group: uploads trust owner_discord_id.
+func (h *AssetHandler) RegisterRoutes(router *gin.RouterGroup) {
+	router.POST("/assets/images", h.UploadImage)
+}
+
+// ==========================================
+// Handler Functions
+// ==========================================
+
+// UploadImage handles POST /api/v1/assets/images
+// It expects multipart/form-data with:
+//
+//	file               the image (JPEG, PNG, GIF or WebP, max 15 MB)
+//	owner_discord_id   the uploading seller
+//
+// The response contains the asset ID to pass in a drop's "image_asset_ids".
+func (h *AssetHandler) UploadImage(c *gin.Context) {
+	// 1. Hard cap on the request body BEFORE reading anything, so a 2 GB upload
+	// is cut off at the socket instead of filling memory. (+1 MB for form overhead.)
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, media.MaxUploadBytes+(1<<20))
+
+	ownerID := c.PostForm("owner_discord_id")
+	if ownerID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "owner_discord_id is required"})
+		return
+	}
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		var tooBig *http.MaxBytesError
+		if errors.As(err, &tooBig) {
+			c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": "Image must be 15 MB or smaller"})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": "file is required"})
+		return
+	}
+	if fileHeader.Size > media.MaxUploadBytes {
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": "Image must be 15 MB or smaller"})
+		return
+	}
+
+	// 2. Read the bytes. Content type is sniffed from the data in the media package;
+	// the filename and the client's Content-Type header are deliberately ignored.
+	f, err := fileHeader.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Could not read upload"})
+		return
+	}
+	defer f.Close()
+	data, err := io.ReadAll(io.LimitReader(f, media.MaxUploadBytes+1))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Could not read upload"})
+		return
+	}
+
+	// 3. Call the Service Layer
+	asset, err := h.assetService.UploadImage(c.Request.Context(), ownerID, data)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrInvalidUpload):
+			// Wrong type, corrupt file or too many pixels: tell the seller why.
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to store image"})
+		}
+		return
+	}
+
+	// 4. Success
+	c.JSON(http.StatusCreated, asset)
+}
@@ -0,0 +1,156 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"path"
+	"time"
+
+	"c500-core-go/internal/domain"
+	"c500-core-go/internal/media"
+	"c500-core-go/internal/storage"
+)
+
+var (
+	ErrAssetNotFound = errors.New("asset not found")
+	ErrAssetNotOwned = errors.New("asset belongs to a different user")
+	ErrInvalidUpload = errors.New("invalid image upload")
+	ErrTooManyImages = errors.New("too many images attached")
+)
+
+// maxImagesPerDrop keeps embeds and product pages reasonable.
+const maxImagesPerDrop = 8
+
+// imageVariants are the sizes generated for every upload.
+var imageVariants = []media.VariantSpec{
+	{Name: domain.VariantOriginal, MaxWidth: 2048},
+	{Name: domain.VariantEmbed, MaxWidth: 1200},
+	{Name: domain.VariantThumb, MaxWidth: 320, Square: true},
+}
+
+// AssetRepository persists asset metadata (the bytes live in the BlobStore).
+// Implemented in internal/database/firestore.go
+type AssetRepository interface {
+	CreateAsset(ctx context.Context, asset *domain.ImageAsset) error
+	GetAssetByID(ctx context.Context, assetID string) (*domain.ImageAsset, error)
+}
+
+// AssetService defines the methods handlers and other services use.
+type AssetService interface {
+	UploadImage(ctx context.Context, ownerDiscordID string, data []byte) (*domain.ImageAsset, error)
+	ResolveForDrop(ctx context.Context, ownerDiscordID string, assetIDs []string) ([]string, error)
+}
+
+// assetService is the concrete implementation.
+type assetService struct {
+	repo  AssetRepository
+	store storage.BlobStore
+}
+
+// NewAssetService constructor used in main.go.
+func NewAssetService(repo AssetRepository, store storage.BlobStore) *assetService {
+	return &assetService{
+		repo:  repo,
+		store: store,
+	}
+}
+
+// ==========================================
+// Business Logic
+// ==========================================
+
+// UploadImage validates, strips metadata, renders every variant, stores them
+// and records the asset. Returns the asset whose ID the seller attaches to a drop.
+func (s *assetService) UploadImage(ctx context.Context, ownerDiscordID string, data []byte) (*domain.ImageAsset, error) {
+	// 1. Validate + re-encode. All media errors are user errors (bad file), so wrap them.
+	renditions, err := media.Process(data, imageVariants)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidUpload, err)
+	}
+
+	// 2. Generate an unguessable ID; it's also the storage folder.
+	assetID, err := newAssetID()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate asset id: %w", err)
+	}
+
+	asset := &domain.ImageAsset{
+		ID:             assetID,
+		OwnerDiscordID: ownerDiscordID,
+		Variants:       make(map[string]domain.AssetVariant, len(renditions)),
+		CreatedAt:      time.Now().UTC(),
+	}
+
+	// 3. Upload every variant. If any upload fails, clean up the ones that succeeded.
+	for _, r := range renditions {
+		key := path.Join("images", assetID, r.Name+extensionFor(r.ContentType))
+		if err := s.store.Put(ctx, key, r.ContentType, r.Data); err != nil {
+			s.cleanup(ctx, asset)
+			return nil, fmt.Errorf("failed to store %s variant: %w", r.Name, err)
+		}
+		asset.Variants[r.Name] = domain.AssetVariant{
+			StorageKey:  key,
+			URL:         s.store.PublicURL(key),
+			ContentType: r.ContentType,
+			Width:       r.Width,
+			Height:      r.Height,
+			SizeBytes:   len(r.Data),
+		}
+	}
+
+	// 4. Record the metadata.
+	if err := s.repo.CreateAsset(ctx, asset); err != nil {
+		s.cleanup(ctx, asset)
+		return nil, fmt.Errorf("failed to save asset record: %w", err)
+	}
+
+	return asset, nil
+}
+
+// ResolveForDrop checks that every asset exists and belongs to the seller,
+// and returns their embed-size URLs in the same order.
+func (s *assetService) ResolveForDrop(ctx context.Context, ownerDiscordID string, assetIDs []string) ([]string, error) {
+	if len(assetIDs) > maxImagesPerDrop {
+		return nil, fmt.Errorf("%w: max %d", ErrTooManyImages, maxImagesPerDrop)
+	}
+
+	urls := make([]string, 0, len(assetIDs))
+	for _, id := range assetIDs {
+		asset, err := s.repo.GetAssetByID(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		// SECURITY CHECK: sellers can't attach someone else's photos.
+		if asset.OwnerDiscordID != ownerDiscordID {
+			return nil, ErrAssetNotOwned
+		}
+		urls = append(urls, asset.URL(domain.VariantEmbed))
+	}
+	return urls, nil
+}
+
+// cleanup deletes any variants already uploaded. Best effort: errors are ignored,
+// orphaned objects are harmless and can be swept by a bucket lifecycle rule.
+func (s *assetService) cleanup(ctx context.Context, asset *domain.ImageAsset) {
+	for _, v := range asset.Variants {
+		_ = s.store.Delete(ctx, v.StorageKey)
+	}
+}
+
+func newAssetID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+func extensionFor(contentType string) string {
+	if contentType == "image/png" {
+		return ".png"
+	}
+	return ".jpg"
+}
@@ -0,0 +1,33 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"os"
+
+	"github.com/gin-gonic/gin"
+)
+
+// InternalAPIKeyHeader carries the shared secret our own services (the
+// Discord bot, the web server) send on every request to the Core.
+const InternalAPIKeyHeader = "X-Internal-API-Key"
+
+// InternalAuthCheck only lets requests through that carry INTERNAL_API_KEY.
+// Routes behind it may trust the Discord IDs in the request (a moderator, a
+// profile's author): the bot takes them from the Discord interaction, which
+// the clicker can't forge. Without a configured key every request is refused.
+func InternalAuthCheck() gin.HandlerFunc {
+	key := os.Getenv("INTERNAL_API_KEY")
+	return func(c *gin.Context) {
+		if key == "" {
+			c.AbortWithStatusJSON(http.StatusServiceUnavailable, gin.H{"error": "Internal API is not configured"})
+			return
+		}
+		given := c.GetHeader(InternalAPIKeyHeader)
+		if subtle.ConstantTimeCompare([]byte(given), []byte(key)) != 1 {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Invalid internal API key"})
+			return
+		}
+		c.Next()
+	}
+}
@@ -7,12 +7,11 @@ import (
 // ProfileData holds the custom "Geocities-style" content for a builder's public profile.
 // This data is stored nested within the Builder document in Firestore.
 type ProfileData struct {
-	// Raw HTML submitted by the user.
-	// SECURITY CRITICAL: This must be securely sanitized before rendering on the web client.
+	// HTML submitted by the user, stored ONLY after sanitize.HTML (see profile_service.go).
 	HTML string `json:"html" firestore:"html"`
 
-	// Raw CSS submitted by the user.
-	// SECURITY CRITICAL: This must be securely sanitized/scoped before rendering.
+	// CSS submitted by the user, stored ONLY after sanitize.CSS, so every rule is
+	// scoped under sanitize.ProfileScope.
 	CSS string `json:"css" firestore:"css"`
 }
 
@@ -24,6 +23,9 @@ type Builder struct {
 	// In our design, this is identical to the DiscordID.
 	ID string `json:"id" firestore:"id"`
 
+	// SchemaVersion records which shape this document was written with.
+	SchemaVersion int `json:"schema_version" firestore:"schema_version"`
+
 	// DiscordID is the user's unique snowflake ID from Discord.
 	// This is our primary way of identifying users across services.
 	DiscordID string `json:"discord_id" firestore:"discord_id" binding:"required"`
@@ -31,17 +33,34 @@ type Builder struct {
 	// DisplayName is their current Discord username (cached for UI display).
 	DisplayName string `json:"display_name" firestore:"display_name"`
 
+	// Slug is their vanity username for c500.store/builder/<slug>, case-folded.
+	// Empty until they claim one; see Slug for the registry and redirects.
+	Slug          string     `json:"slug,omitempty" firestore:"slug,omitempty"`
+	SlugChangedAt *time.Time `json:"slug_changed_at,omitempty" firestore:"slug_changed_at,omitempty"`
+
 	// StripeAccountID is the Connected Express Account ID (e.g., "acct_1GSE7...").
 	// This will be empty/nil if they have not completed seller onboarding.
 	// 'omitempty' means it won't be sent in JSON if it's empty.
 	StripeAccountID string `json:"stripe_account_id,omitempty" firestore:"stripe_account_id,omitempty"`
 
+	// Twitch is their verified Twitch channel, nil until they link one.
+	Twitch *TwitchAccount `json:"twitch,omitempty" firestore:"twitch,omitempty"`
+
 	// IsVerifiedBuilder is a flag set by community admins allowing access to selling commands.
 	IsVerifiedBuilder bool `json:"is_verified_builder" firestore:"is_verified_builder"`
 
 	// Profile contains their custom public profile customizations.
+	// It is a copy of the published revision (see ProfileRevision).
 	Profile ProfileData `json:"profile_data" firestore:"profile_data"`
 
+	// PublishedRevisionID is the profile revision currently live. Empty until the first publish.
+	PublishedRevisionID string `json:"published_revision_id,omitempty" firestore:"published_revision_id,omitempty"`
+
+	// ProfileAssetBytes and ProfileAssetCount track their profile asset library
+	// against its quota. Only changed together with the assets themselves (see ProfileAsset).
+	ProfileAssetBytes int64 `json:"profile_asset_bytes" firestore:"profile_asset_bytes"`
+	ProfileAssetCount int   `json:"profile_asset_count" firestore:"profile_asset_count"`
+
 	// Standard timestamps for record keeping.
 	CreatedAt time.Time `json:"created_at" firestore:"created_at"`
 	UpdatedAt time.Time `json:"updated_at" firestore:"updated_at"`
@@ -53,6 +72,7 @@ func NewBuilder(discordID, displayName string) *Builder {
 	now := time.Now().UTC()
 	return &Builder{
 		ID:                discordID, // We use the DiscordID as the primary document key
+		SchemaVersion:     BuilderSchemaVersion,
 		DiscordID:         discordID,
 		DisplayName:       displayName,
 		IsVerifiedBuilder: false, // Users start unverified by default
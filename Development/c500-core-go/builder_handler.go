@@ -0,0 +1,78 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"c500-core-go/internal/service"
+)
+
+// BuilderHandler handles seller onboarding requests from the bot.
+type BuilderHandler struct {
+	builderService service.BuilderService
+}
+
+// NewBuilderHandler is the constructor.
+func NewBuilderHandler(bs service.BuilderService) *BuilderHandler {
+	return &BuilderHandler{
+		builderService: bs,
+	}
+}
+
+// RegisterRoutes connects the HTTP URLs to the handler functions.
+// This is called in main.go, on the internal group: onboarding hands out a
+// Stripe link for whichever builder is in the path.
+func (h *BuilderHandler) RegisterRoutes(router *gin.RouterGroup) {
+	router.POST("/builders/:discordID/stripe/onboarding", h.StartStripeOnboarding)
+}
+
+// ==========================================
+// Request/Response Structs (Data Contracts)
+// ==========================================
+
+// onboardingRequest is sent by the bot when a user runs /c500 setup.
+type onboardingRequest struct {
+	// DisplayName is used if this is the user's first command and the
+	// builder record has to be created.
+	DisplayName string `json:"display_name"`
+}
+
+// ==========================================
+// Handler Functions
+// ==========================================
+
+// StartStripeOnboarding handles POST /api/v1/builders/:discordID/stripe/onboarding
+// It returns a one-time Stripe Express onboarding URL. The bot must only
+// ever show it to the builder themselves.
+func (h *BuilderHandler) StartStripeOnboarding(c *gin.Context) {
+	var req onboardingRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	discordID := c.Param("discordID")
+	if _, err := h.builderService.GetOrCreateBuilder(c.Request.Context(), discordID, req.DisplayName); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load builder"})
+		return
+	}
+
+	url, err := h.builderService.GetStripeOnboardingLink(c.Request.Context(), discordID)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrAlreadyOnboarded):
+			c.JSON(http.StatusConflict, gin.H{"error": "Your Stripe account is already connected"})
+		case errors.Is(err, service.ErrBuilderNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": "Builder not found"})
+		case errors.Is(err, service.ErrStripeError):
+			c.JSON(http.StatusBadGateway, gin.H{"error": "Stripe is unavailable, try again shortly"})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create onboarding link"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"url": url})
+}
@@ -13,6 +13,8 @@ import (
 var (
 	ErrBuilderNotFound = errors.New("builder not found")
 	ErrStripeError     = errors.New("stripe integration error")
+	// ErrAlreadyOnboarded means the builder's Stripe account is already connected.
+	ErrAlreadyOnboarded = errors.New("user is already connected to Stripe")
 )
 
 // BuilderRepository defines the interface used to persist Builder data.
@@ -22,7 +24,6 @@ type BuilderRepository interface {
 	GetByID(ctx context.Context, discordID string) (*domain.Builder, error)
 	Create(ctx context.Context, builder *domain.Builder) error
 	UpdateStripeID(ctx context.Context, discordID, stripeAccountID string) error
-	UpdateProfileData(ctx context.Context, discordID string, profile domain.ProfileData) error
 }
 
 // StripeIntegration defines the interface for talking to the Stripe API.
@@ -32,7 +33,13 @@ type StripeIntegration interface {
 	CreateAccountLink(discordID string) (string, error)
 }
 
-// BuilderService is the concrete implementation containing business logic.
+// BuilderService defines the methods the builder handler uses.
+type BuilderService interface {
+	GetOrCreateBuilder(ctx context.Context, discordID, displayName string) (*domain.Builder, error)
+	GetStripeOnboardingLink(ctx context.Context, discordID string) (string, error)
+}
+
+// builderService is the concrete implementation containing business logic.
 type builderService struct {
 	repo   BuilderRepository
 	stripe StripeIntegration
@@ -88,7 +95,7 @@ func (s *builderService) GetStripeOnboardingLink(ctx context.Context, discordID
 	if builder.StripeAccountID != "" {
 		// Depending on business logic, maybe return a "dashboard login" link instead.
 		// For now, just note they are already set up.
-		return "", errors.New("user is already connected to Stripe")
+		return "", ErrAlreadyOnboarded
 	}
 
 	// 3. Call Stripe integration to generate the secure, ephemeral link.
@@ -112,37 +119,6 @@ func (s *builderService) CompleteStripeOnboarding(ctx context.Context, discordID
 	return nil
 }
 
-// UpdateBuilderProfile handles saving the custom HTML/CSS.
-// CRITICAL: This is where security sanitization must happen.
-func (s *builderService) UpdateBuilderProfile(ctx context.Context, discordID string, rawHTML, rawCSS string) (*domain.Builder, error) {
-	// 1. Validate inputs (e.g., enforce character limits)
-	if len(rawHTML) > 10000 || len(rawCSS) > 5000 {
-		return nil, errors.New("profile content exceeds size limits")
-	}
-
-	// 2. TODO: SECURITY SANITIZATION
-	// Before saving, we MUST pass rawHTML through a strict sanitizer library like 'bluemonday'.
-	// We strip dangerous tags (<script>, <iframe>, onmouseover attributes, etc.).
-	// sanitizedHTML := bluemonday.UGCPolicy().Sanitize(rawHTML)
-	// For this example code, we assume raw is okay, but DO NOT do this in prod.
-	sanitizedHTML := rawHTML
-	sanitizedCSS := rawCSS // CSS also needs validation against malicious external url() calls.
-
-	// 3. Create the data update struct
-	newData := domain.ProfileData{
-		HTML: sanitizedHTML,
-		CSS:  sanitizedCSS,
-	}
-
-	// 4. Save to repository
-	if err := s.repo.UpdateProfileData(ctx, discordID, newData); err != nil {
-		return nil, fmt.Errorf("failed to update profile data: %w", err)
-	}
-
-	// 5. Fetch and return the updated builder info
-	return s.repo.GetByID(ctx, discordID)
-}
-
 // GetPublicBuilder is used by the Web frontend to render profiles.
 // It might apply different logic than GetByID (e.g., only showing verified builders).
 func (s *builderService) GetPublicBuilder(ctx context.Context, discordID string) (*domain.Builder, error) {
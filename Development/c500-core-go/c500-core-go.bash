@@ -7,27 +7,101 @@ c500-core-go/
 ├── config/                 # Handles environment variables securely
 │   └── config.go
 │
+├── cmd/
+│   ├── migrate/            # CLI: versioned, resumable, dry-run capable Firestore migrations
+│   │   └── main.go
+│   └── twitchstandin/      # Local stand-in for Twitch OAuth/Helix/EventSub (fake streams + VODs)
+│       └── main.go
+│
 └── internal/               # The application logic (private to this service)
     ├── domain/             # The core data structures (Structs)
+    │   ├── analytics.go    # Daily per-builder view/click/checkout counters (no visitor data)
     │   ├── builder.go      # Defines what a "Builder" user is
-    │   └── drop.go         # Defines what an Item Listing looks like
+    │   ├── carrier.go      # Supported shipping carriers + tracking number formats/check digits
+    │   ├── drop.go         # Defines what an Item Listing looks like
+    │   ├── guestbook.go    # Guestbook entries on builder profile pages
+    │   ├── guild.go        # Partner-server subscriptions + per-guild drop posts
+    │   ├── order.go        # Defines a paid transaction, its escrow state and production state machine + timeline
+    │   ├── profile_asset.go # Hosted image/GIF/font in a builder's profile asset library
+    │   ├── profile_revision.go # One saved version of a builder's profile page
+    │   ├── slug.go         # Vanity username registry entry (with rename redirects)
+    │   ├── tracking.go     # Carrier tracking statuses + transit events on an order
+    │   ├── twitch.go       # A builder's verified Twitch channel
+    │   ├── waitlist.go     # A buyer waiting for a reserved or scheduled drop
+    │   └── schema.go       # Current schema_version for each collection
+    │
+    ├── media/              # Image validation, EXIF stripping, thumbnail/embed variants
+    │   ├── processor.go
+    │   └── animation.go    # Animated GIFs (frames kept, bomb-checked) + web font sniffing
+    │
+    ├── storage/            # BlobStore interface for uploaded files
+    │   ├── local.go        # Local filesystem backend (development)
+    │   └── gcs.go          # Cloud Storage backend (production)
+    │
+    ├── sanitize/           # Builder profile sanitizing (stored XSS defense)
+    │   ├── html.go         # Allowlist HTML re-serializer
+    │   ├── css.go          # CSS parser/rewriter: scopes selectors, strips offsite url()/@import/fixed
+    │   ├── assets.go       # Only hosted profile assets may be loaded (img src, url(), @font-face)
+    │   └── sanitize_test.go # Known XSS payloads (TestCorpus, go test ./internal/sanitize)
+    │
+    ├── events/             # Firestore outbox of events the Discord bots poll and announce
+    │   └── events.go
+    │
+    ├── scheduler/          # Background jobs (scheduled drop go-live + countdowns, shipment tracking)
+    │   ├── drop_scheduler.go
+    │   └── tracking_poller.go # Polls carriers for shipped orders, records transit + delivery
+    │
+    ├── search/             # Faceted drop search (GET /api/v1/drops)
+    │   ├── index.go        # In-memory inverted index, facets, cursor pagination
+    │   └── sync.go         # Firestore snapshot listener that keeps the index fresh
+    │
+    ├── migrations/         # Rewrites old Firestore documents to the current schema
+    │   ├── migration.go    # Migration type + registry
+    │   ├── runner.go       # Batched, checkpointed runner (supports dry-run)
+    │   └── registry.go     # The actual registered migrations, in order
     │
     ├── database/           # The Data Access Layer (Talks to Firestore)
     │   └── firestore.go    # Handles reading/writing documents
     │
     ├── service/            # The Business Logic Layer ("The Brain")
+    │   ├── analytics_service.go # Cookieless view counting, bot filter, daily rollups, summaries
     │   ├── builder_service.go # Logic for onboarding, Stripe connection
-    │   └── drop_service.go    # Logic for validating and creating drops
+    │   ├── drop_service.go    # Logic for validating and creating drops
+    │   ├── guestbook_service.go # Signing (rate limit, link/profanity filter), hide/delete, moderation
+    │   ├── production_service.go # Sellers advance an order's production timeline + post progress updates (DMed, mutable), signed buyer order links
+    │   ├── profile_service.go # Profile saves as revisions, rollback, signed preview links
+    │   ├── profile_asset_service.go # Per-builder asset library: validation, content hashes, quota
+    │   ├── slug_service.go    # Vanity usernames: validation, reserved words, renames
+    │   ├── stream_service.go  # Builder goes live -> queued commissions into production, buyers notified
+    │   ├── vod_verification.go # Live fulfillment VODs must be the builder's own stream, after payment
+    │   ├── twitch_service.go  # Twitch account link/unlink (signed OAuth state, one channel per builder, EventSub subscriptions)
+    │   ├── waitlist_service.go # Drop waitlists, DMed once when a checkout expires or a scheduled drop goes live
+    │   └── fanout_service.go  # Matches drops to subscribed guilds, emits fan-out + embed sync events
     │
     ├── transport/          # The HTTP Layer (Talks to the outside world)
     │   ├── router.go       # Sets up Gin URLs and middleware security
     │   ├── middleware/     # Security checks (e.g., ensuring callers are authorized)
     │   │   └── auth.go
     │   └── handlers/       # The specific API endpoints
-    │       ├── builder_handler.go
-    │       ├── drop_handler.go
-    │       └── webhook_handler.go # Handles incoming Stripe events
+    │       ├── analytics_handler.go # Record views/Discord events, builder analytics + hit counter
+    │       ├── builder_handler.go # Stripe onboarding link for /c500 setup
+    │       ├── drop_handler.go    # Create/archive drops (/c500 drop)
+    │       ├── eventsub_handler.go # Twitch EventSub webhook (signature, replay + duplicate checks)
+    │       ├── guestbook_handler.go # Guestbook entries + moderation queue
+    │       ├── guild_handler.go   # Guild subscription registry + post confirmations
+    │       ├── production_handler.go # Order timeline/page (buyer), advance production + progress updates (seller), mute updates
+    │       ├── profile_handler.go # Live profile, revision history, rollback, previews
+    │       ├── profile_asset_handler.go # Upload/list/delete profile assets
+    │       ├── slug_handler.go    # Claim/rename a username, resolve a slug
+    │       ├── twitch_handler.go  # Start/unlink Twitch link + public OAuth callback
+    │       ├── waitlist_handler.go # Join a drop's waitlist (bot's "Join Waitlist" button)
+    │       └── webhook_handler.go # Handles incoming Stripe events (payment completed, checkout expired)
     │
     └── integrations/       # Clients for external APIs
-        └── stripe/
-            └── client.go   # Wrapper around the official Stripe Go SDK
+        ├── stripe/
+        │   └── client.go   # Wrapper around the official Stripe Go SDK
+        ├── tracking/
+        │   └── fake.go     # Local fake carrier (TRACKING_PROVIDER=fake): simulated scans up to delivery
+        └── twitch/
+            ├── client.go   # Twitch OAuth code exchange + Helix users/videos (base URLs configurable)
+            └── eventsub.go # EventSub signatures + stream.online/offline subscriptions
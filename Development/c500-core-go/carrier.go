@@ -0,0 +1,269 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// =================================================================
+// Carrier Registry
+// The carriers sellers can ship with, and what their tracking numbers look
+// like. Validating the format and check digit catches typos and made-up
+// numbers before they're used to release escrow. Adding a carrier is a new
+// entry here (and in the tracking client).
+// =================================================================
+
+// ErrInvalidTracking is wrapped with the reason a tracking number was refused.
+var ErrInvalidTracking = errors.New("invalid tracking number")
+
+// Carrier codes stored on orders.
+const (
+	CarrierUSPS       = "usps"
+	CarrierUPS        = "ups"
+	CarrierFedEx      = "fedex"
+	CarrierDHL        = "dhl"
+	CarrierCanadaPost = "canada_post"
+	CarrierRoyalMail  = "royal_mail"
+)
+
+// Carrier is one supported shipping carrier.
+type Carrier struct {
+	Code string
+	Name string
+	// Aliases are other spellings sellers type, compared after normalizing.
+	Aliases []string
+	// trackingURL is the public tracking page, with %s for the number.
+	trackingURL string
+	// formats are the tracking number shapes the carrier issues.
+	formats []trackingFormat
+}
+
+// trackingFormat is one tracking number shape and its check digit rule.
+type trackingFormat struct {
+	name  string
+	match func(n string) bool
+	check func(n string) bool
+}
+
+var carriers = []Carrier{
+	{
+		Code:        CarrierUSPS,
+		Name:        "USPS",
+		Aliases:     []string{"us postal service", "united states postal service"},
+		trackingURL: "https://tools.usps.com/go/TrackConfirmAction?tLabels=%s",
+		formats: []trackingFormat{
+			{"IMpb (20-22 digits)", digitsOf(20, 22), mod10Check},
+			{"international (e.g. EA123456785US)", s10Of("US"), s10Check},
+		},
+	},
+	{
+		Code:        CarrierUPS,
+		Name:        "UPS",
+		Aliases:     []string{"united parcel service"},
+		trackingURL: "https://www.ups.com/track?tracknum=%s",
+		formats: []trackingFormat{
+			{"1Z + 16 characters", upsMatch, upsCheck},
+		},
+	},
+	{
+		Code:        CarrierFedEx,
+		Name:        "FedEx",
+		Aliases:     []string{"fed ex", "federal express"},
+		trackingURL: "https://www.fedex.com/fedextrack/?trknbr=%s",
+		formats: []trackingFormat{
+			{"Express (12 digits)", digitsOf(12, 12), fedexExpressCheck},
+			{"Ground (15 digits)", digitsOf(15, 15), mod10Check},
+		},
+	},
+	{
+		Code:        CarrierDHL,
+		Name:        "DHL Express",
+		Aliases:     []string{"dhl express"},
+		trackingURL: "https://www.dhl.com/global-en/home/tracking.html?tracking-id=%s",
+		formats: []trackingFormat{
+			{"waybill (10 digits)", digitsOf(10, 10), mod7Check},
+		},
+	},
+	{
+		Code:        CarrierCanadaPost,
+		Name:        "Canada Post",
+		Aliases:     []string{"canadapost", "postes canada"},
+		trackingURL: "https://www.canadapost-postescanada.ca/track-reperage/en#/search?searchFor=%s",
+		formats: []trackingFormat{
+			{"domestic (16 digits)", digitsOf(16, 16), mod10Check},
+			{"international (e.g. EA123456785CA)", s10Of("CA"), s10Check},
+		},
+	},
+	{
+		Code:        CarrierRoyalMail,
+		Name:        "Royal Mail",
+		Aliases:     []string{"royalmail", "parcelforce"},
+		trackingURL: "https://www.royalmail.com/track-your-item#/tracking-results/%s",
+		formats: []trackingFormat{
+			{"tracked (e.g. AB123456785GB)", s10Of("GB"), s10Check},
+		},
+	},
+}
+
+// Carriers lists every supported carrier, for help text and pickers.
+func Carriers() []Carrier {
+	return carriers
+}
+
+// LookupCarrier finds a carrier by code, name or alias ("UPS", "Canada Post", "canada_post").
+func LookupCarrier(s string) (*Carrier, bool) {
+	key := normalizeCarrierName(s)
+	for i := range carriers {
+		c := &carriers[i]
+		if key == normalizeCarrierName(c.Code) || key == normalizeCarrierName(c.Name) {
+			return c, true
+		}
+		for _, alias := range c.Aliases {
+			if key == normalizeCarrierName(alias) {
+				return c, true
+			}
+		}
+	}
+	return nil, false
+}
+
+// NormalizeTrackingNumber drops the spaces and dashes people copy from labels.
+func NormalizeTrackingNumber(s string) string {
+	return strings.ToUpper(strings.NewReplacer(" ", "", "-", "", "\t", "").Replace(strings.TrimSpace(s)))
+}
+
+// ValidateTracking checks the number is one the carrier could have issued,
+// including its check digit, and returns it normalized.
+func (c *Carrier) ValidateTracking(number string) (string, error) {
+	n := NormalizeTrackingNumber(number)
+	var shapes []string
+	for _, f := range c.formats {
+		if !f.match(n) {
+			shapes = append(shapes, f.name)
+			continue
+		}
+		if !f.check(n) {
+			return "", fmt.Errorf("%w: the check digit doesn't match, is there a typo?", ErrInvalidTracking)
+		}
+		return n, nil
+	}
+	return "", fmt.Errorf("%w: %s numbers look like %s", ErrInvalidTracking, c.Name, strings.Join(shapes, " or "))
+}
+
+// TrackingURL is the carrier's public tracking page for the number.
+func (c *Carrier) TrackingURL(number string) string {
+	return fmt.Sprintf(c.trackingURL, number)
+}
+
+func normalizeCarrierName(s string) string {
+	return strings.NewReplacer(" ", "", "_", "", "-", "", ".", "").Replace(strings.ToLower(strings.TrimSpace(s)))
+}
+
+// ==========================================
+// Formats and Check Digits
+// ==========================================
+
+func isDigits(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return s != ""
+}
+
+func digitsOf(min, max int) func(string) bool {
+	return func(n string) bool {
+		return len(n) >= min && len(n) <= max && isDigits(n)
+	}
+}
+
+// mod10Check is the GS1-style check used by USPS, FedEx Ground and Canada
+// Post: weights 3,1,3,1... from the rightmost payload digit.
+func mod10Check(n string) bool {
+	payload, check := n[:len(n)-1], int(n[len(n)-1]-'0')
+	sum := 0
+	for i := 0; i < len(payload); i++ {
+		d := int(payload[len(payload)-1-i] - '0')
+		if i%2 == 0 {
+			d *= 3
+		}
+		sum += d
+	}
+	return (10-sum%10)%10 == check
+}
+
+// fedexExpressCheck: weights 1,3,7 from the rightmost of the first 11 digits, mod 11, mod 10.
+func fedexExpressCheck(n string) bool {
+	weights := []int{1, 3, 7}
+	sum := 0
+	for i := 0; i < 11; i++ {
+		sum += int(n[10-i]-'0') * weights[i%3]
+	}
+	return sum%11%10 == int(n[11]-'0')
+}
+
+// mod7Check: DHL Express waybills end in the first nine digits mod 7.
+func mod7Check(n string) bool {
+	rem := 0
+	for i := 0; i < 9; i++ {
+		rem = (rem*10 + int(n[i]-'0')) % 7
+	}
+	return rem == int(n[9]-'0')
+}
+
+// s10Of matches the UPU S10 international format: 2 letters, 8 digits,
+// a check digit and the issuing country.
+func s10Of(country string) func(string) bool {
+	return func(n string) bool {
+		return len(n) == 13 && n[11:] == country && isDigits(n[2:11]) &&
+			n[0] >= 'A' && n[0] <= 'Z' && n[1] >= 'A' && n[1] <= 'Z'
+	}
+}
+
+// s10Check: weights 8,6,4,2,3,5,9,7; 11 - sum mod 11, with 10 -> 0 and 11 -> 5.
+func s10Check(n string) bool {
+	weights := []int{8, 6, 4, 2, 3, 5, 9, 7}
+	sum := 0
+	for i, w := range weights {
+		sum += int(n[2+i]-'0') * w
+	}
+	check := 11 - sum%11
+	switch check {
+	case 10:
+		check = 0
+	case 11:
+		check = 5
+	}
+	return check == int(n[10]-'0')
+}
+
+func upsMatch(n string) bool {
+	if len(n) != 18 || !strings.HasPrefix(n, "1Z") {
+		return false
+	}
+	for _, r := range n[2:] {
+		if !(r >= '0' && r <= '9') && !(r >= 'A' && r <= 'Z') {
+			return false
+		}
+	}
+	return isDigits(n[17:])
+}
+
+// upsCheck: letters count as (ASCII - 63) mod 10; odd positions after "1Z"
+// count once, even positions twice.
+func upsCheck(n string) bool {
+	sum := 0
+	for i, r := range n[2:17] {
+		d := int(r - '0')
+		if r >= 'A' && r <= 'Z' {
+			d = (int(r) - 63) % 10
+		}
+		if i%2 == 1 {
+			d *= 2
+		}
+		sum += d
+	}
+	return (10-sum%10)%10 == int(n[17]-'0')
+}
@@ -74,6 +74,9 @@ func (h *CheckoutHandler) CreateSession(c *gin.Context) {
 		case errors.Is(err, service.ErrDropNotFound):
 			// The drop ID was invalid. Return HTTP 404.
 			c.JSON(http.StatusNotFound, gin.H{"error": "Drop not found"})
+		case errors.Is(err, service.ErrDropNotYetLive):
+			// The drop was announced but its countdown hasn't finished.
+			c.JSON(http.StatusConflict, gin.H{"error": "Drop is not live yet"})
 		case errors.Is(err, service.ErrDropNotAvailable):
 			// The drop is already sold or pending. Return HTTP 409 Conflict.
 			// This triggers the "Too late!" message in the Python bot.
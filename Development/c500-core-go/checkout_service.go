@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"time"
 
 	"c500-core-go/internal/domain"
 )
@@ -12,6 +13,7 @@ import (
 var (
 	ErrDropNotFound     = errors.New("drop not found")
 	ErrDropNotAvailable = errors.New("drop is not available for purchase")
+	ErrDropNotYetLive   = errors.New("drop is scheduled and not live yet")
 	ErrStripeFailure    = errors.New("upstream stripe api failure")
 )
 
@@ -31,7 +33,14 @@ type StripeIntegration interface {
 	CreateCheckoutSession(ctx context.Context, drop *domain.Drop, buyerDiscordID string) (string, string, error)
 }
 
-// CheckoutService is the concrete implementation holding business logic.
+// CheckoutService defines the methods the checkout and webhook handlers use.
+type CheckoutService interface {
+	CreateCheckoutSession(ctx context.Context, dropID, buyerDiscordID string) (string, error)
+	ProcessSuccessfulPayment(ctx context.Context, dropID, buyerDiscordID, stripePaymentIntentID string) error
+	ReleaseCheckout(ctx context.Context, dropID string) error
+}
+
+// checkoutService is the concrete implementation holding business logic.
 type checkoutService struct {
 	dropRepo DropRepository
 	stripe   StripeIntegration
@@ -62,7 +71,12 @@ func (s *checkoutService) CreateCheckoutSession(ctx context.Context, dropID, buy
 
 	// 2. CRITICAL BUSINESS RULE: Is it actually available?
 	// This prevents race conditions where two people click buy at the exact same second.
-	if drop.Status != domain.StatusAvailable {
+	// Scheduled drops are rejected until their go-live time, even if someone
+	// guessed the ID from the announcement and calls the API directly.
+	if drop.Status == domain.StatusScheduled && !drop.IsLive(time.Now().UTC()) {
+		return "", ErrDropNotYetLive
+	}
+	if !drop.IsLive(time.Now().UTC()) {
 		return "", ErrDropNotAvailable
 	}
 
@@ -94,3 +108,21 @@ func (s *checkoutService) CreateCheckoutSession(ctx context.Context, dropID, buy
 	// 5. Return the URL to be sent to the user.
 	return checkoutURL, nil
 }
+
+// ReleaseCheckout is called by the Webhook Handler when a Stripe session
+// expires unpaid: the drop stops being reserved and can be bought again.
+func (s *checkoutService) ReleaseCheckout(ctx context.Context, dropID string) error {
+	drop, err := s.dropRepo.GetDropByID(ctx, dropID)
+	if err != nil {
+		return fmt.Errorf("failed to fetch drop: %w", ErrDropNotFound)
+	}
+
+	// Only a reservation is released. A sold or withdrawn drop stays as it is.
+	if drop.Status != domain.StatusPending {
+		return nil
+	}
+	if err := s.dropRepo.UpdateDropStatus(ctx, dropID, domain.StatusAvailable); err != nil {
+		return fmt.Errorf("failed to release drop: %w", err)
+	}
+	return nil
+}
@@ -68,6 +68,7 @@ func (s *checkoutService) ProcessSuccessfulPayment(ctx context.Context, dropID,
 		drop.SellerDiscordID,
 		stripePaymentIntentID,
 		drop.PriceInCents,
+		drop.Type,
 	)
 
 	// 3. CRITICAL DB UPDATES.
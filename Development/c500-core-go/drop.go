@@ -1,6 +1,9 @@
 package domain
 
-import "time"
+import (
+	"encoding/json"
+	"time"
+)
 
 // DropType Enum to differentiate sales models
 type DropType string
@@ -10,35 +13,125 @@ const (
 	DropTypeRTS        DropType = "ready_to_ship"
 )
 
-// DropStatus Enum for lifecycle state
+// DropStatus defines the lifecycle stages of a listing.
 type DropStatus string
 
 const (
-	DropStatusAvailable DropStatus = "available"
-	DropStatusPending   DropStatus = "pending_payment"
-	DropStatusSold      DropStatus = "sold"
+	StatusDraft     DropStatus = "draft"     // Seller is still editing
+	StatusScheduled DropStatus = "scheduled" // Announced, goes live at GoLiveAt
+	StatusAvailable DropStatus = "available" // Live in the shop
+	StatusPending   DropStatus = "pending"   // Buyer is in checkout flow (locked)
+	StatusSold      DropStatus = "sold"      // Transaction complete
+	StatusArchived  DropStatus = "archived"  // Withdrawn by the seller, hidden everywhere
 )
 
 // Drop represents a single listing in the marketplace.
-// The struct tags (e.g., `json:"title"`) define how it looks in API calls
-// and how it's stored in Firestore.
+// This is the ONE canonical shape of a document in the "drops" collection.
+// Older documents written before schema_version existed (float "price",
+// single "image_url", "pending_payment" status) are rewritten in place by
+// the migration runner (see internal/migrations).
 type Drop struct {
-	ID              string                 `json:"id" firestore:"id"` // Firestore Document ID
-	SellerDiscordID string                 `json:"seller_discord_id" firestore:"seller_discord_id"`
-	Title           string                 `json:"title" firestore:"title" binding:"required"`
-	Price           float64                `json:"price" firestore:"price" binding:"required,gt=0"`
-	ImageURL        string                 `json:"image_url" firestore:"image_url" binding:"required,url"`
-	Description     string                 `json:"description" firestore:"description"`
-	Type            DropType               `json:"type" firestore:"type"`
-	Status          DropStatus             `json:"status" firestore:"status"`
-	StructuredData  map[string]interface{} `json:"structured_data" firestore:"structured_data"`
-	CreatedAt       time.Time              `json:"created_at" firestore:"created_at"`
+	ID string `json:"id" firestore:"id"` // Firestore Document ID
+
+	// SchemaVersion records which shape this document was written with.
+	// New documents are always stamped with DropSchemaVersion.
+	SchemaVersion int `json:"schema_version" firestore:"schema_version"`
+
+	// SellerDiscordID links this drop back to the user who created it.
+	// This is a foreign key reference to the 'users' collection.
+	SellerDiscordID string `json:"seller_discord_id" firestore:"seller_discord_id"`
+
+	Title       string `json:"title" firestore:"title" binding:"required,min=5"`
+	Description string `json:"description" firestore:"description"`
+
+	// PriceInCents is stored as an integer to avoid floating point math errors.
+	// e.g., $450.00 is stored as 45000.
+	PriceInCents int64 `json:"price_in_cents" firestore:"price_in_cents" binding:"required,gt=0"`
+
+	Type   DropType   `json:"type" firestore:"type"`
+	Status DropStatus `json:"status" firestore:"status"`
+
+	// ImageAssetIDs reference photos uploaded through POST /api/v1/assets/images.
+	// We host them ourselves, so they never expire like pasted Discord CDN links.
+	ImageAssetIDs []string `json:"image_asset_ids,omitempty" firestore:"image_asset_ids,omitempty"`
+
+	// ImageURLs are the embed-size URLs resolved from ImageAssetIDs when the drop is saved.
+	// The first entry is used as the main image in embeds and on Stripe.
+	ImageURLs []string `json:"image_urls" firestore:"image_urls"`
+
+	// Spec is the typed, validated spec buyers filter on (layout, switches, ...).
+	// It is nil for drops created before typed specs existed.
+	Spec *DropSpec `json:"spec,omitempty" firestore:"spec,omitempty"`
+
+	// StructuredData holds any free-form extras that don't fit the typed Spec
+	// (e.g. "foam": "PE + case foam"). Nothing filters on this.
+	StructuredData map[string]interface{} `json:"structured_data" firestore:"structured_data"`
+
+	// GoLiveAt is when a StatusScheduled drop becomes available.
+	// The scheduler flips the status; until then checkout rejects it.
+	GoLiveAt *time.Time `json:"go_live_at,omitempty" firestore:"go_live_at,omitempty"`
+
+	// OneHourNoticeAt records when the "dropping in 1 hour" announcement was sent,
+	// so it is only ever sent once even with several Core instances running.
+	OneHourNoticeAt *time.Time `json:"-" firestore:"one_hour_notice_at,omitempty"`
+
+	CreatedAt time.Time `json:"created_at" firestore:"created_at"`
+	UpdatedAt time.Time `json:"updated_at" firestore:"updated_at"`
+}
+
+// IsLive reports whether buyers may check out this drop right now.
+// A scheduled drop counts as live once its time has passed, even if the
+// scheduler hasn't flipped its status yet.
+func (d *Drop) IsLive(now time.Time) bool {
+	switch d.Status {
+	case StatusAvailable:
+		return true
+	case StatusScheduled:
+		return d.GoLiveAt != nil && !now.Before(*d.GoLiveAt)
+	default:
+		return false
+	}
 }
 
 // CreateDropRequest defines the exact JSON payload the Python Bot must send
 type CreateDropRequest struct {
-	SellerDiscordID string   `json:"seller_discord_id" binding:"required"`
-	Title           string   `json:"title" binding:"required,max=100"`
-	Price           float64  `json:"price" binding:"required,gt=0"`
-	// ... other fields ...
+	SellerDiscordID string    `json:"seller_discord_id" binding:"required"`
+	Title           string    `json:"title" binding:"required,max=100"`
+	PriceInCents    int64     `json:"price_in_cents" binding:"required,gt=0"`
+	Type            DropType  `json:"type" binding:"required,oneof=commission ready_to_ship"`
+	Description     string    `json:"description"`
+	ImageAssetIDs   []string  `json:"image_asset_ids" binding:"required,min=1,max=8"`
+	Spec            *DropSpec `json:"spec" binding:"required"`
+	// GoLiveAt is optional. If set, the drop is announced now and goes live later.
+	GoLiveAt *time.Time `json:"go_live_at"`
+}
+
+// NewDrop is a helper to create a new, ready-to-save drop.
+// The id must be unique: it's the Firestore document ID.
+func NewDrop(id, sellerID, title string, priceCents int64, dropType DropType) *Drop {
+	now := time.Now().UTC()
+	return &Drop{
+		ID:              id,
+		SchemaVersion:   DropSchemaVersion,
+		SellerDiscordID: sellerID,
+		Title:           title,
+		PriceInCents:    priceCents,
+		Type:            dropType,
+		Status:          StatusDraft, // Start as draft by default
+		CreatedAt:       now,
+		UpdatedAt:       now,
+	}
+}
+
+// MarshalJSON adds the pre-rendered "spec_display" lines to every API response,
+// so the Discord bot and the web frontend never re-implement spec formatting.
+func (d Drop) MarshalJSON() ([]byte, error) {
+	type dropAlias Drop // Alias drops the method set, avoiding infinite recursion.
+	return json.Marshal(struct {
+		dropAlias
+		SpecDisplay []SpecField `json:"spec_display,omitempty"`
+	}{
+		dropAlias:   dropAlias(d),
+		SpecDisplay: d.Spec.DisplayFields(),
+	})
 }
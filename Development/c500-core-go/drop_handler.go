@@ -1,8 +1,11 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
+
 	"github.com/gin-gonic/gin"
+
 	"c500-core-go/internal/domain"
 	"c500-core-go/internal/service"
 )
@@ -16,7 +19,15 @@ func NewDropHandler(ds service.DropService) *DropHandler {
 	return &DropHandler{dropService: ds}
 }
 
-// CreateDrop handles POST /api/internal/drops/create
+// RegisterRoutes connects the HTTP URLs to the handler functions.
+// This is called in main.go, on the internal group: both routes trust the
+// seller ID in the body.
+func (h *DropHandler) RegisterRoutes(router *gin.RouterGroup) {
+	router.POST("/drops", h.CreateDrop)
+	router.POST("/drops/:dropID/archive", h.ArchiveDrop)
+}
+
+// CreateDrop handles POST /api/v1/drops (and POST /api/internal/drops/create)
 func (h *DropHandler) CreateDrop(c *gin.Context) {
 	var req domain.CreateDropRequest
 
@@ -30,11 +41,57 @@ func (h *DropHandler) CreateDrop(c *gin.Context) {
 	// (The service layer handles talking to Firestore, adding timestamps, setting initial status, etc.)
 	newDrop, err := h.dropService.CreateNewDrop(c.Request.Context(), req)
 	if err != nil {
-		// In a real app, check error type to decide between 500 vs 400 errors
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create drop"})
+		switch {
+		case errors.Is(err, domain.ErrInvalidSpec):
+			// The spec failed validation (unknown switch, missing layout, ...).
+			// The message is safe to show the seller so they can fix the form.
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case errors.Is(err, service.ErrAssetNotFound), errors.Is(err, service.ErrTooManyImages),
+			errors.Is(err, service.ErrInvalidGoLiveTime):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case errors.Is(err, service.ErrAssetNotOwned):
+			c.JSON(http.StatusForbidden, gin.H{"error": "You can only attach images you uploaded"})
+		case errors.Is(err, service.ErrBuilderNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": "Seller not found"})
+		case errors.Is(err, service.ErrSellerNotOnboarded):
+			c.JSON(http.StatusForbidden, gin.H{"error": "Finish seller setup with /c500 setup before creating drops"})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create drop"})
+		}
 		return
 	}
 
 	// 3. Return success response with the newly created drop data
 	c.JSON(http.StatusCreated, newDrop)
 }
+
+// archiveDropRequest identifies who is asking, so the service can check ownership.
+type archiveDropRequest struct {
+	SellerDiscordID string `json:"seller_discord_id" binding:"required"`
+}
+
+// ArchiveDrop handles POST /api/v1/drops/:dropID/archive
+func (h *DropHandler) ArchiveDrop(c *gin.Context) {
+	var req archiveDropRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	err := h.dropService.ArchiveDrop(c.Request.Context(), c.Param("dropID"), req.SellerDiscordID)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrDropNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": "Drop not found"})
+		case errors.Is(err, service.ErrDropNotOwned):
+			c.JSON(http.StatusForbidden, gin.H{"error": "You can only archive your own drops"})
+		case errors.Is(err, service.ErrDropLocked):
+			c.JSON(http.StatusConflict, gin.H{"error": "This drop is in checkout or already sold"})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to archive drop"})
+		}
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,127 @@
+package scheduler
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"c500-core-go/internal/domain"
+	"c500-core-go/internal/events"
+)
+
+// noticeLead is how far ahead of go-live the countdown announcement goes out.
+const noticeLead = time.Hour
+
+// DropAnnouncer fans an announcement out to every subscribed guild.
+// Implemented by service.FanoutService.
+type DropAnnouncer interface {
+	AnnounceDrop(ctx context.Context, drop *domain.Drop, eventType string) error
+}
+
+// ScheduledDropRepository defines the DB operations the scheduler needs.
+// Implemented in internal/database/firestore.go
+type ScheduledDropRepository interface {
+	// ListScheduledDropsBefore returns scheduled drops whose go_live_at is <= cutoff.
+	ListScheduledDropsBefore(ctx context.Context, cutoff time.Time) ([]domain.Drop, error)
+	// PublishScheduledDrop flips scheduled -> available. It returns false if the drop
+	// was no longer scheduled (another instance got there first, or it was cancelled).
+	PublishScheduledDrop(ctx context.Context, dropID string) (bool, error)
+	// MarkOneHourNoticeSent stamps one_hour_notice_at. It returns false if it was already set.
+	MarkOneHourNoticeSent(ctx context.Context, dropID string) (bool, error)
+}
+
+// DropScheduler publishes scheduled drops at their go-live time and emits
+// countdown events for the Discord bots to announce.
+//
+// Every Core instance runs one. That's safe because each state change is a
+// Firestore transaction that only one instance can win, and only the winner
+// publishes the event, so Discord never gets a duplicate announcement.
+type DropScheduler struct {
+	repo      ScheduledDropRepository
+	announcer DropAnnouncer
+	interval  time.Duration
+}
+
+// NewDropScheduler constructor used in main.go.
+func NewDropScheduler(repo ScheduledDropRepository, announcer DropAnnouncer, interval time.Duration) *DropScheduler {
+	return &DropScheduler{
+		repo:      repo,
+		announcer: announcer,
+		interval:  interval,
+	}
+}
+
+// Run ticks until ctx is cancelled. Start it in a goroutine from main.go.
+func (s *DropScheduler) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	s.tick(ctx) // Don't wait a full interval after a deploy.
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.tick(ctx)
+		}
+	}
+}
+
+// tick handles every drop that is due for either announcement.
+func (s *DropScheduler) tick(ctx context.Context) {
+	now := time.Now().UTC()
+
+	// 1. Everything going live within the next hour (or already overdue).
+	drops, err := s.repo.ListScheduledDropsBefore(ctx, now.Add(noticeLead))
+	if err != nil {
+		log.Printf("drop scheduler: failed to list scheduled drops: %v", err)
+		return
+	}
+
+	for _, drop := range drops {
+		if drop.GoLiveAt == nil {
+			continue
+		}
+
+		// 2. Go-live time reached: publish it.
+		if !now.Before(*drop.GoLiveAt) {
+			s.goLive(ctx, drop)
+			continue
+		}
+
+		// 3. Within the hour: send the countdown notice once.
+		if drop.OneHourNoticeAt == nil {
+			s.sendNotice(ctx, drop)
+		}
+	}
+}
+
+func (s *DropScheduler) goLive(ctx context.Context, drop domain.Drop) {
+	won, err := s.repo.PublishScheduledDrop(ctx, drop.ID)
+	if err != nil {
+		log.Printf("drop scheduler: failed to publish drop %s: %v", drop.ID, err)
+		return
+	}
+	if !won {
+		return // Another instance already did it.
+	}
+	drop.Status = domain.StatusAvailable
+	if err := s.announcer.AnnounceDrop(ctx, &drop, events.DropLive); err != nil {
+		// The drop IS live; only the announcement is lost. Log loudly.
+		log.Printf("drop scheduler: drop %s is live but event publish failed: %v", drop.ID, err)
+	}
+}
+
+func (s *DropScheduler) sendNotice(ctx context.Context, drop domain.Drop) {
+	won, err := s.repo.MarkOneHourNoticeSent(ctx, drop.ID)
+	if err != nil {
+		log.Printf("drop scheduler: failed to mark notice for drop %s: %v", drop.ID, err)
+		return
+	}
+	if !won {
+		return
+	}
+	if err := s.announcer.AnnounceDrop(ctx, &drop, events.DropGoingLiveSoon); err != nil {
+		log.Printf("drop scheduler: notice event publish failed for drop %s: %v", drop.ID, err)
+	}
+}
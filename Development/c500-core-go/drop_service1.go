@@ -0,0 +1,166 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"c500-core-go/internal/domain"
+	"c500-core-go/internal/events"
+)
+
+var (
+	// ErrSellerNotOnboarded means the builder exists but CanSell() is false.
+	ErrSellerNotOnboarded = errors.New("seller is not verified or has not finished stripe onboarding")
+	// ErrInvalidGoLiveTime means the requested schedule is in the past or too far ahead.
+	ErrInvalidGoLiveTime = errors.New("go-live time must be between 5 minutes and 30 days from now")
+	// ErrDropNotOwned means someone other than the seller tried to change a drop.
+	ErrDropNotOwned = errors.New("drop belongs to another seller")
+	// ErrDropLocked means the drop is in checkout or already sold and can't be withdrawn.
+	ErrDropLocked = errors.New("drop is in checkout or already sold")
+)
+
+// Limits for scheduled drops.
+const (
+	minScheduleLead = 5 * time.Minute
+	maxScheduleLead = 30 * 24 * time.Hour
+)
+
+// DropCreator is the narrow DB operation needed to save a brand new listing.
+// Implemented in internal/database/firestore.go (CreateDrop).
+type DropCreator interface {
+	CreateDrop(ctx context.Context, drop *domain.Drop) error
+}
+
+// DropService defines the publicly available methods handlers use for drops.
+type DropService interface {
+	CreateNewDrop(ctx context.Context, req domain.CreateDropRequest) (*domain.Drop, error)
+	ArchiveDrop(ctx context.Context, dropID, sellerDiscordID string) error
+}
+
+// dropService is the concrete implementation holding business logic.
+type dropService struct {
+	dropRepo    DropCreator
+	statusRepo  DropRepository // Wrapped with NewStatusSyncingDropRepository in main.go
+	builderRepo BuilderRepository
+	assets      AssetService
+	fanout      FanoutService
+}
+
+// NewDropService constructor used in main.go.
+func NewDropService(dr DropCreator, sr DropRepository, br BuilderRepository, as AssetService, fs FanoutService) *dropService {
+	return &dropService{
+		dropRepo:    dr,
+		statusRepo:  sr,
+		builderRepo: br,
+		assets:      as,
+		fanout:      fs,
+	}
+}
+
+// ==========================================
+// Business Logic
+// ==========================================
+
+// CreateNewDrop validates the request, canonicalizes the spec and saves the listing.
+func (s *dropService) CreateNewDrop(ctx context.Context, req domain.CreateDropRequest) (*domain.Drop, error) {
+	// 1. Only onboarded sellers can list items.
+	seller, err := s.builderRepo.GetByID(ctx, req.SellerDiscordID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch seller: %w", err)
+	}
+	if !seller.CanSell() {
+		return nil, ErrSellerNotOnboarded
+	}
+
+	// 2. Validate the typed spec and fold every alias into its canonical spelling
+	// ("Gat yellows" -> "Gateron Yellow") so search and filters see one vocabulary.
+	// Errors wrap domain.ErrInvalidSpec, which the handler maps to HTTP 400.
+	if err := req.Spec.Normalize(); err != nil {
+		return nil, err
+	}
+
+	// 3. Resolve the uploaded photos. This also checks the seller owns every asset.
+	imageURLs, err := s.assets.ResolveForDrop(ctx, req.SellerDiscordID, req.ImageAssetIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	// 4. Build the domain object and attach the validated fields.
+	dropID, err := newDropID()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate drop id: %w", err)
+	}
+	drop := domain.NewDrop(dropID, req.SellerDiscordID, req.Title, req.PriceInCents, req.Type)
+	drop.Description = req.Description
+	drop.ImageAssetIDs = req.ImageAssetIDs
+	drop.ImageURLs = imageURLs
+	drop.Spec = req.Spec
+
+	// Drops go live immediately unless the builder is hyping a future drop.
+	drop.Status = domain.StatusAvailable
+	if req.GoLiveAt != nil {
+		goLive := req.GoLiveAt.UTC()
+		lead := time.Until(goLive)
+		if lead < minScheduleLead || lead > maxScheduleLead {
+			return nil, ErrInvalidGoLiveTime
+		}
+		drop.Status = domain.StatusScheduled
+		drop.GoLiveAt = &goLive
+	}
+
+	// 5. Persist it.
+	if err := s.dropRepo.CreateDrop(ctx, drop); err != nil {
+		return nil, fmt.Errorf("failed to save drop: %w", err)
+	}
+
+	// 6. Fan the new listing out to every subscribed partner server.
+	// Scheduled drops are announced by the scheduler when they go live instead.
+	if drop.Status == domain.StatusAvailable {
+		if err := s.fanout.AnnounceDrop(ctx, drop, events.DropPublished); err != nil {
+			// The drop is saved and visible on the web shop; only the Discord posts failed.
+			// Don't fail the seller's request over it.
+			log.Printf("drop %s created but fan-out failed: %v", drop.ID, err)
+		}
+	}
+
+	return drop, nil
+}
+
+// ArchiveDrop withdraws a listing. Every posted embed is updated to match.
+func (s *dropService) ArchiveDrop(ctx context.Context, dropID, sellerDiscordID string) error {
+	drop, err := s.statusRepo.GetDropByID(ctx, dropID)
+	if err != nil {
+		return err
+	}
+	if drop.SellerDiscordID != sellerDiscordID {
+		return ErrDropNotOwned
+	}
+
+	switch drop.Status {
+	case domain.StatusArchived:
+		return nil // Already done.
+	case domain.StatusPending, domain.StatusSold:
+		// Someone is paying (or has paid) for it; pulling it now would strand the buyer.
+		return ErrDropLocked
+	}
+
+	if err := s.statusRepo.UpdateDropStatus(ctx, dropID, domain.StatusArchived); err != nil {
+		return fmt.Errorf("failed to archive drop: %w", err)
+	}
+	return nil
+}
+
+// newDropID returns a random document ID for a new drop. It's also used in
+// search entries, Discord post records and the buy buttons' custom IDs.
+func newDropID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,108 @@
+package events
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"cloud.google.com/go/firestore"
+)
+
+// Event types the bots subscribe to. Keep these in sync with the Discord bots.
+const (
+	DropPublished     = "drop.published"       // New drop, live immediately
+	DropGoingLiveSoon = "drop.going_live_soon" // "Dropping in 1 hour!"
+	DropLive          = "drop.live"            // "Now live!" (a scheduled drop went live)
+	DropStatusChanged = "drop.status_changed"  // Edit every posted embed (Reserved / SOLD / back in stock)
+	DropWaitlist      = "drop.waitlist"        // DM the waitlist: the drop they wanted can be bought now
+
+	BuildStreamOnline  = "build.stream_online"  // DM buyers: "<builder> is building your board live right now!"
+	BuildStreamOffline = "build.stream_offline" // DM buyers: the stream ended, VOD link to follow
+
+	OrderProgressUpdate = "order.progress_update" // DM the buyer the seller's update (not sent if they muted them)
+)
+
+// eventsCollection is the outbox: every event is written here once and bots
+// read it in ID order. Firestore is our only shared infrastructure, so this
+// avoids running a separate message broker.
+const eventsCollection = "events"
+
+// Event is one thing that happened in the Core that a bot may want to announce.
+type Event struct {
+	// ID sorts by creation time (zero-padded nanoseconds + random suffix),
+	// so "everything after ID X" is a simple ordered query.
+	ID        string                 `json:"id" firestore:"id"`
+	Type      string                 `json:"type" firestore:"type"`
+	Data      map[string]interface{} `json:"data" firestore:"data"`
+	CreatedAt time.Time              `json:"created_at" firestore:"created_at"`
+}
+
+// Publisher is what services depend on. They never know about Firestore.
+type Publisher interface {
+	Publish(ctx context.Context, eventType string, data map[string]interface{}) error
+}
+
+// Outbox is the Firestore-backed Publisher, plus the read side used by the events API.
+type Outbox struct {
+	client *firestore.Client
+}
+
+// NewOutbox constructor.
+func NewOutbox(client *firestore.Client) *Outbox {
+	return &Outbox{client: client}
+}
+
+// Publish appends an event to the outbox.
+func (o *Outbox) Publish(ctx context.Context, eventType string, data map[string]interface{}) error {
+	id, err := newEventID()
+	if err != nil {
+		return fmt.Errorf("failed to generate event id: %w", err)
+	}
+	ev := Event{
+		ID:        id,
+		Type:      eventType,
+		Data:      data,
+		CreatedAt: time.Now().UTC(),
+	}
+	if _, err := o.client.Collection(eventsCollection).Doc(id).Create(ctx, ev); err != nil {
+		return fmt.Errorf("firestore create event error: %w", err)
+	}
+	return nil
+}
+
+// ListAfter returns up to limit events with an ID greater than afterID, oldest first.
+// Bots remember the last ID they processed and pass it back as afterID.
+func (o *Outbox) ListAfter(ctx context.Context, afterID string, limit int) ([]Event, error) {
+	query := o.client.Collection(eventsCollection).
+		OrderBy(firestore.DocumentID, firestore.Asc).
+		Limit(limit)
+	if afterID != "" {
+		query = query.StartAfter(afterID)
+	}
+
+	docs, err := query.Documents(ctx).GetAll()
+	if err != nil {
+		return nil, fmt.Errorf("firestore list events error: %w", err)
+	}
+
+	out := make([]Event, 0, len(docs))
+	for _, doc := range docs {
+		var ev Event
+		if err := doc.DataTo(&ev); err != nil {
+			continue
+		}
+		out = append(out, ev)
+	}
+	return out, nil
+}
+
+// newEventID returns a lexicographically time-ordered ID.
+func newEventID() (string, error) {
+	b := make([]byte, 4)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%020d-%s", time.Now().UTC().UnixNano(), hex.EncodeToString(b)), nil
+}
@@ -0,0 +1,57 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"c500-core-go/internal/events"
+)
+
+// EventLister is the read side of the event outbox.
+type EventLister interface {
+	ListAfter(ctx context.Context, afterID string, limit int) ([]events.Event, error)
+}
+
+// EventsHandler lets the Discord bots poll for things to announce.
+type EventsHandler struct {
+	events EventLister
+}
+
+// NewEventsHandler is the constructor.
+func NewEventsHandler(el EventLister) *EventsHandler {
+	return &EventsHandler{
+		events: el,
+	}
+}
+
+// RegisterRoutes connects the HTTP URLs to the handler functions.
+// This is called in main.go, on the internal group: events carry buyer
+// Discord IDs and signed order links, so only our bots may read them.
+func (h *EventsHandler) RegisterRoutes(router *gin.RouterGroup) {
+	router.GET("/events", h.ListEvents)
+}
+
+// ListEvents handles GET /api/v1/events?after=<last event id>&limit=100
+// Bots store the ID of the last event they handled and poll with it every few seconds.
+func (h *EventsHandler) ListEvents(c *gin.Context) {
+	limit := 100
+	if raw := c.Query("limit"); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil || parsed <= 0 || parsed > 500 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "limit must be between 1 and 500"})
+			return
+		}
+		limit = parsed
+	}
+
+	list, err := h.events.ListAfter(c.Request.Context(), c.Query("after"), limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list events"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"events": list})
+}
@@ -0,0 +1,131 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"c500-core-go/internal/integrations/twitch"
+	"c500-core-go/internal/service"
+)
+
+// maxEventSubBody caps how much of a webhook request we read. Stream events are tiny.
+const maxEventSubBody = 1 << 20
+
+// EventSubHandler receives Twitch EventSub webhooks (stream.online/offline
+// for every linked builder, subscribed in twitch_service.go).
+type EventSubHandler struct {
+	streamService service.StreamService
+	// secret is the same EventSub secret the subscriptions were created with.
+	secret string
+}
+
+// NewEventSubHandler constructor.
+// We pass the secret in here so it's only read from env vars once at startup.
+func NewEventSubHandler(ss service.StreamService, secret string) *EventSubHandler {
+	return &EventSubHandler{
+		streamService: ss,
+		secret:        secret,
+	}
+}
+
+// RegisterRoutes connects the URL to the handler function. Like the Stripe
+// webhook it is NOT under /api/v1.
+// Called in main.go.
+func (h *EventSubHandler) RegisterRoutes(router *gin.RouterGroup) {
+	router.POST("/webhooks/twitch", h.HandleEventSub)
+}
+
+// ==========================================
+// Handler Function
+// ==========================================
+
+// HandleEventSub is the entrypoint for requests coming FROM Twitch's servers.
+// Any non-2xx response makes Twitch retry, and too many failures get the
+// subscription revoked, so only real failures on our side return 5xx.
+func (h *EventSubHandler) HandleEventSub(c *gin.Context) {
+	// 1. Read the raw body; the signature covers the exact bytes.
+	payload, err := io.ReadAll(io.LimitReader(c.Request.Body, maxEventSubBody))
+	if err != nil {
+		c.Status(http.StatusServiceUnavailable)
+		return
+	}
+
+	// 2. Verify it was signed with our secret and isn't a stale replay.
+	if err := twitch.VerifyEventSub(h.secret, c.Request.Header, payload, time.Now()); err != nil {
+		log.Printf("eventsub: rejected webhook: %v", err)
+		c.Status(http.StatusForbidden)
+		return
+	}
+
+	var msg twitch.EventSubMessage
+	if err := json.Unmarshal(payload, &msg); err != nil {
+		c.Status(http.StatusBadRequest)
+		return
+	}
+
+	// 3. Route on the message type.
+	switch c.GetHeader(twitch.HeaderMessageType) {
+	case twitch.MessageVerification:
+		// Twitch confirms we own the callback: echo the challenge as plain text.
+		c.Data(http.StatusOK, "text/plain; charset=utf-8", []byte(msg.Challenge))
+		return
+
+	case twitch.MessageRevocation:
+		// The builder disconnected our app, deleted their account, or we failed too often.
+		log.Printf("eventsub: subscription %s (%s) revoked: %s", msg.Subscription.ID, msg.Subscription.Type, msg.Subscription.Status)
+		c.Status(http.StatusNoContent)
+		return
+
+	case twitch.MessageNotification:
+	default:
+		c.Status(http.StatusNoContent)
+		return
+	}
+
+	// 4. Twitch delivers at least once: drop retries we've already handled.
+	messageID := c.GetHeader(twitch.HeaderMessageID)
+	first, err := h.streamService.FirstDelivery(c.Request.Context(), messageID)
+	if err != nil {
+		log.Printf("eventsub: failed to de-duplicate message %s: %v", messageID, err)
+		c.Status(http.StatusInternalServerError)
+		return
+	}
+	if !first {
+		c.Status(http.StatusNoContent)
+		return
+	}
+
+	var ev twitch.StreamEvent
+	if err := json.Unmarshal(msg.Event, &ev); err != nil {
+		c.Status(http.StatusBadRequest)
+		return
+	}
+
+	switch msg.Subscription.Type {
+	case twitch.StreamOnline:
+		err = h.streamService.StreamOnline(c.Request.Context(), ev.BroadcasterUserID, ev.StartedAt)
+	case twitch.StreamOffline:
+		err = h.streamService.StreamOffline(c.Request.Context(), ev.BroadcasterUserID)
+	}
+
+	if errors.Is(err, service.ErrBuilderNotFound) {
+		// The builder unlinked and the subscription hasn't been deleted yet.
+		log.Printf("eventsub: no builder linked to twitch user %s, ignoring %s", ev.BroadcasterUserID, msg.Subscription.Type)
+		err = nil
+	}
+	if err != nil {
+		log.Printf("eventsub: failed to handle %s for twitch user %s: %v", msg.Subscription.Type, ev.BroadcasterUserID, err)
+		// Let Twitch's retry through.
+		h.streamService.Redeliver(c.Request.Context(), messageID)
+		c.Status(http.StatusInternalServerError)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
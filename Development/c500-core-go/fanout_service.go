@@ -0,0 +1,234 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"c500-core-go/internal/domain"
+	"c500-core-go/internal/events"
+)
+
+var ErrSubscriptionNotFound = errors.New("guild subscription not found")
+
+// GuildRepository defines DB operations for the guild subscription registry
+// and the per-guild drop posts. Implemented in internal/database/firestore.go
+type GuildRepository interface {
+	UpsertSubscription(ctx context.Context, sub *domain.GuildSubscription) error
+	GetSubscription(ctx context.Context, guildID string) (*domain.GuildSubscription, error)
+	DeleteSubscription(ctx context.Context, guildID string) error
+	ListSubscriptions(ctx context.Context) ([]domain.GuildSubscription, error)
+
+	CreateDropPosts(ctx context.Context, posts []domain.DropPost) error
+	RecordDropPostMessage(ctx context.Context, post *domain.DropPost) error
+	ListDropPosts(ctx context.Context, dropID string) ([]domain.DropPost, error)
+}
+
+// FanoutService defines the methods handlers and other services use.
+type FanoutService interface {
+	Subscribe(ctx context.Context, sub domain.GuildSubscription) (*domain.GuildSubscription, error)
+	GetSubscription(ctx context.Context, guildID string) (*domain.GuildSubscription, error)
+	Unsubscribe(ctx context.Context, guildID string) error
+	AnnounceDrop(ctx context.Context, drop *domain.Drop, eventType string) error
+	RecordPost(ctx context.Context, post domain.DropPost) error
+	SyncDropStatus(ctx context.Context, dropID string, status domain.DropStatus) error
+}
+
+// fanoutService is the concrete implementation.
+type fanoutService struct {
+	repo      GuildRepository
+	builders  BuilderRepository
+	publisher events.Publisher
+}
+
+// NewFanoutService constructor used in main.go. builders is used to put the
+// seller's linked Twitch channel on the embeds.
+func NewFanoutService(repo GuildRepository, builders BuilderRepository, publisher events.Publisher) *fanoutService {
+	return &fanoutService{
+		repo:      repo,
+		builders:  builders,
+		publisher: publisher,
+	}
+}
+
+// ==========================================
+// Subscription Registry
+// ==========================================
+
+// Subscribe creates or replaces a guild's subscription.
+func (s *fanoutService) Subscribe(ctx context.Context, sub domain.GuildSubscription) (*domain.GuildSubscription, error) {
+	now := time.Now().UTC()
+	existing, err := s.repo.GetSubscription(ctx, sub.GuildID)
+	switch {
+	case err == nil:
+		sub.CreatedAt = existing.CreatedAt
+	case errors.Is(err, ErrSubscriptionNotFound):
+		sub.CreatedAt = now
+	default:
+		return nil, fmt.Errorf("failed to read subscription: %w", err)
+	}
+	sub.UpdatedAt = now
+
+	if err := s.repo.UpsertSubscription(ctx, &sub); err != nil {
+		return nil, fmt.Errorf("failed to save subscription: %w", err)
+	}
+	return &sub, nil
+}
+
+// GetSubscription returns a guild's subscription.
+func (s *fanoutService) GetSubscription(ctx context.Context, guildID string) (*domain.GuildSubscription, error) {
+	return s.repo.GetSubscription(ctx, guildID)
+}
+
+// Unsubscribe removes a guild from the registry. Existing posts are left alone.
+func (s *fanoutService) Unsubscribe(ctx context.Context, guildID string) error {
+	return s.repo.DeleteSubscription(ctx, guildID)
+}
+
+// ==========================================
+// Fan-out
+// ==========================================
+
+// AnnounceDrop works out which guilds want this drop and emits ONE event listing
+// every target channel. The Go bot consumes the event, posts the embed in each
+// channel and reports each message ID back through RecordPost.
+//
+// eventType is events.DropPublished (new drop, live now), events.DropLive
+// (scheduled drop just went live) or events.DropGoingLiveSoon (countdown).
+func (s *fanoutService) AnnounceDrop(ctx context.Context, drop *domain.Drop, eventType string) error {
+	// 1. Find matching guilds.
+	subs, err := s.repo.ListSubscriptions(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list guild subscriptions: %w", err)
+	}
+
+	var targets []map[string]interface{}
+	var posts []domain.DropPost
+	now := time.Now().UTC()
+	for i := range subs {
+		if !subs[i].Matches(drop) {
+			continue
+		}
+		targets = append(targets, map[string]interface{}{
+			"guild_id":   subs[i].GuildID,
+			"channel_id": subs[i].ChannelID,
+		})
+		posts = append(posts, domain.DropPost{
+			DropID:    drop.ID,
+			GuildID:   subs[i].GuildID,
+			ChannelID: subs[i].ChannelID,
+			CreatedAt: now,
+		})
+	}
+	if len(targets) == 0 {
+		return nil // Nobody subscribed; nothing to do.
+	}
+
+	// 2. Reserve a post record per guild BEFORE the bot posts, so we always know
+	// where a drop was sent even if the bot crashes before confirming.
+	// Countdown notices are throwaway messages and aren't tracked.
+	if eventType != events.DropGoingLiveSoon {
+		if err := s.repo.CreateDropPosts(ctx, posts); err != nil {
+			return fmt.Errorf("failed to create drop posts: %w", err)
+		}
+	}
+
+	// 3. Emit the fan-out event.
+	data := map[string]interface{}{
+		"drop_id":           drop.ID,
+		"title":             drop.Title,
+		"description":       drop.Description,
+		"seller_discord_id": drop.SellerDiscordID,
+		"price_in_cents":    drop.PriceInCents,
+		"type":              string(drop.Type),
+		"image_urls":        drop.ImageURLs,
+		"spec_display":      drop.Spec.DisplayFields(),
+		"go_live_at":        drop.GoLiveAt,
+		"targets":           targets,
+	}
+	// The embed links the builder's stream. A failed lookup only loses the link.
+	if seller, err := s.builders.GetByID(ctx, drop.SellerDiscordID); err != nil {
+		log.Printf("fanout: failed to load seller %s of drop %s: %v", drop.SellerDiscordID, drop.ID, err)
+	} else if seller.Twitch != nil {
+		data["seller_twitch_login"] = seller.Twitch.Login
+	}
+	if err := s.publisher.Publish(ctx, eventType, data); err != nil {
+		return fmt.Errorf("failed to publish fan-out event: %w", err)
+	}
+	return nil
+}
+
+// RecordPost stores the Discord message ID a bot got back after posting.
+// Any bot that posts a drop embed (fan-out or not) should report it here,
+// otherwise the embed can't be kept in sync with the drop's status.
+func (s *fanoutService) RecordPost(ctx context.Context, post domain.DropPost) error {
+	post.PostedAt = time.Now().UTC()
+	return s.repo.RecordDropPostMessage(ctx, &post)
+}
+
+// ==========================================
+// Embed Sync
+// ==========================================
+
+// SyncDropStatus asks the bots to edit every message showing this drop so it
+// reflects the new status (button disabled, "Reserved" / "SOLD" stamp...).
+// The edits themselves are done by the Go bot's rate-limited edit worker.
+func (s *fanoutService) SyncDropStatus(ctx context.Context, dropID string, status domain.DropStatus) error {
+	posts, err := s.repo.ListDropPosts(ctx, dropID)
+	if err != nil {
+		return fmt.Errorf("failed to list drop posts: %w", err)
+	}
+	if len(posts) == 0 {
+		return nil // Never posted anywhere.
+	}
+
+	messages := make([]map[string]interface{}, 0, len(posts))
+	for _, p := range posts {
+		messages = append(messages, map[string]interface{}{
+			"guild_id":   p.GuildID,
+			"channel_id": p.ChannelID,
+			"message_id": p.MessageID,
+		})
+	}
+
+	data := map[string]interface{}{
+		"drop_id":  dropID,
+		"status":   string(status),
+		"messages": messages,
+	}
+	if err := s.publisher.Publish(ctx, events.DropStatusChanged, data); err != nil {
+		return fmt.Errorf("failed to publish status change event: %w", err)
+	}
+	return nil
+}
+
+// statusSyncingDropRepository wraps a DropRepository so that EVERY status change,
+// whichever service makes it, is also pushed to the Discord messages.
+type statusSyncingDropRepository struct {
+	DropRepository
+	fanout FanoutService
+}
+
+// NewStatusSyncingDropRepository constructor used in main.go. Give the result
+// to any service that changes drop status instead of the bare Firestore client.
+func NewStatusSyncingDropRepository(repo DropRepository, fanout FanoutService) DropRepository {
+	return &statusSyncingDropRepository{
+		DropRepository: repo,
+		fanout:         fanout,
+	}
+}
+
+// UpdateDropStatus saves the new status, then syncs the embeds.
+func (r *statusSyncingDropRepository) UpdateDropStatus(ctx context.Context, dropID string, newStatus domain.DropStatus) error {
+	if err := r.DropRepository.UpdateDropStatus(ctx, dropID, newStatus); err != nil {
+		return err
+	}
+	if err := r.fanout.SyncDropStatus(ctx, dropID, newStatus); err != nil {
+		// The status IS saved; a stale embed is annoying but checkout still
+		// re-checks the status, so never fail the caller over it.
+		log.Printf("drop %s is now %s but embed sync failed: %v", dropID, newStatus, err)
+	}
+	return nil
+}
@@ -38,6 +38,12 @@ func NewFirestoreClient(ctx context.Context, projectID string) (*FirestoreClient
 	return &FirestoreClient{client: client}, nil
 }
 
+// SDK exposes the underlying Google client for subsystems that need more than
+// the repository methods, e.g. the search index's snapshot listener.
+func (f *FirestoreClient) SDK() *firestore.Client {
+	return f.client
+}
+
 // Close ensures the connection is shut down properly when the app stops.
 func (f *FirestoreClient) Close() error {
 	return f.client.Close()
@@ -106,24 +112,3 @@ func (f *FirestoreClient) UpdateStripeID(ctx context.Context, discordID, stripeA
 	}
 	return nil
 }
-
-// UpdateProfileData handles updating nested data structures.
-func (f *FirestoreClient) UpdateProfileData(ctx context.Context, discordID string, profile domain.ProfileData) error {
-	docRef := f.client.Collection(usersCollection).Doc(discordID)
-
-	// Firestore allows updating nested fields using dot notation in the path.
-	// This replaces the entire "profile_data" map with the new one.
-	updates := []firestore.Update{
-		{Path: "profile_data", Value: profile},
-		{Path: "updated_at", Value: time.Now().UTC()},
-	}
-
-	_, err := docRef.Update(ctx, updates)
-	if err != nil {
-		if status.Code(err) == codes.NotFound {
-			return service.ErrBuilderNotFound
-		}
-		return fmt.Errorf("firestore update profile error: %w", err)
-	}
-	return nil
-}
@@ -0,0 +1,148 @@
+// ... (previous code for users, drops, orders, assets, guilds, profiles, slugs, guestbooks and profile assets remains above)
+
+const (
+	// ...
+	analyticsDailyCollection    = "analytics_daily"    // Keyed by "<builderID>_<day>"
+	analyticsTotalsCollection   = "analytics_totals"   // Keyed by builderID
+	analyticsVisitorsCollection = "analytics_visitors" // TTL policy on expires_at
+	analyticsSaltsCollection    = "analytics_salts"    // TTL policy on expires_at
+)
+
+// =================================================================
+// AnalyticsRepository Implementation
+// These methods fulfill the interface defined in analytics_service.go
+// =================================================================
+
+// IncrementAnalytics adds the counters to the day document and the lifetime
+// totals in one batch. Increment transforms merge with concurrent flushes
+// from other Core instances, so no transaction is needed.
+func (f *FirestoreClient) IncrementAnalytics(ctx context.Context, delta *domain.AnalyticsDay) error {
+	counts := incrementsFor(delta.Counts)
+	day := map[string]interface{}{
+		"builder_id": delta.BuilderID,
+		"day":        delta.Day,
+		"counts":     counts,
+		"updated_at": time.Now().UTC(),
+	}
+	if len(delta.Drops) > 0 {
+		drops := make(map[string]interface{}, len(delta.Drops))
+		for dropID, c := range delta.Drops {
+			drops[dropID] = incrementsFor(c)
+		}
+		day["drops"] = drops
+	}
+
+	batch := f.client.Batch()
+	batch.Set(f.client.Collection(analyticsDailyCollection).Doc(delta.BuilderID+"_"+delta.Day), day, firestore.MergeAll)
+	batch.Set(f.client.Collection(analyticsTotalsCollection).Doc(delta.BuilderID), map[string]interface{}{
+		"counts": counts,
+	}, firestore.MergeAll)
+	if _, err := batch.Commit(ctx); err != nil {
+		return fmt.Errorf("firestore increment analytics error: %w", err)
+	}
+	return nil
+}
+
+func incrementsFor(counts map[string]int64) map[string]interface{} {
+	out := make(map[string]interface{}, len(counts))
+	for metric, n := range counts {
+		out[metric] = firestore.Increment(n)
+	}
+	return out
+}
+
+// ListAnalyticsDays returns a builder's days in [from, to], oldest first.
+// Requires a composite index on (builder_id, day).
+func (f *FirestoreClient) ListAnalyticsDays(ctx context.Context, builderID, from, to string) ([]domain.AnalyticsDay, error) {
+	iter := f.client.Collection(analyticsDailyCollection).
+		Where("builder_id", "==", builderID).
+		Where("day", ">=", from).
+		Where("day", "<=", to).
+		OrderBy("day", firestore.Asc).
+		Documents(ctx)
+	defer iter.Stop()
+
+	var days []domain.AnalyticsDay
+	for {
+		doc, err := iter.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("firestore list analytics error: %w", err)
+		}
+
+		var day domain.AnalyticsDay
+		if err := doc.DataTo(&day); err != nil {
+			return nil, fmt.Errorf("failed to map data to analytics day struct: %w", err)
+		}
+		days = append(days, day)
+	}
+	return days, nil
+}
+
+// GetAnalyticsTotals returns a builder's lifetime counters (empty if they have none yet).
+func (f *FirestoreClient) GetAnalyticsTotals(ctx context.Context, builderID string) (map[string]int64, error) {
+	docSnap, err := f.client.Collection(analyticsTotalsCollection).Doc(builderID).Get(ctx)
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			return map[string]int64{}, nil
+		}
+		return nil, fmt.Errorf("firestore get analytics totals error: %w", err)
+	}
+
+	var totals struct {
+		Counts map[string]int64 `firestore:"counts"`
+	}
+	if err := docSnap.DataTo(&totals); err != nil {
+		return nil, fmt.Errorf("failed to map data to analytics totals: %w", err)
+	}
+	return totals.Counts, nil
+}
+
+// MarkVisitor creates the marker; AlreadyExists means this visitor was seen today.
+func (f *FirestoreClient) MarkVisitor(ctx context.Context, key string, expires time.Time) (bool, error) {
+	_, err := f.client.Collection(analyticsVisitorsCollection).Doc(key).Create(ctx, map[string]interface{}{
+		"expires_at": expires,
+	})
+	if err != nil {
+		if status.Code(err) == codes.AlreadyExists {
+			return false, nil
+		}
+		return false, fmt.Errorf("firestore mark visitor error: %w", err)
+	}
+	return true, nil
+}
+
+// DailySalt creates the day's salt if no instance has yet, then reads back
+// whichever one won.
+func (f *FirestoreClient) DailySalt(ctx context.Context, day string, expires time.Time) ([]byte, error) {
+	docRef := f.client.Collection(analyticsSaltsCollection).Doc(day)
+
+	salt := make([]byte, 32)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, fmt.Errorf("failed to generate analytics salt: %w", err)
+	}
+	_, err := docRef.Create(ctx, map[string]interface{}{
+		"salt":       salt,
+		"expires_at": expires,
+	})
+	if err == nil {
+		return salt, nil
+	}
+	if status.Code(err) != codes.AlreadyExists {
+		return nil, fmt.Errorf("firestore create analytics salt error: %w", err)
+	}
+
+	docSnap, err := docRef.Get(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("firestore get analytics salt error: %w", err)
+	}
+	var stored struct {
+		Salt []byte `firestore:"salt"`
+	}
+	if err := docSnap.DataTo(&stored); err != nil {
+		return nil, fmt.Errorf("failed to map analytics salt: %w", err)
+	}
+	return stored.Salt, nil
+}
@@ -0,0 +1,105 @@
+// ... (previous code for users, drops, orders, assets, guilds, profiles, slugs, guestbooks, profile assets and analytics remains above)
+
+const (
+	// ...
+	twitchLinksCollection = "twitch_links" // Keyed by Twitch user ID -> {builder_id}
+)
+
+// =================================================================
+// TwitchRepository Implementation
+// These methods fulfill the interface defined in twitch_service.go
+// =================================================================
+
+// LinkTwitch claims the Twitch user ID in the registry and sets it on the
+// builder in one transaction, so two builders can't race for one channel.
+func (f *FirestoreClient) LinkTwitch(ctx context.Context, builderID string, account *domain.TwitchAccount) error {
+	userRef := f.client.Collection(usersCollection).Doc(builderID)
+	linkRef := f.client.Collection(twitchLinksCollection).Doc(account.UserID)
+
+	err := f.client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		userSnap, err := tx.Get(userRef)
+		if err != nil {
+			return err
+		}
+		var builder domain.Builder
+		if err := userSnap.DataTo(&builder); err != nil {
+			return err
+		}
+
+		linkSnap, err := tx.Get(linkRef)
+		if err == nil {
+			owner, _ := linkSnap.DataAt("builder_id")
+			if owner != builderID {
+				return service.ErrTwitchAccountTaken
+			}
+		} else if status.Code(err) != codes.NotFound {
+			return err
+		}
+
+		// Re-linking a different account releases the old one.
+		if builder.Twitch != nil && builder.Twitch.UserID != account.UserID {
+			if err := tx.Delete(f.client.Collection(twitchLinksCollection).Doc(builder.Twitch.UserID)); err != nil {
+				return err
+			}
+		}
+		if err := tx.Set(linkRef, map[string]interface{}{
+			"builder_id": builderID,
+			"linked_at":  account.LinkedAt,
+		}); err != nil {
+			return err
+		}
+		return tx.Update(userRef, []firestore.Update{
+			{Path: "twitch", Value: account},
+			{Path: "updated_at", Value: time.Now().UTC()},
+		})
+	})
+	if err != nil {
+		if errors.Is(err, service.ErrTwitchAccountTaken) {
+			return err
+		}
+		if status.Code(err) == codes.NotFound {
+			return service.ErrBuilderNotFound
+		}
+		return fmt.Errorf("firestore link twitch error: %w", err)
+	}
+	return nil
+}
+
+// UnlinkTwitch releases the builder's Twitch user ID and clears it from the builder.
+func (f *FirestoreClient) UnlinkTwitch(ctx context.Context, builderID string) (*domain.TwitchAccount, error) {
+	userRef := f.client.Collection(usersCollection).Doc(builderID)
+
+	var account *domain.TwitchAccount
+	err := f.client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		userSnap, err := tx.Get(userRef)
+		if err != nil {
+			return err
+		}
+		var builder domain.Builder
+		if err := userSnap.DataTo(&builder); err != nil {
+			return err
+		}
+		if builder.Twitch == nil {
+			return service.ErrTwitchNotLinked
+		}
+		account = builder.Twitch
+
+		if err := tx.Delete(f.client.Collection(twitchLinksCollection).Doc(account.UserID)); err != nil {
+			return err
+		}
+		return tx.Update(userRef, []firestore.Update{
+			{Path: "twitch", Value: firestore.Delete},
+			{Path: "updated_at", Value: time.Now().UTC()},
+		})
+	})
+	if err != nil {
+		if errors.Is(err, service.ErrTwitchNotLinked) {
+			return nil, err
+		}
+		if status.Code(err) == codes.NotFound {
+			return nil, service.ErrBuilderNotFound
+		}
+		return nil, fmt.Errorf("firestore unlink twitch error: %w", err)
+	}
+	return account, nil
+}
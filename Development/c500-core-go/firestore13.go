@@ -0,0 +1,33 @@
+// ... (previous code for users, drops, orders, assets, guilds, profiles, slugs, guestbooks, profile assets, analytics, twitch links and eventsub messages remains above)
+
+// =================================================================
+// VOD Review Implementation
+// These methods fulfill the OrderRepository interface in fulfillment_service.go
+// =================================================================
+
+// ListOrdersInVODReview needs a composite index on (vod_review.status, vod_review.submitted_at).
+func (f *FirestoreClient) ListOrdersInVODReview(ctx context.Context, limit int) ([]domain.Order, error) {
+	iter := f.client.Collection(ordersCollection).
+		Where("vod_review.status", "==", domain.VODReviewPending).
+		OrderBy("vod_review.submitted_at", firestore.Asc).
+		Limit(limit).
+		Documents(ctx)
+	defer iter.Stop()
+
+	var orders []domain.Order
+	for {
+		doc, err := iter.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("firestore list vod reviews error: %w", err)
+		}
+		var order domain.Order
+		if err := doc.DataTo(&order); err != nil {
+			continue
+		}
+		orders = append(orders, order)
+	}
+	return orders, nil
+}
@@ -0,0 +1,59 @@
+// ... (previous code for users, drops, orders, assets, guilds, profiles, slugs, guestbooks, profile assets, analytics, twitch links, eventsub messages, vod reviews, production timelines and shipment tracking remains above)
+
+const (
+	// ...
+	waitlistCollection = "waitlist" // Subcollection under drops/{dropID}, keyed by Discord ID
+)
+
+// maxWaitlistTake keeps TakeWaitlist inside Firestore's 500 writes per
+// transaction. Anyone past it stays on the list for the next time.
+const maxWaitlistTake = 450
+
+// =================================================================
+// WaitlistRepository Implementation
+// These methods fulfill the interface defined in waitlist_service.go
+// =================================================================
+
+func (f *FirestoreClient) waitlistRef(dropID string) *firestore.CollectionRef {
+	return f.client.Collection(dropsCollection).Doc(dropID).Collection(waitlistCollection)
+}
+
+// AddToWaitlist is keyed by Discord ID, so joining twice just refreshes joined_at.
+func (f *FirestoreClient) AddToWaitlist(ctx context.Context, entry *domain.WaitlistEntry) error {
+	if _, err := f.waitlistRef(entry.DropID).Doc(entry.DiscordID).Set(ctx, entry); err != nil {
+		return fmt.Errorf("firestore add to waitlist error: %w", err)
+	}
+	return nil
+}
+
+// TakeWaitlist reads and deletes the waitlist in one transaction, oldest
+// first, so concurrent callers never both get the same entry.
+func (f *FirestoreClient) TakeWaitlist(ctx context.Context, dropID string) ([]domain.WaitlistEntry, error) {
+	query := f.waitlistRef(dropID).OrderBy("joined_at", firestore.Asc).Limit(maxWaitlistTake)
+
+	var entries []domain.WaitlistEntry
+	err := f.client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		entries = nil // The transaction function can be retried.
+		docs, err := tx.Documents(query).GetAll()
+		if err != nil {
+			return err
+		}
+		for _, doc := range docs {
+			var entry domain.WaitlistEntry
+			if err := doc.DataTo(&entry); err != nil {
+				continue
+			}
+			entries = append(entries, entry)
+		}
+		for _, doc := range docs {
+			if err := tx.Delete(doc.Ref); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("firestore take waitlist error: %w", err)
+	}
+	return entries, nil
+}
@@ -0,0 +1,38 @@
+// ... (previous code for users, drops and orders remains above)
+
+const (
+	// ...
+	assetsCollection = "assets"
+)
+
+// =================================================================
+// AssetRepository Implementation
+// These methods fulfill the interface defined in asset_service.go
+// The image bytes live in the BlobStore; only metadata is stored here.
+// =================================================================
+
+// CreateAsset saves the metadata for a freshly uploaded image.
+func (f *FirestoreClient) CreateAsset(ctx context.Context, asset *domain.ImageAsset) error {
+	_, err := f.client.Collection(assetsCollection).Doc(asset.ID).Create(ctx, asset)
+	if err != nil {
+		return fmt.Errorf("firestore create asset error: %w", err)
+	}
+	return nil
+}
+
+// GetAssetByID fetches asset metadata, e.g. to resolve a drop's image IDs into URLs.
+func (f *FirestoreClient) GetAssetByID(ctx context.Context, assetID string) (*domain.ImageAsset, error) {
+	docSnap, err := f.client.Collection(assetsCollection).Doc(assetID).Get(ctx)
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			return nil, fmt.Errorf("asset %s: %w", assetID, service.ErrAssetNotFound)
+		}
+		return nil, fmt.Errorf("firestore get asset error: %w", err)
+	}
+
+	var asset domain.ImageAsset
+	if err := docSnap.DataTo(&asset); err != nil {
+		return nil, fmt.Errorf("failed to map data to asset struct: %w", err)
+	}
+	return &asset, nil
+}
@@ -0,0 +1,84 @@
+// ... (previous code for users, drops, orders and assets remains above)
+
+// =================================================================
+// ScheduledDropRepository Implementation
+// These methods fulfill the interface defined in scheduler/drop_scheduler.go
+// =================================================================
+
+// ListScheduledDropsBefore finds scheduled drops going live at or before cutoff.
+// Requires a composite index on (status ASC, go_live_at ASC).
+func (f *FirestoreClient) ListScheduledDropsBefore(ctx context.Context, cutoff time.Time) ([]domain.Drop, error) {
+	docs, err := f.client.Collection(dropsCollection).
+		Where("status", "==", domain.StatusScheduled).
+		Where("go_live_at", "<=", cutoff).
+		OrderBy("go_live_at", firestore.Asc).
+		Documents(ctx).GetAll()
+	if err != nil {
+		return nil, fmt.Errorf("firestore list scheduled drops error: %w", err)
+	}
+
+	drops := make([]domain.Drop, 0, len(docs))
+	for _, doc := range docs {
+		var drop domain.Drop
+		if err := doc.DataTo(&drop); err != nil {
+			continue
+		}
+		drops = append(drops, drop)
+	}
+	return drops, nil
+}
+
+// PublishScheduledDrop flips a drop from scheduled to available inside a transaction.
+// The transaction re-reads the status, so if two Core instances race, exactly one wins.
+func (f *FirestoreClient) PublishScheduledDrop(ctx context.Context, dropID string) (bool, error) {
+	docRef := f.client.Collection(dropsCollection).Doc(dropID)
+	won := false
+
+	err := f.client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		won = false // Transactions may retry; reset on every attempt.
+		snap, err := tx.Get(docRef)
+		if err != nil {
+			return err
+		}
+		if current, _ := snap.DataAt("status"); current != string(domain.StatusScheduled) {
+			return nil // Already published, cancelled or sold.
+		}
+		won = true
+		return tx.Update(docRef, []firestore.Update{
+			{Path: "status", Value: domain.StatusAvailable},
+			{Path: "updated_at", Value: time.Now().UTC()},
+		})
+	})
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			return false, fmt.Errorf("drop not found during publish: %w", service.ErrDropNotFound)
+		}
+		return false, fmt.Errorf("firestore publish scheduled drop error: %w", err)
+	}
+	return won, nil
+}
+
+// MarkOneHourNoticeSent stamps one_hour_notice_at exactly once.
+func (f *FirestoreClient) MarkOneHourNoticeSent(ctx context.Context, dropID string) (bool, error) {
+	docRef := f.client.Collection(dropsCollection).Doc(dropID)
+	won := false
+
+	err := f.client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		won = false
+		snap, err := tx.Get(docRef)
+		if err != nil {
+			return err
+		}
+		if existing, _ := snap.DataAt("one_hour_notice_at"); existing != nil {
+			return nil
+		}
+		won = true
+		return tx.Update(docRef, []firestore.Update{
+			{Path: "one_hour_notice_at", Value: time.Now().UTC()},
+		})
+	})
+	if err != nil {
+		return false, fmt.Errorf("firestore mark drop notice error: %w", err)
+	}
+	return won, nil
+}
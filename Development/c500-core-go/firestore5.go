@@ -0,0 +1,122 @@
+// ... (previous code for users, drops, orders and assets remains above)
+
+const (
+	// ...
+	guildSubscriptionsCollection = "guild_subscriptions"
+	dropPostsCollection          = "drop_posts"
+)
+
+// =================================================================
+// GuildRepository Implementation
+// These methods fulfill the interface defined in fanout_service.go
+// =================================================================
+
+// UpsertSubscription creates or overwrites a guild's subscription (doc ID = guild ID).
+func (f *FirestoreClient) UpsertSubscription(ctx context.Context, sub *domain.GuildSubscription) error {
+	_, err := f.client.Collection(guildSubscriptionsCollection).Doc(sub.GuildID).Set(ctx, sub)
+	if err != nil {
+		return fmt.Errorf("firestore upsert subscription error: %w", err)
+	}
+	return nil
+}
+
+// GetSubscription fetches one guild's subscription.
+func (f *FirestoreClient) GetSubscription(ctx context.Context, guildID string) (*domain.GuildSubscription, error) {
+	docSnap, err := f.client.Collection(guildSubscriptionsCollection).Doc(guildID).Get(ctx)
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			return nil, service.ErrSubscriptionNotFound
+		}
+		return nil, fmt.Errorf("firestore get subscription error: %w", err)
+	}
+
+	var sub domain.GuildSubscription
+	if err := docSnap.DataTo(&sub); err != nil {
+		return nil, fmt.Errorf("failed to map data to subscription struct: %w", err)
+	}
+	return &sub, nil
+}
+
+// DeleteSubscription removes a guild from the registry.
+func (f *FirestoreClient) DeleteSubscription(ctx context.Context, guildID string) error {
+	_, err := f.client.Collection(guildSubscriptionsCollection).Doc(guildID).Delete(ctx)
+	if err != nil {
+		return fmt.Errorf("firestore delete subscription error: %w", err)
+	}
+	return nil
+}
+
+// ListSubscriptions returns every subscribed guild.
+// The number of partner servers is small (dozens), so we read them all and
+// filter in the service layer rather than building a query per drop.
+func (f *FirestoreClient) ListSubscriptions(ctx context.Context) ([]domain.GuildSubscription, error) {
+	docs, err := f.client.Collection(guildSubscriptionsCollection).Documents(ctx).GetAll()
+	if err != nil {
+		return nil, fmt.Errorf("firestore list subscriptions error: %w", err)
+	}
+
+	subs := make([]domain.GuildSubscription, 0, len(docs))
+	for _, doc := range docs {
+		var sub domain.GuildSubscription
+		if err := doc.DataTo(&sub); err != nil {
+			continue
+		}
+		subs = append(subs, sub)
+	}
+	return subs, nil
+}
+
+// CreateDropPosts reserves one post record per target guild in a single batch.
+// We use Set (not Create) so re-announcing a drop to the same guild reuses its record.
+func (f *FirestoreClient) CreateDropPosts(ctx context.Context, posts []domain.DropPost) error {
+	batch := f.client.Batch()
+	for i := range posts {
+		ref := f.client.Collection(dropPostsCollection).Doc(domain.DropPostID(posts[i].DropID, posts[i].GuildID))
+		batch.Set(ref, posts[i])
+	}
+	if _, err := batch.Commit(ctx); err != nil {
+		return fmt.Errorf("firestore create drop posts error: %w", err)
+	}
+	return nil
+}
+
+// RecordDropPostMessage saves the Discord message ID once a bot has posted.
+// It merges into the record reserved by CreateDropPosts, or creates one for
+// posts nobody reserved (e.g. the home server's own #marketplace).
+func (f *FirestoreClient) RecordDropPostMessage(ctx context.Context, post *domain.DropPost) error {
+	docRef := f.client.Collection(dropPostsCollection).Doc(domain.DropPostID(post.DropID, post.GuildID))
+	data := map[string]interface{}{
+		"drop_id":    post.DropID,
+		"guild_id":   post.GuildID,
+		"channel_id": post.ChannelID,
+		"message_id": post.MessageID,
+		"posted_at":  post.PostedAt,
+	}
+	if _, err := docRef.Set(ctx, data, firestore.MergeAll); err != nil {
+		return fmt.Errorf("firestore record drop post error: %w", err)
+	}
+	return nil
+}
+
+// ListDropPosts returns every confirmed Discord message showing a drop.
+func (f *FirestoreClient) ListDropPosts(ctx context.Context, dropID string) ([]domain.DropPost, error) {
+	docs, err := f.client.Collection(dropPostsCollection).
+		Where("drop_id", "==", dropID).
+		Documents(ctx).GetAll()
+	if err != nil {
+		return nil, fmt.Errorf("firestore list drop posts error: %w", err)
+	}
+
+	posts := make([]domain.DropPost, 0, len(docs))
+	for _, doc := range docs {
+		var post domain.DropPost
+		if err := doc.DataTo(&post); err != nil {
+			continue
+		}
+		if post.MessageID == "" {
+			continue // Reserved but the bot never confirmed it; nothing to edit.
+		}
+		posts = append(posts, post)
+	}
+	return posts, nil
+}
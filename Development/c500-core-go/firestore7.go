@@ -0,0 +1,97 @@
+// ... (previous code for users, drops, orders, assets, guilds and profiles remains above)
+
+const (
+	// ...
+	slugsCollection = "slugs"
+)
+
+// =================================================================
+// SlugRepository Implementation
+// These methods fulfill the interface defined in slug_service.go
+// =================================================================
+
+// GetSlug fetches one entry from the slug registry.
+func (f *FirestoreClient) GetSlug(ctx context.Context, slug string) (*domain.Slug, error) {
+	docSnap, err := f.client.Collection(slugsCollection).Doc(slug).Get(ctx)
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			return nil, service.ErrSlugNotFound
+		}
+		return nil, fmt.Errorf("firestore get slug error: %w", err)
+	}
+
+	var rec domain.Slug
+	if err := docSnap.DataTo(&rec); err != nil {
+		return nil, fmt.Errorf("failed to map data to slug struct: %w", err)
+	}
+	return &rec, nil
+}
+
+// ClaimSlug moves a builder to a new slug inside a transaction, so two builders
+// racing for the same name can't both win, and the builder doc, the new slug
+// and the retired one always agree.
+func (f *FirestoreClient) ClaimSlug(ctx context.Context, builderID, slug string, now, redirectUntil time.Time) error {
+	userRef := f.client.Collection(usersCollection).Doc(builderID)
+	slugRef := f.client.Collection(slugsCollection).Doc(slug)
+
+	err := f.client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		// All reads first (Firestore transactions require reads before writes).
+		userSnap, err := tx.Get(userRef)
+		if err != nil {
+			return err
+		}
+		var builder domain.Builder
+		if err := userSnap.DataTo(&builder); err != nil {
+			return err
+		}
+		if builder.Slug == slug {
+			return nil
+		}
+
+		slugSnap, err := tx.Get(slugRef)
+		if err != nil && status.Code(err) != codes.NotFound {
+			return err
+		}
+		if slugSnap != nil && slugSnap.Exists() {
+			var existing domain.Slug
+			if err := slugSnap.DataTo(&existing); err != nil {
+				return err
+			}
+			if existing.Blocks(builderID, now) {
+				return service.ErrSlugTaken
+			}
+		}
+
+		// Overwriting clears any retired_at / redirect_until from a previous owner.
+		if err := tx.Set(slugRef, domain.Slug{Slug: slug, BuilderID: builderID, CreatedAt: now}); err != nil {
+			return err
+		}
+		if builder.Slug != "" {
+			oldRef := f.client.Collection(slugsCollection).Doc(builder.Slug)
+			// Set+merge rather than Update, so a missing registry entry is repaired instead of failing.
+			if err := tx.Set(oldRef, map[string]interface{}{
+				"slug":           builder.Slug,
+				"builder_id":     builderID,
+				"retired_at":     now,
+				"redirect_until": redirectUntil,
+			}, firestore.MergeAll); err != nil {
+				return err
+			}
+		}
+		return tx.Update(userRef, []firestore.Update{
+			{Path: "slug", Value: slug},
+			{Path: "slug_changed_at", Value: now},
+			{Path: "updated_at", Value: now},
+		})
+	})
+	if err != nil {
+		if errors.Is(err, service.ErrSlugTaken) {
+			return err
+		}
+		if status.Code(err) == codes.NotFound {
+			return service.ErrBuilderNotFound
+		}
+		return fmt.Errorf("firestore claim slug error: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,149 @@
+// ... (previous code for users, drops, orders, assets, guilds, profiles and slugs remains above)
+
+const (
+	// ...
+	guestbookCollection = "guestbook_entries"
+)
+
+// =================================================================
+// GuestbookRepository Implementation
+// These methods fulfill the interface defined in guestbook_service.go
+// =================================================================
+
+// CreateGuestbookEntry saves a new entry.
+func (f *FirestoreClient) CreateGuestbookEntry(ctx context.Context, entry *domain.GuestbookEntry) error {
+	_, err := f.client.Collection(guestbookCollection).Doc(entry.ID).Create(ctx, entry)
+	if err != nil {
+		return fmt.Errorf("firestore create guestbook entry error: %w", err)
+	}
+	return nil
+}
+
+// GetGuestbookEntry fetches one entry by ID.
+func (f *FirestoreClient) GetGuestbookEntry(ctx context.Context, entryID string) (*domain.GuestbookEntry, error) {
+	docSnap, err := f.client.Collection(guestbookCollection).Doc(entryID).Get(ctx)
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			return nil, service.ErrGuestbookEntryNotFound
+		}
+		return nil, fmt.Errorf("firestore get guestbook entry error: %w", err)
+	}
+
+	var entry domain.GuestbookEntry
+	if err := docSnap.DataTo(&entry); err != nil {
+		return nil, fmt.Errorf("failed to map data to guestbook entry struct: %w", err)
+	}
+	return &entry, nil
+}
+
+// ListGuestbookEntries returns a builder's entries, newest first.
+// Requires a composite index on (builder_id, status, created_at desc).
+func (f *FirestoreClient) ListGuestbookEntries(ctx context.Context, builderID string, statuses []domain.GuestbookStatus, limit int) ([]domain.GuestbookEntry, error) {
+	query := f.client.Collection(guestbookCollection).
+		Where("builder_id", "==", builderID).
+		Where("status", "in", statuses).
+		OrderBy("created_at", firestore.Desc).
+		Limit(limit)
+	return f.queryGuestbook(ctx, query)
+}
+
+// ListGuestbookEntriesByAuthorSince is used for rate limiting.
+// Requires a composite index on (author_discord_id, created_at).
+func (f *FirestoreClient) ListGuestbookEntriesByAuthorSince(ctx context.Context, authorID string, since time.Time) ([]domain.GuestbookEntry, error) {
+	query := f.client.Collection(guestbookCollection).
+		Where("author_discord_id", "==", authorID).
+		Where("created_at", ">=", since)
+	return f.queryGuestbook(ctx, query)
+}
+
+// UpdateGuestbookEntry applies a partial update (status, moderation fields...).
+func (f *FirestoreClient) UpdateGuestbookEntry(ctx context.Context, entryID string, updates map[string]interface{}) error {
+	fsUpdates := make([]firestore.Update, 0, len(updates))
+	for field, value := range updates {
+		fsUpdates = append(fsUpdates, firestore.Update{Path: field, Value: value})
+	}
+
+	_, err := f.client.Collection(guestbookCollection).Doc(entryID).Update(ctx, fsUpdates)
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			return service.ErrGuestbookEntryNotFound
+		}
+		return fmt.Errorf("firestore update guestbook entry error: %w", err)
+	}
+	return nil
+}
+
+// DeleteGuestbookEntry permanently removes an entry.
+func (f *FirestoreClient) DeleteGuestbookEntry(ctx context.Context, entryID string) error {
+	_, err := f.client.Collection(guestbookCollection).Doc(entryID).Delete(ctx)
+	if err != nil {
+		return fmt.Errorf("firestore delete guestbook entry error: %w", err)
+	}
+	return nil
+}
+
+// ReportGuestbookEntry counts each reporter once. It runs in a transaction so
+// report_count always matches reported_by.
+func (f *FirestoreClient) ReportGuestbookEntry(ctx context.Context, entryID, reporterID, reason string) error {
+	docRef := f.client.Collection(guestbookCollection).Doc(entryID)
+
+	err := f.client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		snap, err := tx.Get(docRef)
+		if err != nil {
+			return err
+		}
+		var entry domain.GuestbookEntry
+		if err := snap.DataTo(&entry); err != nil {
+			return err
+		}
+		for _, id := range entry.ReportedBy {
+			if id == reporterID {
+				return nil // Already reported by this person.
+			}
+		}
+		if entry.Status == domain.GuestbookRemoved {
+			return nil
+		}
+		return tx.Update(docRef, []firestore.Update{
+			{Path: "reported_by", Value: firestore.ArrayUnion(reporterID)},
+			{Path: "report_count", Value: firestore.Increment(1)},
+			{Path: "last_report_reason", Value: reason},
+			{Path: "needs_review", Value: true},
+			{Path: "updated_at", Value: time.Now().UTC()},
+		})
+	})
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			return service.ErrGuestbookEntryNotFound
+		}
+		return fmt.Errorf("firestore report guestbook entry error: %w", err)
+	}
+	return nil
+}
+
+// ListReportedGuestbookEntries is the moderation queue, oldest report first.
+// Requires a composite index on (needs_review, updated_at).
+func (f *FirestoreClient) ListReportedGuestbookEntries(ctx context.Context, limit int) ([]domain.GuestbookEntry, error) {
+	query := f.client.Collection(guestbookCollection).
+		Where("needs_review", "==", true).
+		OrderBy("updated_at", firestore.Asc).
+		Limit(limit)
+	return f.queryGuestbook(ctx, query)
+}
+
+func (f *FirestoreClient) queryGuestbook(ctx context.Context, query firestore.Query) ([]domain.GuestbookEntry, error) {
+	docs, err := query.Documents(ctx).GetAll()
+	if err != nil {
+		return nil, fmt.Errorf("firestore list guestbook entries error: %w", err)
+	}
+
+	entries := make([]domain.GuestbookEntry, 0, len(docs))
+	for _, doc := range docs {
+		var entry domain.GuestbookEntry
+		if err := doc.DataTo(&entry); err != nil {
+			return nil, fmt.Errorf("failed to map data to guestbook entry struct: %w", err)
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
@@ -0,0 +1,136 @@
+// ... (previous code for users, drops, orders, assets, guilds, profiles, slugs and guestbooks remains above)
+
+const (
+	// ...
+	profileAssetsCollection = "profile_assets" // Subcollection under users/{discordID}
+)
+
+// =================================================================
+// ProfileAssetRepository Implementation
+// These methods fulfill the interface defined in profile_asset_service.go
+// =================================================================
+
+func (f *FirestoreClient) profileAssetsRef(builderID string) *firestore.CollectionRef {
+	return f.client.Collection(usersCollection).Doc(builderID).Collection(profileAssetsCollection)
+}
+
+// GetProfileAsset fetches one asset from a builder's library.
+func (f *FirestoreClient) GetProfileAsset(ctx context.Context, builderID, assetID string) (*domain.ProfileAsset, error) {
+	docSnap, err := f.profileAssetsRef(builderID).Doc(assetID).Get(ctx)
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			return nil, service.ErrProfileAssetNotFound
+		}
+		return nil, fmt.Errorf("firestore get profile asset error: %w", err)
+	}
+
+	var asset domain.ProfileAsset
+	if err := docSnap.DataTo(&asset); err != nil {
+		return nil, fmt.Errorf("failed to map data to profile asset struct: %w", err)
+	}
+	return &asset, nil
+}
+
+// ListProfileAssets returns a builder's library, newest first. Libraries are
+// capped (see maxProfileAssets), so there's no paging.
+func (f *FirestoreClient) ListProfileAssets(ctx context.Context, builderID string) ([]domain.ProfileAsset, error) {
+	iter := f.profileAssetsRef(builderID).
+		OrderBy("created_at", firestore.Desc).
+		Documents(ctx)
+	defer iter.Stop()
+
+	var assets []domain.ProfileAsset
+	for {
+		doc, err := iter.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("firestore list profile assets error: %w", err)
+		}
+
+		var asset domain.ProfileAsset
+		if err := doc.DataTo(&asset); err != nil {
+			return nil, fmt.Errorf("failed to map data to profile asset struct: %w", err)
+		}
+		assets = append(assets, asset)
+	}
+	return assets, nil
+}
+
+// CreateProfileAsset records the asset and bumps the builder's usage counters in
+// one transaction, so two uploads at once can't both squeeze under the quota.
+func (f *FirestoreClient) CreateProfileAsset(ctx context.Context, asset *domain.ProfileAsset, quotaBytes int64, maxCount int) error {
+	userRef := f.client.Collection(usersCollection).Doc(asset.BuilderID)
+	assetRef := f.profileAssetsRef(asset.BuilderID).Doc(asset.ID)
+
+	err := f.client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		userSnap, err := tx.Get(userRef)
+		if err != nil {
+			return err
+		}
+		if _, err := tx.Get(assetRef); err == nil {
+			return nil // Same file uploaded twice at once; the first one counted.
+		} else if status.Code(err) != codes.NotFound {
+			return err
+		}
+
+		var builder domain.Builder
+		if err := userSnap.DataTo(&builder); err != nil {
+			return err
+		}
+		if builder.ProfileAssetBytes+asset.SizeBytes > quotaBytes || builder.ProfileAssetCount+1 > maxCount {
+			return service.ErrProfileAssetQuota
+		}
+
+		if err := tx.Create(assetRef, asset); err != nil {
+			return err
+		}
+		return tx.Update(userRef, []firestore.Update{
+			{Path: "profile_asset_bytes", Value: firestore.Increment(asset.SizeBytes)},
+			{Path: "profile_asset_count", Value: firestore.Increment(1)},
+		})
+	})
+	if err != nil {
+		if errors.Is(err, service.ErrProfileAssetQuota) {
+			return err
+		}
+		if status.Code(err) == codes.NotFound {
+			return service.ErrBuilderNotFound
+		}
+		return fmt.Errorf("firestore create profile asset error: %w", err)
+	}
+	return nil
+}
+
+// DeleteProfileAsset removes the asset record and gives its bytes back to the quota.
+func (f *FirestoreClient) DeleteProfileAsset(ctx context.Context, builderID, assetID string) (*domain.ProfileAsset, error) {
+	userRef := f.client.Collection(usersCollection).Doc(builderID)
+	assetRef := f.profileAssetsRef(builderID).Doc(assetID)
+
+	var asset domain.ProfileAsset
+	err := f.client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		snap, err := tx.Get(assetRef)
+		if err != nil {
+			return err
+		}
+		if err := snap.DataTo(&asset); err != nil {
+			return err
+		}
+
+		if err := tx.Delete(assetRef); err != nil {
+			return err
+		}
+		return tx.Update(userRef, []firestore.Update{
+			{Path: "profile_asset_bytes", Value: firestore.Increment(-asset.SizeBytes)},
+			{Path: "profile_asset_count", Value: firestore.Increment(-1)},
+		})
+	})
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			return nil, service.ErrProfileAssetNotFound
+		}
+		return nil, fmt.Errorf("firestore delete profile asset error: %w", err)
+	}
+	return &asset, nil
+}
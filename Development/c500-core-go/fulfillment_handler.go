@@ -3,9 +3,11 @@ package http
 import (
 	"errors"
 	"net/http"
+	"strconv"
 
 	"github.com/gin-gonic/gin"
 
+	"c500-core-go/internal/domain"
 	"c500-core-go/internal/service"
 )
 
@@ -22,12 +24,17 @@ func NewFulfillmentHandler(fs service.FulfillmentService) *FulfillmentHandler {
 }
 
 // RegisterRoutes connects the HTTP URLs to the handler functions.
-// This is called in main.go.
+// This is called in main.go, on the internal group: every route here can
+// release escrow and trusts the seller or moderator ID the bot sends, which
+// it takes from the Discord interaction.
 func (h *FulfillmentHandler) RegisterRoutes(router *gin.RouterGroup) {
 	// Map endpoints to handle shipping and live fulfillment methods.
 	// The ':orderID' part is a path parameter that Gin extracts for us.
 	router.POST("/orders/:orderID/fulfill/ship", h.FulfillShip)
 	router.POST("/orders/:orderID/fulfill/live", h.FulfillLive)
+	// VODs that failed verification wait here for a moderator.
+	router.GET("/moderation/vods", h.VODReviewQueue)
+	router.POST("/moderation/vods/:orderID", h.ReviewVOD)
 }
 
 
@@ -45,16 +52,23 @@ type fulfillShipRequest struct {
 
 // fulfillLiveRequest defines the expected JSON body from /fulfill live.
 type fulfillLiveRequest struct {
-	VODLink         string `json:"vod_url" binding:"required,url"` # Use 'url' validator
+	VODLink         string `json:"vod_url" binding:"required,url"` // Use 'url' validator; the service checks it's a Twitch VOD
 	SellerDiscordID string `json:"seller_discord_id" binding:"required"`
 }
 
+// reviewVODRequest is a moderator's decision on a queued VOD.
+type reviewVODRequest struct {
+	ModeratorDiscordID string `json:"moderator_discord_id" binding:"required"`
+	Action             string `json:"action" binding:"required,oneof=approve reject"`
+}
+
 
 // ==========================================
 // Handler Functions
 // ==========================================
 
-// FulfillShip processes requests for Ready-to-Ship items.
+// FulfillShip marks an order shipped. Ready-to-Ship items are paid out here;
+// commissions still need their build VOD (FulfillLive).
 func (h *FulfillmentHandler) FulfillShip(c *gin.Context) {
 	orderID := c.Param("orderID")
 	var req fulfillShipRequest
@@ -66,7 +80,7 @@ func (h *FulfillmentHandler) FulfillShip(c *gin.Context) {
 	}
 
 	// 2. Call the Service Layer
-	err := h.fulfillmentService.FulfillOrderWithShipping(c.Request.Context(), orderID, req.SellerDiscordID, req.TrackingNumber, req.Carrier)
+	released, err := h.fulfillmentService.FulfillOrderWithShipping(c.Request.Context(), orderID, req.SellerDiscordID, req.TrackingNumber, req.Carrier)
 
 	// 3. Handle Errors
 	if err != nil {
@@ -78,6 +92,10 @@ func (h *FulfillmentHandler) FulfillShip(c *gin.Context) {
 			c.JSON(http.StatusForbidden, gin.H{"error": "You are not the seller of this order"})
 		case errors.Is(err, service.ErrOrderAlreadyFulfilled):
 			c.JSON(http.StatusBadRequest, gin.H{"error": "Order is already fulfilled"})
+		case errors.Is(err, service.ErrUnknownCarrier), errors.Is(err, domain.ErrInvalidTracking):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case errors.Is(err, service.ErrInvalidProductionStep), errors.Is(err, service.ErrProductionConflict):
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
 		default:
 			// Log actual error in production
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to process fulfillment"})
@@ -86,6 +104,10 @@ func (h *FulfillmentHandler) FulfillShip(c *gin.Context) {
 	}
 
 	// 4. Success
+	if !released {
+		c.JSON(http.StatusOK, gin.H{"status": "shipped", "message": "Order marked shipped. Submit the build VOD to release the funds."})
+		return
+	}
 	c.JSON(http.StatusOK, gin.H{"status": "success", "message": "Order fulfilled and funds released."})
 }
 
@@ -99,7 +121,7 @@ func (h *FulfillmentHandler) FulfillLive(c *gin.Context) {
 		return
 	}
 
-	err := h.fulfillmentService.FulfillOrderWithVOD(c.Request.Context(), orderID, req.SellerDiscordID, req.VODLink)
+	reviewReason, err := h.fulfillmentService.FulfillOrderWithVOD(c.Request.Context(), orderID, req.SellerDiscordID, req.VODLink)
 
 	if err != nil {
 		// (Same error handling logic as above)
@@ -110,11 +132,69 @@ func (h *FulfillmentHandler) FulfillLive(c *gin.Context) {
 			c.JSON(http.StatusForbidden, gin.H{"error": "You are not the seller of this order"})
 		case errors.Is(err, service.ErrOrderAlreadyFulfilled):
 			c.JSON(http.StatusBadRequest, gin.H{"error": "Order is already fulfilled"})
+		case errors.Is(err, service.ErrVODInReview), errors.Is(err, service.ErrProductionNotFinished):
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
 		default:
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to process fulfillment"})
 		}
 		return
 	}
 
+	// The VOD didn't check out: funds stay held until a moderator reviews it.
+	if reviewReason != "" {
+		c.JSON(http.StatusAccepted, gin.H{
+			"status":  "in_review",
+			"reason":  reviewReason,
+			"message": "Your VOD couldn't be verified automatically (" + reviewReason + "). A moderator will review it before funds are released.",
+		})
+		return
+	}
+
 	c.JSON(http.StatusOK, gin.H{"status": "success", "message": "Order fulfilled and funds released."})
 }
+
+// VODReviewQueue handles GET /api/v1/moderation/vods?moderator_id=&limit=
+func (h *FulfillmentHandler) VODReviewQueue(c *gin.Context) {
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if err != nil || limit < 1 || limit > 100 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "limit must be between 1 and 100"})
+		return
+	}
+
+	orders, err := h.fulfillmentService.VODReviewQueue(c.Request.Context(), c.Query("moderator_id"), limit)
+	if err != nil {
+		if errors.Is(err, service.ErrNotModerator) {
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load VOD review queue"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"orders": orders})
+}
+
+// ReviewVOD handles POST /api/v1/moderation/vods/:orderID
+func (h *FulfillmentHandler) ReviewVOD(c *gin.Context) {
+	var req reviewVODRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	err := h.fulfillmentService.ReviewVOD(c.Request.Context(), c.Param("orderID"), req.ModeratorDiscordID, req.Action)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrOrderNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": "Order not found"})
+		case errors.Is(err, service.ErrNotModerator):
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		case errors.Is(err, service.ErrNoVODReview), errors.Is(err, service.ErrOrderAlreadyFulfilled),
+			errors.Is(err, service.ErrProductionNotFinished):
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to review VOD"})
+		}
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	"c500-core-go/internal/domain"
@@ -15,14 +16,23 @@ var (
 	ErrUnauthorizedSeller    = errors.New("user is not the seller of this order")
 	ErrOrderAlreadyFulfilled = errors.New("order is not in held status")
 	ErrStripePayoutFailed    = errors.New("failed to release funds via stripe")
+	ErrVODInReview           = errors.New("this order's VOD is already waiting for a moderator")
+	ErrNoVODReview           = errors.New("this order has no VOD waiting for review")
+	ErrUnknownCarrier        = errors.New("unsupported carrier, use USPS, UPS, FedEx, DHL, Canada Post or Royal Mail")
 )
 
+// ModerationReject sends a reviewed VOD back: the seller must submit a real one.
+// (Approving uses ModerationApprove, as for guestbook entries.)
+const ModerationReject = "reject"
+
 // OrderRepository defines DB operations needed for fulfillment.
 // (Implemented in internal/database/firestore.go)
 type OrderRepository interface {
 	GetOrderByID(ctx context.Context, orderID string) (*domain.Order, error)
 	// UpdateOrderFulfillment performs a partial update on specific fields.
 	UpdateOrderFulfillment(ctx context.Context, orderID string, updates map[string]interface{}) error
+	// ListOrdersInVODReview returns orders whose VOD waits for a moderator, oldest first.
+	ListOrdersInVODReview(ctx context.Context, limit int) ([]domain.Order, error)
 }
 
 // Use existing BuilderRepository interface to fetch seller's Stripe ID.
@@ -38,19 +48,47 @@ type StripeIntegration interface {
 	ReleaseEscrowFunds(ctx context.Context, paymentIntentID, destinationStripeAcctID string, amountCents int64) error
 }
 
-// FulfillmentService is the concrete implementation.
+// FulfillmentService defines the methods the fulfillment handler uses.
+type FulfillmentService interface {
+	// FulfillOrderWithShipping marks the order shipped and reports whether
+	// escrow was released (commissions also need their VOD).
+	FulfillOrderWithShipping(ctx context.Context, orderID, sellerDiscordID, tracking, carrier string) (released bool, err error)
+	// FulfillOrderWithVOD releases escrow if the VOD checks out. Otherwise the
+	// order goes to the VOD review queue and the reason is returned.
+	FulfillOrderWithVOD(ctx context.Context, orderID, sellerDiscordID, vodURL string) (reviewReason string, err error)
+
+	VODReviewQueue(ctx context.Context, moderatorID string, limit int) ([]domain.Order, error)
+	ReviewVOD(ctx context.Context, orderID, moderatorID, action string) error
+}
+
+// fulfillmentService is the concrete implementation.
 type fulfillmentService struct {
 	orderRepo   OrderRepository
 	builderRepo BuilderRepository
+	dropRepo    DropRepository
+	production  ProductionRepository
 	stripe      StripeIntegration
+	videos      TwitchVideos
+	moderators  map[string]bool
 }
 
 // NewFulfillmentService constructor.
-func NewFulfillmentService(or OrderRepository, br BuilderRepository, si StripeIntegration) *fulfillmentService {
+// moderatorIDs are the Discord IDs allowed to work the VOD review queue.
+func NewFulfillmentService(or OrderRepository, br BuilderRepository, dr DropRepository, pr ProductionRepository, si StripeIntegration, tv TwitchVideos, moderatorIDs []string) *fulfillmentService {
+	mods := make(map[string]bool, len(moderatorIDs))
+	for _, id := range moderatorIDs {
+		if id = strings.TrimSpace(id); id != "" {
+			mods[id] = true
+		}
+	}
 	return &fulfillmentService{
 		orderRepo:   or,
 		builderRepo: br,
+		dropRepo:    dr,
+		production:  pr,
 		stripe:      si,
+		videos:      tv,
+		moderators:  mods,
 	}
 }
 
@@ -58,44 +96,183 @@ func NewFulfillmentService(or OrderRepository, br BuilderRepository, si StripeIn
 // Business Logic
 // ==========================================
 
-// FulfillOrderWithShipping handles RTS orders.
-func (s *fulfillmentService) FulfillOrderWithShipping(ctx context.Context, orderID, sellerDiscordID, tracking, carrier string) error {
-	// Prepare the specific updates for shipping
-	updates := map[string]interface{}{
-		"tracking_number": tracking,
-		"carrier":         carrier,
+// FulfillOrderWithShipping records the tracking number and moves the order to
+// shipped. RTS orders are paid out straight away; commissions once their build
+// VOD checks out (FulfillOrderWithVOD).
+func (s *fulfillmentService) FulfillOrderWithShipping(ctx context.Context, orderID, sellerDiscordID, tracking, carrier string) (bool, error) {
+	// The number must be one the carrier could have issued: escrow is released on it.
+	c, ok := domain.LookupCarrier(carrier)
+	if !ok {
+		return false, ErrUnknownCarrier
+	}
+	tracking, err := c.ValidateTracking(tracking)
+	if err != nil {
+		return false, err
+	}
+
+	order, err := s.heldOrder(ctx, orderID, sellerDiscordID)
+	if err != nil {
+		return false, err
 	}
-	// Call shared logic helper
-	return s.processFulfillment(ctx, orderID, sellerDiscordID, updates)
+
+	// Already shipped means an earlier payout failed: just retry the payout.
+	if !order.ProductionStatus.Final() {
+		if !order.CanAdvanceTo(domain.ProductionShipped) {
+			return false, ErrInvalidProductionStep
+		}
+		// Prepare the specific updates for shipping
+		now := time.Now().UTC()
+		updates := map[string]interface{}{
+			"tracking_number": tracking,
+			"carrier":         c.Code,
+			// Queues the shipment for the tracking poller.
+			"tracking_checked_at": now,
+		}
+		ev := domain.ProductionEvent{
+			Status: domain.ProductionShipped,
+			At:     now,
+			Note:   "Shipped with " + c.Name + ", tracking " + tracking,
+			By:     sellerDiscordID,
+		}
+		if err := s.production.AdvanceProduction(ctx, orderID, order.ProductionStatus, ev, updates); err != nil {
+			return false, err
+		}
+		order.ProductionStatus = domain.ProductionShipped
+	}
+
+	if order.DropType == domain.DropTypeCommission {
+		return false, nil
+	}
+	return true, s.release(ctx, order, map[string]interface{}{})
 }
 
-// FulfillOrderWithVOD handles Commission orders.
-func (s *fulfillmentService) FulfillOrderWithVOD(ctx context.Context, orderID, sellerDiscordID, vodURL string) error {
+// FulfillOrderWithVOD handles Commission orders. The VOD must be a past
+// broadcast from the seller's linked Twitch channel, streamed after the order
+// was paid; anything else waits for a moderator instead of paying out.
+func (s *fulfillmentService) FulfillOrderWithVOD(ctx context.Context, orderID, sellerDiscordID, vodURL string) (string, error) {
+	order, err := s.heldOrder(ctx, orderID, sellerDiscordID)
+	if err != nil {
+		return "", err
+	}
+	if !order.ProductionStatus.Final() {
+		return "", ErrProductionNotFinished
+	}
+	if order.VODReview != nil && order.VODReview.Status == domain.VODReviewPending {
+		return "", ErrVODInReview
+	}
+	seller, err := s.builderRepo.GetByID(ctx, order.SellerDiscordID)
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch seller: %w", err)
+	}
+
+	if reason := s.checkVOD(ctx, order, seller, vodURL); reason != "" {
+		now := time.Now().UTC()
+		err := s.orderRepo.UpdateOrderFulfillment(ctx, orderID, map[string]interface{}{
+			"vod_link": vodURL,
+			"vod_review": domain.VODReview{
+				Status:      domain.VODReviewPending,
+				Reason:      reason,
+				SubmittedAt: now,
+			},
+			"updated_at": now,
+		})
+		if err != nil {
+			return "", fmt.Errorf("failed to queue order %s for vod review: %w", orderID, err)
+		}
+		return reason, nil
+	}
+
 	// Prepare the specific updates for VOD
 	updates := map[string]interface{}{
 		"vod_link": vodURL,
 	}
-	// Call shared logic helper
-	return s.processFulfillment(ctx, orderID, sellerDiscordID, updates)
+	return "", s.release(ctx, order, updates)
+}
+
+// ==========================================
+// VOD Review (moderators)
+// ==========================================
+
+// VODReviewQueue returns orders whose VOD couldn't be verified, oldest first.
+func (s *fulfillmentService) VODReviewQueue(ctx context.Context, moderatorID string, limit int) ([]domain.Order, error) {
+	if !s.moderators[moderatorID] {
+		return nil, ErrNotModerator
+	}
+	return s.orderRepo.ListOrdersInVODReview(ctx, limit)
 }
 
-// processFulfillment is the shared Core Logic responsible for checks and payouts.
-func (s *fulfillmentService) processFulfillment(ctx context.Context, orderID, requestingSellerID string, specificUpdates map[string]interface{}) error {
+// ReviewVOD resolves a queued VOD: approve releases escrow, reject clears the
+// link so the seller can submit another.
+// moderatorID is only as good as its caller: the handler is internal-only,
+// so it comes from the bot, which read it off the Discord interaction.
+func (s *fulfillmentService) ReviewVOD(ctx context.Context, orderID, moderatorID, action string) error {
+	if !s.moderators[moderatorID] {
+		return ErrNotModerator
+	}
+	order, err := s.orderRepo.GetOrderByID(ctx, orderID)
+	if err != nil {
+		return fmt.Errorf("failed to fetch order: %w", ErrOrderNotFound)
+	}
+	if order.VODReview == nil || order.VODReview.Status != domain.VODReviewPending {
+		return ErrNoVODReview
+	}
+	if order.EscrowStatus != domain.EscrowHeld {
+		return ErrOrderAlreadyFulfilled
+	}
+
+	now := time.Now().UTC()
+	updates := map[string]interface{}{
+		"vod_review.reviewed_by": moderatorID,
+		"vod_review.reviewed_at": now,
+	}
+	switch action {
+	case ModerationApprove:
+		updates["vod_review.status"] = domain.VODReviewApproved
+		return s.release(ctx, order, updates)
+	case ModerationReject:
+		updates["vod_review.status"] = domain.VODReviewRejected
+		updates["vod_link"] = ""
+		updates["updated_at"] = now
+		return s.orderRepo.UpdateOrderFulfillment(ctx, orderID, updates)
+	default:
+		return fmt.Errorf("unknown moderation action %q", action)
+	}
+}
+
+// heldOrder fetches an order the requester sells and whose funds are still held.
+func (s *fulfillmentService) heldOrder(ctx context.Context, orderID, requestingSellerID string) (*domain.Order, error) {
 	// 1. Fetch the Order
 	order, err := s.orderRepo.GetOrderByID(ctx, orderID)
 	if err != nil {
 		// Assume repo maps DB not found to standard error, or check here.
-		return fmt.Errorf("failed to fetch order: %w", ErrOrderNotFound)
+		return nil, fmt.Errorf("failed to fetch order: %w", ErrOrderNotFound)
 	}
 
 	// 2. SECURITY CHECK: Is the requester actually the seller?
 	if order.SellerDiscordID != requestingSellerID {
-		return ErrUnauthorizedSeller
+		return nil, ErrUnauthorizedSeller
 	}
 
 	// 3. STATE CHECK: Is the money actually held right now?
 	if order.EscrowStatus != domain.EscrowHeld {
-		return ErrOrderAlreadyFulfilled
+		return nil, ErrOrderAlreadyFulfilled
+	}
+
+	// 4. Commissions ship and pay out differently; older orders don't say.
+	if err := resolveDropType(ctx, s.dropRepo, order); err != nil {
+		return nil, err
+	}
+	return order, nil
+}
+
+// release is the shared Core Logic responsible for payouts: it pays the
+// seller out and records the fulfillment.
+func (s *fulfillmentService) release(ctx context.Context, order *domain.Order, specificUpdates map[string]interface{}) error {
+	orderID := order.ID
+
+	// Escrow follows production: nothing is paid out before the order ships.
+	if !order.ProductionStatus.Final() {
+		return ErrProductionNotFinished
 	}
 
 	// 4. Fetch Seller's Stripe destination account ID.
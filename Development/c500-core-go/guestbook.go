@@ -0,0 +1,41 @@
+package domain
+
+import "time"
+
+// GuestbookStatus controls whether an entry shows on the builder's page.
+type GuestbookStatus string
+
+const (
+	GuestbookVisible GuestbookStatus = "visible" // Shown on the profile page
+	GuestbookHidden  GuestbookStatus = "hidden"  // Hidden by the builder (can be unhidden)
+	GuestbookRemoved GuestbookStatus = "removed" // Taken down by a moderator
+)
+
+// GuestbookEntry is one message left on a builder's profile page.
+// Entries are signed from Discord, so every author is a real Discord account.
+// Stored in the top-level "guestbook_entries" collection, so the moderation
+// queue can look across every builder at once.
+type GuestbookEntry struct {
+	ID        string `json:"id" firestore:"id"`
+	BuilderID string `json:"builder_id" firestore:"builder_id"`
+
+	AuthorDiscordID string `json:"author_discord_id" firestore:"author_discord_id"`
+	// AuthorName is their Discord display name when they signed (cached for the page).
+	AuthorName string `json:"author_name" firestore:"author_name"`
+
+	// Message is plain text; the web server escapes it when rendering.
+	Message string          `json:"message" firestore:"message"`
+	Status  GuestbookStatus `json:"status" firestore:"status"`
+
+	// Reports. ReportedBy is kept so one person can't report the same entry twice,
+	// but is never sent to clients.
+	ReportCount      int      `json:"report_count" firestore:"report_count"`
+	ReportedBy       []string `json:"-" firestore:"reported_by,omitempty"`
+	LastReportReason string   `json:"last_report_reason,omitempty" firestore:"last_report_reason,omitempty"`
+	// NeedsReview puts the entry in the moderation queue until a moderator acts.
+	NeedsReview bool   `json:"needs_review" firestore:"needs_review"`
+	ModeratedBy string `json:"moderated_by,omitempty" firestore:"moderated_by,omitempty"`
+
+	CreatedAt time.Time `json:"created_at" firestore:"created_at"`
+	UpdatedAt time.Time `json:"updated_at" firestore:"updated_at"`
+}
@@ -0,0 +1,219 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"c500-core-go/internal/service"
+)
+
+// GuestbookHandler serves profile guestbooks: signing from the bot, the
+// public list for the web server, owner controls and the moderation queue.
+type GuestbookHandler struct {
+	guestbookService service.GuestbookService
+}
+
+// NewGuestbookHandler is the constructor.
+func NewGuestbookHandler(gs service.GuestbookService) *GuestbookHandler {
+	return &GuestbookHandler{
+		guestbookService: gs,
+	}
+}
+
+// RegisterRoutes connects the HTTP URLs to the handler functions.
+// This is called in main.go.
+func (h *GuestbookHandler) RegisterRoutes(router *gin.RouterGroup) {
+	router.GET("/builders/:discordID/guestbook", h.List)
+}
+
+// RegisterInternalRoutes connects the routes that act as a Discord user (the
+// signer, the page's owner, a moderator). They trust the IDs in the request,
+// so main.go puts them behind the internal API key.
+func (h *GuestbookHandler) RegisterInternalRoutes(router *gin.RouterGroup) {
+	router.POST("/builders/:discordID/guestbook", h.Sign)
+	// The owner's own view, hidden entries included.
+	router.GET("/builders/:discordID/guestbook/all", h.ListAll)
+
+	router.POST("/guestbook/:entryID/hide", h.SetHidden)
+	router.DELETE("/guestbook/:entryID", h.Delete)
+	router.POST("/guestbook/:entryID/report", h.Report)
+
+	router.GET("/moderation/guestbook", h.ModerationQueue)
+	router.POST("/moderation/guestbook/:entryID", h.Moderate)
+}
+
+// ==========================================
+// Request/Response Structs (Data Contracts)
+// ==========================================
+
+// signGuestbookRequest is sent by the bot. The author is whoever ran the command,
+// so entries are always tied to a real Discord account.
+type signGuestbookRequest struct {
+	AuthorDiscordID string `json:"author_discord_id" binding:"required"`
+	AuthorName      string `json:"author_name" binding:"required"`
+	Message         string `json:"message" binding:"required"`
+}
+
+type hideEntryRequest struct {
+	OwnerDiscordID string `json:"owner_discord_id" binding:"required"`
+	Hidden         bool   `json:"hidden"`
+}
+
+type deleteEntryRequest struct {
+	OwnerDiscordID string `json:"owner_discord_id" binding:"required"`
+}
+
+type reportEntryRequest struct {
+	ReporterDiscordID string `json:"reporter_discord_id" binding:"required"`
+	Reason            string `json:"reason"`
+}
+
+type moderateEntryRequest struct {
+	ModeratorDiscordID string `json:"moderator_discord_id" binding:"required"`
+	Action             string `json:"action" binding:"required,oneof=approve remove"`
+}
+
+// ==========================================
+// Handler Functions
+// ==========================================
+
+// List handles GET /api/v1/builders/:discordID/guestbook?limit=
+// It's public, so it only ever returns visible entries.
+func (h *GuestbookHandler) List(c *gin.Context) {
+	h.list(c, false)
+}
+
+// ListAll handles GET /api/v1/builders/:discordID/guestbook/all?limit=
+// The bot calls it when the builder is looking at their own page.
+func (h *GuestbookHandler) ListAll(c *gin.Context) {
+	h.list(c, true)
+}
+
+func (h *GuestbookHandler) list(c *gin.Context, includeHidden bool) {
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if err != nil || limit < 1 || limit > 100 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "limit must be between 1 and 100"})
+		return
+	}
+
+	entries, err := h.guestbookService.List(c.Request.Context(), c.Param("discordID"), includeHidden, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load guestbook"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"entries": entries})
+}
+
+// Sign handles POST /api/v1/builders/:discordID/guestbook
+func (h *GuestbookHandler) Sign(c *gin.Context) {
+	var req signGuestbookRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	entry, err := h.guestbookService.Sign(c.Request.Context(), c.Param("discordID"), req.AuthorDiscordID, req.AuthorName, req.Message)
+	if err != nil {
+		h.writeError(c, err, "Failed to sign guestbook")
+		return
+	}
+	c.JSON(http.StatusCreated, entry)
+}
+
+// SetHidden handles POST /api/v1/guestbook/:entryID/hide
+func (h *GuestbookHandler) SetHidden(c *gin.Context) {
+	var req hideEntryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.guestbookService.SetHidden(c.Request.Context(), c.Param("entryID"), req.OwnerDiscordID, req.Hidden); err != nil {
+		h.writeError(c, err, "Failed to update entry")
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// Delete handles DELETE /api/v1/guestbook/:entryID
+func (h *GuestbookHandler) Delete(c *gin.Context) {
+	var req deleteEntryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.guestbookService.Delete(c.Request.Context(), c.Param("entryID"), req.OwnerDiscordID); err != nil {
+		h.writeError(c, err, "Failed to delete entry")
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// Report handles POST /api/v1/guestbook/:entryID/report
+func (h *GuestbookHandler) Report(c *gin.Context) {
+	var req reportEntryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.guestbookService.Report(c.Request.Context(), c.Param("entryID"), req.ReporterDiscordID, req.Reason); err != nil {
+		h.writeError(c, err, "Failed to report entry")
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// ModerationQueue handles GET /api/v1/moderation/guestbook?moderator_id=&limit=
+func (h *GuestbookHandler) ModerationQueue(c *gin.Context) {
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if err != nil || limit < 1 || limit > 100 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "limit must be between 1 and 100"})
+		return
+	}
+
+	entries, err := h.guestbookService.ModerationQueue(c.Request.Context(), c.Query("moderator_id"), limit)
+	if err != nil {
+		h.writeError(c, err, "Failed to load moderation queue")
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"entries": entries})
+}
+
+// Moderate handles POST /api/v1/moderation/guestbook/:entryID
+func (h *GuestbookHandler) Moderate(c *gin.Context) {
+	var req moderateEntryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.guestbookService.Moderate(c.Request.Context(), c.Param("entryID"), req.ModeratorDiscordID, req.Action); err != nil {
+		h.writeError(c, err, "Failed to moderate entry")
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// writeError maps service errors to HTTP status codes.
+func (h *GuestbookHandler) writeError(c *gin.Context, err error, fallback string) {
+	switch {
+	case errors.Is(err, service.ErrGuestbookEntryNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrBuilderNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Builder not found"})
+	case errors.Is(err, service.ErrGuestbookInvalid), errors.Is(err, service.ErrGuestbookRejected),
+		errors.Is(err, service.ErrGuestbookOwnPage):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrGuestbookRateLimited):
+		c.JSON(http.StatusTooManyRequests, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrNotEntryOwner), errors.Is(err, service.ErrNotModerator):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": fallback})
+	}
+}
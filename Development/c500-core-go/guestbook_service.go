@@ -0,0 +1,292 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"c500-core-go/internal/domain"
+)
+
+var (
+	ErrGuestbookEntryNotFound = errors.New("guestbook entry not found")
+	ErrGuestbookInvalid       = errors.New("guestbook messages must be 1-500 characters")
+	ErrGuestbookRejected      = errors.New("guestbook messages can't contain links or profanity")
+	ErrGuestbookRateLimited   = errors.New("you're signing guestbooks too quickly, try again later")
+	ErrGuestbookOwnPage       = errors.New("you can't sign your own guestbook")
+	ErrNotEntryOwner          = errors.New("only the builder whose guestbook this is can do that")
+	ErrNotModerator           = errors.New("only C500 moderators can do that")
+)
+
+const (
+	maxGuestbookMessage = 500
+
+	// One entry per builder per day per author, and a few entries an hour overall.
+	guestbookPerBuilderCooldown = 24 * time.Hour
+	guestbookHourlyLimit        = 5
+)
+
+// Moderation actions for reported entries.
+const (
+	ModerationApprove = "approve" // Keep the entry, clear it from the queue
+	ModerationRemove  = "remove"  // Take the entry down
+)
+
+// GuestbookRepository defines DB operations for guestbook entries.
+// Implemented in internal/database/firestore.go
+type GuestbookRepository interface {
+	CreateGuestbookEntry(ctx context.Context, entry *domain.GuestbookEntry) error
+	GetGuestbookEntry(ctx context.Context, entryID string) (*domain.GuestbookEntry, error)
+	// ListGuestbookEntries returns a builder's entries with one of the given statuses, newest first.
+	ListGuestbookEntries(ctx context.Context, builderID string, statuses []domain.GuestbookStatus, limit int) ([]domain.GuestbookEntry, error)
+	ListGuestbookEntriesByAuthorSince(ctx context.Context, authorID string, since time.Time) ([]domain.GuestbookEntry, error)
+	UpdateGuestbookEntry(ctx context.Context, entryID string, updates map[string]interface{}) error
+	DeleteGuestbookEntry(ctx context.Context, entryID string) error
+	// ReportGuestbookEntry records a report once per reporter and flags the entry for review.
+	ReportGuestbookEntry(ctx context.Context, entryID, reporterID, reason string) error
+	// ListReportedGuestbookEntries returns entries awaiting review, oldest first.
+	ListReportedGuestbookEntries(ctx context.Context, limit int) ([]domain.GuestbookEntry, error)
+}
+
+// GuestbookService defines the methods handlers use for profile guestbooks.
+type GuestbookService interface {
+	Sign(ctx context.Context, builderID, authorID, authorName, message string) (*domain.GuestbookEntry, error)
+	List(ctx context.Context, builderID string, includeHidden bool, limit int) ([]domain.GuestbookEntry, error)
+	SetHidden(ctx context.Context, entryID, ownerID string, hidden bool) error
+	Delete(ctx context.Context, entryID, ownerID string) error
+	Report(ctx context.Context, entryID, reporterID, reason string) error
+	ModerationQueue(ctx context.Context, moderatorID string, limit int) ([]domain.GuestbookEntry, error)
+	Moderate(ctx context.Context, entryID, moderatorID, action string) error
+}
+
+// guestbookService is the concrete implementation.
+type guestbookService struct {
+	entries    GuestbookRepository
+	builders   BuilderRepository
+	moderators map[string]bool
+}
+
+// NewGuestbookService constructor used in main.go.
+// moderatorIDs are the Discord IDs allowed to work the moderation queue.
+func NewGuestbookService(gr GuestbookRepository, br BuilderRepository, moderatorIDs []string) *guestbookService {
+	mods := make(map[string]bool, len(moderatorIDs))
+	for _, id := range moderatorIDs {
+		if id = strings.TrimSpace(id); id != "" {
+			mods[id] = true
+		}
+	}
+	return &guestbookService{
+		entries:    gr,
+		builders:   br,
+		moderators: mods,
+	}
+}
+
+// ==========================================
+// Business Logic
+// ==========================================
+
+// Sign adds an entry to a builder's guestbook.
+func (s *guestbookService) Sign(ctx context.Context, builderID, authorID, authorName, message string) (*domain.GuestbookEntry, error) {
+	// 1. Validate inputs.
+	message = strings.TrimSpace(message)
+	if message == "" || utf8.RuneCountInString(message) > maxGuestbookMessage {
+		return nil, ErrGuestbookInvalid
+	}
+	if builderID == authorID {
+		return nil, ErrGuestbookOwnPage
+	}
+	if !guestbookMessageAllowed(message) {
+		return nil, ErrGuestbookRejected
+	}
+	if _, err := s.builders.GetByID(ctx, builderID); err != nil {
+		return nil, err
+	}
+
+	// 2. Rate limit by author.
+	now := time.Now().UTC()
+	recent, err := s.entries.ListGuestbookEntriesByAuthorSince(ctx, authorID, now.Add(-guestbookPerBuilderCooldown))
+	if err != nil {
+		return nil, fmt.Errorf("failed to check guestbook rate limit: %w", err)
+	}
+	lastHour := 0
+	for _, e := range recent {
+		if e.BuilderID == builderID {
+			return nil, ErrGuestbookRateLimited
+		}
+		if now.Sub(e.CreatedAt) < time.Hour {
+			lastHour++
+		}
+	}
+	if lastHour >= guestbookHourlyLimit {
+		return nil, ErrGuestbookRateLimited
+	}
+
+	// 3. Save.
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return nil, fmt.Errorf("failed to generate entry id: %w", err)
+	}
+	entry := &domain.GuestbookEntry{
+		ID:              hex.EncodeToString(b),
+		BuilderID:       builderID,
+		AuthorDiscordID: authorID,
+		AuthorName:      authorName,
+		Message:         message,
+		Status:          domain.GuestbookVisible,
+		CreatedAt:       now,
+		UpdatedAt:       now,
+	}
+	if err := s.entries.CreateGuestbookEntry(ctx, entry); err != nil {
+		return nil, fmt.Errorf("failed to save guestbook entry: %w", err)
+	}
+	return entry, nil
+}
+
+// List returns a builder's guestbook, newest first. The public page only
+// sees visible entries; the builder also sees the ones they hid.
+func (s *guestbookService) List(ctx context.Context, builderID string, includeHidden bool, limit int) ([]domain.GuestbookEntry, error) {
+	statuses := []domain.GuestbookStatus{domain.GuestbookVisible}
+	if includeHidden {
+		statuses = append(statuses, domain.GuestbookHidden)
+	}
+	return s.entries.ListGuestbookEntries(ctx, builderID, statuses, limit)
+}
+
+// SetHidden lets the builder hide (or unhide) an entry on their own page.
+// Entries taken down by a moderator stay down.
+func (s *guestbookService) SetHidden(ctx context.Context, entryID, ownerID string, hidden bool) error {
+	entry, err := s.ownedEntry(ctx, entryID, ownerID)
+	if err != nil {
+		return err
+	}
+	if entry.Status == domain.GuestbookRemoved {
+		return ErrGuestbookEntryNotFound
+	}
+
+	status := domain.GuestbookVisible
+	if hidden {
+		status = domain.GuestbookHidden
+	}
+	return s.entries.UpdateGuestbookEntry(ctx, entryID, map[string]interface{}{
+		"status":     status,
+		"updated_at": time.Now().UTC(),
+	})
+}
+
+// Delete lets the builder permanently remove an entry from their page.
+func (s *guestbookService) Delete(ctx context.Context, entryID, ownerID string) error {
+	if _, err := s.ownedEntry(ctx, entryID, ownerID); err != nil {
+		return err
+	}
+	return s.entries.DeleteGuestbookEntry(ctx, entryID)
+}
+
+// Report flags an entry for the moderation queue. Anyone can report.
+func (s *guestbookService) Report(ctx context.Context, entryID, reporterID, reason string) error {
+	reason = strings.TrimSpace(reason)
+	if r := []rune(reason); len(r) > 200 {
+		reason = string(r[:200])
+	}
+	return s.entries.ReportGuestbookEntry(ctx, entryID, reporterID, reason)
+}
+
+// ModerationQueue returns reported entries, oldest first.
+func (s *guestbookService) ModerationQueue(ctx context.Context, moderatorID string, limit int) ([]domain.GuestbookEntry, error) {
+	if !s.moderators[moderatorID] {
+		return nil, ErrNotModerator
+	}
+	return s.entries.ListReportedGuestbookEntries(ctx, limit)
+}
+
+// Moderate resolves a reported entry: approve keeps it, remove takes it down.
+func (s *guestbookService) Moderate(ctx context.Context, entryID, moderatorID, action string) error {
+	if !s.moderators[moderatorID] {
+		return ErrNotModerator
+	}
+	if _, err := s.entries.GetGuestbookEntry(ctx, entryID); err != nil {
+		return err
+	}
+
+	updates := map[string]interface{}{
+		"needs_review": false,
+		"moderated_by": moderatorID,
+		"updated_at":   time.Now().UTC(),
+	}
+	switch action {
+	case ModerationApprove:
+	case ModerationRemove:
+		updates["status"] = domain.GuestbookRemoved
+	default:
+		return fmt.Errorf("unknown moderation action %q", action)
+	}
+	return s.entries.UpdateGuestbookEntry(ctx, entryID, updates)
+}
+
+// ownedEntry fetches an entry and checks it's on ownerID's guestbook.
+func (s *guestbookService) ownedEntry(ctx context.Context, entryID, ownerID string) (*domain.GuestbookEntry, error) {
+	entry, err := s.entries.GetGuestbookEntry(ctx, entryID)
+	if err != nil {
+		return nil, err
+	}
+	if entry.BuilderID != ownerID {
+		return nil, ErrNotEntryOwner
+	}
+	return entry, nil
+}
+
+// ==========================================
+// Content Filter
+// ==========================================
+
+// guestbookLinkRe catches URLs, bare domains, Discord invites and markdown links.
+// Guestbooks are for messages, not for advertising other shops.
+var guestbookLinkRe = regexp.MustCompile(`(?i)(https?://|www\.|discord\.gg|\]\(|` +
+	`\b[a-z0-9-]+\s*(\.|\(dot\)|\[dot\])\s*(com|net|org|gg|io|ly|me|co|xyz|shop|store|app|link|ru|tk)\b)`)
+
+// guestbookBlockedWords are matched as whole words after undoing common
+// letter substitutions. The list is short on purpose; reports catch the rest.
+var guestbookBlockedWords = map[string]bool{
+	"fuck": true, "fucking": true, "fucker": true, "shit": true, "bitch": true,
+	"cunt": true, "dick": true, "cock": true, "pussy": true, "whore": true,
+	"slut": true, "asshole": true, "bastard": true, "retard": true, "kys": true,
+}
+
+var leetReplacer = strings.NewReplacer("0", "o", "1", "i", "3", "e", "4", "a", "5", "s", "7", "t", "@", "a", "$", "s", "!", "i")
+
+// guestbookMessageAllowed runs the link and profanity filter.
+func guestbookMessageAllowed(message string) bool {
+	lower := strings.ToLower(message)
+	if guestbookLinkRe.MatchString(lower) || strings.Contains(lower, "@everyone") || strings.Contains(lower, "@here") {
+		return false
+	}
+
+	words := strings.FieldsFunc(leetReplacer.Replace(lower), func(r rune) bool {
+		return !(r >= 'a' && r <= 'z')
+	})
+	for _, w := range words {
+		if guestbookBlockedWords[w] || guestbookBlockedWords[squeezeRepeats(w)] {
+			return false
+		}
+	}
+	return true
+}
+
+// squeezeRepeats collapses runs of the same letter ("fuuuck" -> "fuck").
+func squeezeRepeats(w string) string {
+	var b strings.Builder
+	var last rune
+	for _, r := range w {
+		if r != last {
+			b.WriteRune(r)
+		}
+		last = r
+	}
+	return b.String()
+}
@@ -0,0 +1,78 @@
+package domain
+
+import "time"
+
+// GuildSubscription is a partner server's opt-in to receive drop announcements.
+// C500 is federated: one drop can appear in every partner server's #marketplace.
+// Stored in the "guild_subscriptions" collection, keyed by GuildID.
+type GuildSubscription struct {
+	// GuildID is the Discord server snowflake (also the document ID).
+	GuildID string `json:"guild_id" firestore:"guild_id"`
+
+	// ChannelID is where drop embeds are posted in that server.
+	ChannelID string `json:"channel_id" firestore:"channel_id" binding:"required"`
+
+	// AllDrops subscribes to everything. When false, a drop must match the filters below.
+	AllDrops bool `json:"all_drops" firestore:"all_drops"`
+
+	// Filters (only used when AllDrops is false). Empty slices mean "any".
+	// A drop matches if its seller is in BuilderIDs (when set) AND its type is in DropTypes (when set).
+	BuilderIDs []string   `json:"builder_ids,omitempty" firestore:"builder_ids,omitempty"`
+	DropTypes  []DropType `json:"drop_types,omitempty" firestore:"drop_types,omitempty"`
+
+	// UpdatedBy is the Discord ID of the server admin who last changed the subscription.
+	UpdatedBy string    `json:"updated_by" firestore:"updated_by"`
+	CreatedAt time.Time `json:"created_at" firestore:"created_at"`
+	UpdatedAt time.Time `json:"updated_at" firestore:"updated_at"`
+}
+
+// Matches decides whether a drop should be posted to this guild.
+func (g *GuildSubscription) Matches(drop *Drop) bool {
+	if g.AllDrops {
+		return true
+	}
+	// A filtered subscription with no filters at all would match everything;
+	// treat it as "nothing yet" so an admin doesn't get flooded by accident.
+	if len(g.BuilderIDs) == 0 && len(g.DropTypes) == 0 {
+		return false
+	}
+	if len(g.BuilderIDs) > 0 && !containsString(g.BuilderIDs, drop.SellerDiscordID) {
+		return false
+	}
+	if len(g.DropTypes) > 0 {
+		found := false
+		for _, t := range g.DropTypes {
+			found = found || t == drop.Type
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// DropPost records one Discord message that shows a drop.
+// The bot reports the message ID back after posting, so the message can be
+// edited later (e.g. stamped "SOLD"). Stored in "drop_posts", keyed by "{dropID}_{guildID}".
+type DropPost struct {
+	DropID    string    `json:"drop_id" firestore:"drop_id"`
+	GuildID   string    `json:"guild_id" firestore:"guild_id"`
+	ChannelID string    `json:"channel_id" firestore:"channel_id"`
+	MessageID string    `json:"message_id,omitempty" firestore:"message_id,omitempty"` // Empty until the bot confirms
+	PostedAt  time.Time `json:"posted_at,omitempty" firestore:"posted_at,omitempty"`
+	CreatedAt time.Time `json:"created_at" firestore:"created_at"`
+}
+
+// DropPostID builds the document ID for a drop/guild pair.
+func DropPostID(dropID, guildID string) string {
+	return dropID + "_" + guildID
+}
+
+func containsString(values []string, v string) bool {
+	for _, candidate := range values {
+		if candidate == v {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,132 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"c500-core-go/internal/domain"
+	"c500-core-go/internal/service"
+)
+
+// GuildHandler exposes the partner-server subscription registry to the Go bot.
+type GuildHandler struct {
+	fanoutService service.FanoutService
+}
+
+// NewGuildHandler is the constructor.
+func NewGuildHandler(fs service.FanoutService) *GuildHandler {
+	return &GuildHandler{
+		fanoutService: fs,
+	}
+}
+
+// RegisterRoutes connects the HTTP URLs to the handler functions.
+// This is called in main.go.
+func (h *GuildHandler) RegisterRoutes(router *gin.RouterGroup) {
+	router.GET("/guilds/:guildID/subscription", h.GetSubscription)
+}
+
+// RegisterInternalRoutes connects the routes only the bots may call: they
+// redirect a guild's fan-out or tell the embed-sync worker what to edit.
+// main.go puts them behind the internal API key.
+func (h *GuildHandler) RegisterInternalRoutes(router *gin.RouterGroup) {
+	router.PUT("/guilds/:guildID/subscription", h.PutSubscription)
+	router.DELETE("/guilds/:guildID/subscription", h.DeleteSubscription)
+
+	// Bots call this after posting a drop embed anywhere, so it can be kept in sync.
+	router.POST("/drops/:dropID/posts", h.RecordPost)
+}
+
+// ==========================================
+// Request/Response Structs (Data Contracts)
+// ==========================================
+
+// putSubscriptionRequest is sent by the bot when a server admin opts in.
+type putSubscriptionRequest struct {
+	ChannelID  string            `json:"channel_id" binding:"required"`
+	AllDrops   bool              `json:"all_drops"`
+	BuilderIDs []string          `json:"builder_ids"`
+	DropTypes  []domain.DropType `json:"drop_types" binding:"dive,oneof=commission ready_to_ship"`
+	// UpdatedBy is the admin's Discord ID. The bot checks Manage Server before calling.
+	UpdatedBy string `json:"updated_by" binding:"required"`
+}
+
+// recordPostRequest is sent by a bot after posting a drop embed in a guild.
+type recordPostRequest struct {
+	GuildID   string `json:"guild_id" binding:"required"`
+	ChannelID string `json:"channel_id" binding:"required"`
+	MessageID string `json:"message_id" binding:"required"`
+}
+
+// ==========================================
+// Handler Functions
+// ==========================================
+
+// GetSubscription handles GET /api/v1/guilds/:guildID/subscription
+func (h *GuildHandler) GetSubscription(c *gin.Context) {
+	sub, err := h.fanoutService.GetSubscription(c.Request.Context(), c.Param("guildID"))
+	if err != nil {
+		if errors.Is(err, service.ErrSubscriptionNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "This server is not subscribed to C500 drops"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load subscription"})
+		return
+	}
+	c.JSON(http.StatusOK, sub)
+}
+
+// PutSubscription handles PUT /api/v1/guilds/:guildID/subscription
+func (h *GuildHandler) PutSubscription(c *gin.Context) {
+	var req putSubscriptionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	sub, err := h.fanoutService.Subscribe(c.Request.Context(), domain.GuildSubscription{
+		GuildID:    c.Param("guildID"),
+		ChannelID:  req.ChannelID,
+		AllDrops:   req.AllDrops,
+		BuilderIDs: req.BuilderIDs,
+		DropTypes:  req.DropTypes,
+		UpdatedBy:  req.UpdatedBy,
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save subscription"})
+		return
+	}
+	c.JSON(http.StatusOK, sub)
+}
+
+// DeleteSubscription handles DELETE /api/v1/guilds/:guildID/subscription
+func (h *GuildHandler) DeleteSubscription(c *gin.Context) {
+	if err := h.fanoutService.Unsubscribe(c.Request.Context(), c.Param("guildID")); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to remove subscription"})
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// RecordPost handles POST /api/v1/drops/:dropID/posts
+func (h *GuildHandler) RecordPost(c *gin.Context) {
+	var req recordPostRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	err := h.fanoutService.RecordPost(c.Request.Context(), domain.DropPost{
+		DropID:    c.Param("dropID"),
+		GuildID:   req.GuildID,
+		ChannelID: req.ChannelID,
+		MessageID: req.MessageID,
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to record post"})
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,176 @@
+package media
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"net/http"
+
+	"golang.org/x/image/draw"
+	"golang.org/x/image/webp"
+)
+
+// MaxUploadBytes caps a single image upload. Phone photos are ~3-8MB.
+const MaxUploadBytes = 15 << 20 // 15 MB
+
+// maxPixels protects against "decompression bombs": tiny files that declare
+// enormous dimensions and would exhaust memory when decoded.
+const maxPixels = 50_000_000
+
+var (
+	ErrUnsupportedType = errors.New("unsupported image type (use JPEG, PNG, GIF or WebP)")
+	ErrTooLarge        = errors.New("image is too large")
+	ErrCorruptImage    = errors.New("image could not be decoded")
+)
+
+// allowedTypes maps sniffed content types to decoders.
+// We trust the BYTES, never the filename or the client's Content-Type header.
+var allowedTypes = map[string]func(io.Reader) (image.Image, error){
+	"image/jpeg": jpeg.Decode,
+	"image/png":  png.Decode,
+	"image/gif":  gif.Decode, // Only the first frame is kept.
+	"image/webp": webp.Decode,
+}
+
+// Rendition is one encoded output size.
+type Rendition struct {
+	Name        string
+	Data        []byte
+	ContentType string
+	Width       int
+	Height      int
+}
+
+// VariantSpec describes how to produce one output size.
+type VariantSpec struct {
+	Name     string
+	MaxWidth int
+	Square   bool // Center-crop to a square before resizing (thumbnails)
+}
+
+// Process validates an upload and produces every requested variant.
+//
+// Stripping EXIF/GPS is a side effect of how this works: we fully decode the
+// pixels and re-encode them from scratch, so NO metadata from the original file
+// (location, camera serial, embedded thumbnails) can survive into what we store.
+// JPEG orientation is the one EXIF tag we'd normally honor; sellers re-rotating
+// a sideways photo is a much smaller harm than leaking their home address.
+func Process(data []byte, variants []VariantSpec) ([]Rendition, error) {
+	if len(data) > MaxUploadBytes {
+		return nil, ErrTooLarge
+	}
+
+	// 1. Sniff the real content type from the first 512 bytes.
+	contentType := http.DetectContentType(data)
+	decode, ok := allowedTypes[contentType]
+	if !ok {
+		return nil, fmt.Errorf("%w: got %s", ErrUnsupportedType, contentType)
+	}
+
+	// 2. Check dimensions BEFORE decoding the full image.
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrCorruptImage, err)
+	}
+	if cfg.Width*cfg.Height > maxPixels {
+		return nil, fmt.Errorf("%w: %dx%d exceeds pixel limit", ErrTooLarge, cfg.Width, cfg.Height)
+	}
+
+	// 3. Decode the pixels (this is the step that discards all metadata).
+	src, err := decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrCorruptImage, err)
+	}
+
+	// 4. Render each variant.
+	out := make([]Rendition, 0, len(variants))
+	for _, v := range variants {
+		img := src
+		if v.Square {
+			img = cropSquare(img)
+		}
+		img = resizeToWidth(img, v.MaxWidth)
+
+		encoded, ct, err := encode(img)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode %s variant: %w", v.Name, err)
+		}
+		b := img.Bounds()
+		out = append(out, Rendition{
+			Name:        v.Name,
+			Data:        encoded,
+			ContentType: ct,
+			Width:       b.Dx(),
+			Height:      b.Dy(),
+		})
+	}
+	return out, nil
+}
+
+// resizeToWidth scales down (never up) to maxWidth, keeping the aspect ratio.
+func resizeToWidth(src image.Image, maxWidth int) image.Image {
+	b := src.Bounds()
+	if maxWidth <= 0 || b.Dx() <= maxWidth {
+		return src
+	}
+	height := b.Dy() * maxWidth / b.Dx()
+	dst := image.NewRGBA(image.Rect(0, 0, maxWidth, height))
+	draw.CatmullRom.Scale(dst, dst.Bounds(), src, b, draw.Over, nil)
+	return dst
+}
+
+// cropSquare takes the largest centered square.
+func cropSquare(src image.Image) image.Image {
+	b := src.Bounds()
+	side := b.Dx()
+	if b.Dy() < side {
+		side = b.Dy()
+	}
+	x0 := b.Min.X + (b.Dx()-side)/2
+	y0 := b.Min.Y + (b.Dy()-side)/2
+	dst := image.NewRGBA(image.Rect(0, 0, side, side))
+	draw.Draw(dst, dst.Bounds(), src, image.Point{X: x0, Y: y0}, draw.Src)
+	return dst
+}
+
+// encode writes PNG when the image has transparency (so cutouts keep their alpha)
+// and JPEG otherwise (much smaller for photos).
+func encode(img image.Image) ([]byte, string, error) {
+	var buf bytes.Buffer
+	if hasAlpha(img) {
+		if err := png.Encode(&buf, img); err != nil {
+			return nil, "", err
+		}
+		return buf.Bytes(), "image/png", nil
+	}
+	if err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: 85}); err != nil {
+		return nil, "", err
+	}
+	return buf.Bytes(), "image/jpeg", nil
+}
+
+// hasAlpha samples the image for any non-opaque pixel.
+func hasAlpha(img image.Image) bool {
+	if _, ok := img.(*image.YCbCr); ok {
+		return false // JPEG-decoded images are always opaque
+	}
+	b := img.Bounds()
+	step := 1 + b.Dx()*b.Dy()/10000 // Sample ~10k pixels at most
+	i := 0
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			if i%step == 0 {
+				if _, _, _, a := img.At(x, y).RGBA(); a != 0xffff {
+					return true
+				}
+			}
+			i++
+		}
+	}
+	return false
+}
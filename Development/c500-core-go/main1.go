@@ -2,17 +2,29 @@ package main
 
 import (
 	"context"
+	"crypto/rand"
 	"log"
 	"net/http"
 	"os"
+	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/stripe/stripe-go/v74"
 
 	"c500-core-go/internal/database"
+	"c500-core-go/internal/events"
+	"c500-core-go/internal/scheduler"
+	"c500-core-go/internal/sanitize"
+	"c500-core-go/internal/search"
+	"c500-core-go/internal/storage"
 	stripeintegration "c500-core-go/internal/integrations/stripe"
+	trackingintegration "c500-core-go/internal/integrations/tracking"
+	twitchintegration "c500-core-go/internal/integrations/twitch"
 	"c500-core-go/internal/service"
+	"c500-core-go/internal/transport/handlers"
 	transport "c500-core-go/internal/transport/http"
+	"c500-core-go/internal/transport/middleware"
 )
 
 func main() {
@@ -30,6 +42,36 @@ func main() {
 	if port == "" {
 		port = "8080" // Default for Cloud Run
 	}
+	previewSecret := os.Getenv("PROFILE_PREVIEW_SECRET")
+	if previewSecret == "" {
+		// Fine for local dev: preview links just stop working after a restart.
+		log.Println("PROFILE_PREVIEW_SECRET not set, using a random per-process secret")
+		previewSecret = rand.Text()
+	}
+	orderLinkSecret := os.Getenv("ORDER_LINK_SECRET")
+	if orderLinkSecret == "" {
+		// Fine for local dev: order page links in buyers' DMs stop working after a restart.
+		log.Println("ORDER_LINK_SECRET not set, using a random per-process secret")
+		orderLinkSecret = rand.Text()
+	}
+
+	twitchStateSecret := os.Getenv("TWITCH_STATE_SECRET")
+	if twitchStateSecret == "" {
+		// Fine for local dev: Twitch link URLs just stop working after a restart.
+		log.Println("TWITCH_STATE_SECRET not set, using a random per-process secret")
+		twitchStateSecret = rand.Text()
+	}
+	eventSubSecret := os.Getenv("TWITCH_EVENTSUB_SECRET")
+	if eventSubSecret == "" {
+		// Fine for local dev: stream subscriptions made before a restart stop verifying.
+		log.Println("TWITCH_EVENTSUB_SECRET not set, using a random per-process secret")
+		eventSubSecret = rand.Text()
+	}
+
+	if os.Getenv("INTERNAL_API_KEY") == "" {
+		// The bot and web server send it as X-Internal-API-Key; see internal/transport/middleware.
+		log.Println("INTERNAL_API_KEY not set, internal routes (profile edits, moderation) will refuse every request")
+	}
 
 	ctx := context.Background()
 
@@ -47,6 +89,55 @@ func main() {
 	// Create our wrapper client.
 	stripeClient := stripeintegration.NewClient()
 
+	// Twitch account linking and stream events. TWITCH_AUTH_URL/TWITCH_API_URL
+	// default to Twitch itself; point them at cmd/twitchstandin to run locally.
+	twitchClient := twitchintegration.NewClient(twitchintegration.Config{
+		ClientID:            os.Getenv("TWITCH_CLIENT_ID"),
+		ClientSecret:        os.Getenv("TWITCH_CLIENT_SECRET"),
+		RedirectURL:         os.Getenv("TWITCH_REDIRECT_URL"), // e.g. https://core.c500.store/twitch/callback
+		AuthBaseURL:         os.Getenv("TWITCH_AUTH_URL"),
+		APIBaseURL:          os.Getenv("TWITCH_API_URL"),
+		EventSubCallbackURL: os.Getenv("TWITCH_EVENTSUB_CALLBACK_URL"), // e.g. https://core.c500.store/webhooks/twitch
+		EventSubSecret:      eventSubSecret,
+	})
+
+	// Shipment tracking. Only the local fake exists so far (TRACKING_PROVIDER=fake,
+	// TRACKING_FAKE_STEP between simulated scans); a carrier or aggregator client
+	// just has to implement scheduler.TrackingClient. Without one, nothing is polled.
+	var trackingClient scheduler.TrackingClient
+	trackingInterval := 15 * time.Minute
+	switch provider := os.Getenv("TRACKING_PROVIDER"); provider {
+	case "fake":
+		step, err := time.ParseDuration(os.Getenv("TRACKING_FAKE_STEP"))
+		if err != nil || step <= 0 {
+			step = 2 * time.Minute
+		}
+		trackingClient = trackingintegration.NewFakeClient(step)
+		trackingInterval = step
+	case "":
+		log.Println("TRACKING_PROVIDER not set, shipments won't be tracked")
+	default:
+		log.Fatalf("Unknown TRACKING_PROVIDER %q", provider)
+	}
+
+	// Media storage: local disk for development, a Cloud Storage bucket in production.
+	// MEDIA_BASE_URL is the public prefix for stored images (our CDN domain in prod).
+	var blobStore storage.BlobStore
+	mediaDir := ""
+	if bucket := os.Getenv("MEDIA_BUCKET"); bucket != "" {
+		log.Printf("Using Cloud Storage bucket %s for media...", bucket)
+		blobStore, err = storage.NewGCSStore(ctx, bucket, os.Getenv("MEDIA_BASE_URL"))
+	} else {
+		mediaDir = "./media"
+		log.Printf("MEDIA_BUCKET not set, storing media on local disk in %s...", mediaDir)
+		blobStore, err = storage.NewLocalStore(mediaDir, "http://localhost:"+port+"/media")
+	}
+	if err != nil {
+		log.Fatalf("Failed to init media storage: %v", err)
+	}
+	// Profile HTML/CSS may only load files from the builders' hosted asset libraries.
+	sanitize.SetAssetBaseURL(blobStore.PublicURL(service.ProfileAssetKeyPrefix))
+
 	// =====================================================================
 	// 3. THE WIRING PHASE (Dependency Injection)
 	// We build the layers from the bottom up: Repo -> Service -> Handler
@@ -61,19 +152,80 @@ func main() {
 	// --- Layer 2: Services (Middle) ---
 	// Inject repos/clients into services.
 	// Note: We reuse firestoreClient wherever a Repo interface is needed.
+	// Services publish events (drop live, countdowns...) to a Firestore outbox
+	// that the Discord bots poll through GET /api/v1/events.
+	eventOutbox := events.NewOutbox(firestoreClient.SDK())
+
 	builderService := service.NewBuilderService(firestoreClient, stripeClient)
-	// (We haven't written DropService yet, but it would go here)
-	// dropService := service.NewDropService(firestoreClient, builderService)
-	checkoutService := service.NewCheckoutService(firestoreClient, firestoreClient, stripeClient)
-	fulfillmentService := service.NewFulfillmentService(firestoreClient, firestoreClient, stripeClient)
+	assetService := service.NewAssetService(firestoreClient, blobStore)
+	// Every profile save is kept as a revision; previewSecret signs the
+	// preview links for unpublished revisions. ADMIN_DISCORD_IDS (comma
+	// separated) may edit any builder's page, everyone else only their own.
+	adminIDs := strings.Split(os.Getenv("ADMIN_DISCORD_IDS"), ",")
+	profileService := service.NewProfileService(firestoreClient, firestoreClient, previewSecret, adminIDs)
+	slugService := service.NewSlugService(firestoreClient, firestoreClient)
+	profileAssetService := service.NewProfileAssetService(firestoreClient, firestoreClient, blobStore)
+	// MODERATOR_DISCORD_IDS (comma separated) may work the guestbook and VOD review queues.
+	moderatorIDs := strings.Split(os.Getenv("MODERATOR_DISCORD_IDS"), ",")
+	guestbookService := service.NewGuestbookService(firestoreClient, firestoreClient, moderatorIDs)
+	twitchService := service.NewTwitchService(firestoreClient, firestoreClient, twitchClient, twitchStateSecret)
+	fanoutService := service.NewFanoutService(firestoreClient, firestoreClient, eventOutbox)
+	// Going live on Twitch starts production of the builder's queued commissions.
+	streamService := service.NewStreamService(firestoreClient, firestoreClient, firestoreClient, eventOutbox)
+	// Page views are rolled up in memory and flushed to Firestore by Run below.
+	analyticsService := service.NewAnalyticsService(firestoreClient, firestoreClient)
+	// Every drop status change goes through these wrappers so posted Discord embeds
+	// stay in sync and checkout starts/conversions are counted.
+	syncedDropRepo := service.NewStatusSyncingDropRepository(service.NewAnalyticsDropRepository(firestoreClient, analyticsService), fanoutService)
+	dropService := service.NewDropService(firestoreClient, syncedDropRepo, firestoreClient, assetService, fanoutService)
+	// Buyers who miss a drop can wait for it: they're DMed when an expired
+	// checkout releases it or its scheduled go-live arrives.
+	waitlistService := service.NewWaitlistService(firestoreClient, firestoreClient, eventOutbox)
+	checkoutService := service.NewCheckoutService(service.NewWaitlistDropRepository(syncedDropRepo, waitlistService), firestoreClient, stripeClient)
+	// Live fulfillments are checked against the builder's Twitch VODs before payout,
+	// and escrow is only released once the order has shipped.
+	fulfillmentService := service.NewFulfillmentService(firestoreClient, firestoreClient, firestoreClient, firestoreClient, stripeClient, twitchClient, moderatorIDs)
+	// Sellers' progress updates are DMed to buyers, with a signed link to their order page.
+	productionService := service.NewProductionService(firestoreClient, firestoreClient, firestoreClient, firestoreClient, assetService, eventOutbox, orderLinkSecret, os.Getenv("WEB_BASE_URL"))
+
+	// --- Background Workers ---
+	// The scheduler announces go-lives and countdowns through the fan-out service,
+	// so every subscribed guild hears about them.
+	dropScheduler := scheduler.NewDropScheduler(firestoreClient, service.NewWaitlistAnnouncer(fanoutService, waitlistService), 30*time.Second)
+	go dropScheduler.Run(ctx)
+	go analyticsService.Run(ctx)
+	// Shipped orders get their carrier's transit events and are marked delivered.
+	if trackingClient != nil {
+		trackingPoller := scheduler.NewTrackingPoller(firestoreClient, trackingClient, trackingInterval)
+		go trackingPoller.Run(ctx)
+	}
+
+	// --- Search Index ---
+	// Held in memory and kept in sync with the "drops" collection by a Firestore
+	// snapshot listener running in the background for the life of the process.
+	searchIndex := search.NewMemoryIndex()
+	go search.SyncFromFirestore(ctx, firestoreClient.SDK(), searchIndex)
 
 	// --- Layer 1: Handlers (Top) ---
 	// Inject services into HTTP handlers.
-	// builderHandler := transport.NewBuilderHandler(builderService) (Not written yet)
-	// dropHandler := transport.NewDropHandler(dropService) (Not written yet)
+	builderHandler := transport.NewBuilderHandler(builderService)
+	dropHandler := handlers.NewDropHandler(dropService)
 	checkoutHandler := transport.NewCheckoutHandler(checkoutService)
+	waitlistHandler := transport.NewWaitlistHandler(waitlistService)
 	webhookHandler := transport.NewWebhookHandler(checkoutService, stripeWebhookSecret)
 	fulfillmentHandler := transport.NewFulfillmentHandler(fulfillmentService)
+	productionHandler := transport.NewProductionHandler(productionService)
+	searchHandler := transport.NewSearchHandler(searchIndex)
+	assetHandler := transport.NewAssetHandler(assetService)
+	eventsHandler := transport.NewEventsHandler(eventOutbox)
+	guildHandler := transport.NewGuildHandler(fanoutService)
+	profileHandler := transport.NewProfileHandler(profileService, slugService, os.Getenv("WEB_BASE_URL"))
+	slugHandler := transport.NewSlugHandler(slugService)
+	profileAssetHandler := transport.NewProfileAssetHandler(profileAssetService)
+	guestbookHandler := transport.NewGuestbookHandler(guestbookService)
+	analyticsHandler := transport.NewAnalyticsHandler(analyticsService)
+	twitchHandler := transport.NewTwitchHandler(twitchService)
+	eventSubHandler := transport.NewEventSubHandler(streamService, eventSubSecret)
 
 
 	// 4. Setup HTTP Server (Gin Router)
@@ -93,15 +245,51 @@ func main() {
 	apiV1 := router.Group("/api/v1")
 	{
 		// Tell each handler to register its own paths under this group.
-		// builderHandler.RegisterRoutes(apiV1)
-		// dropHandler.RegisterRoutes(apiV1)
 		checkoutHandler.RegisterRoutes(apiV1)
-		fulfillmentHandler.RegisterRoutes(apiV1)
+		productionHandler.RegisterRoutes(apiV1)
+		searchHandler.RegisterRoutes(apiV1)
+		guildHandler.RegisterRoutes(apiV1)
+		profileHandler.RegisterRoutes(apiV1)
+		slugHandler.RegisterRoutes(apiV1)
+		profileAssetHandler.RegisterRoutes(apiV1)
+		guestbookHandler.RegisterRoutes(apiV1)
+		analyticsHandler.RegisterRoutes(apiV1)
+	}
+
+	// Same paths, but only for our own services: these trust the Discord IDs
+	// in the request, which only the bot can vouch for.
+	internal := apiV1.Group("", middleware.InternalAuthCheck())
+	{
+		profileHandler.RegisterInternalRoutes(internal)
+		guestbookHandler.RegisterInternalRoutes(internal)
+		// Shipping, VOD submissions and VOD reviews all release escrow.
+		fulfillmentHandler.RegisterRoutes(internal)
+		// Uploads are filed under the owner_discord_id the bot sends.
+		assetHandler.RegisterRoutes(internal)
+		// The outbox carries buyer IDs and signed order links.
+		eventsHandler.RegisterRoutes(internal)
+		guildHandler.RegisterInternalRoutes(internal)
+		slugHandler.RegisterInternalRoutes(internal)
+		profileAssetHandler.RegisterInternalRoutes(internal)
+		analyticsHandler.RegisterInternalRoutes(internal)
+		twitchHandler.RegisterRoutes(internal)
+		productionHandler.RegisterInternalRoutes(internal)
+		builderHandler.RegisterRoutes(internal)
+		dropHandler.RegisterRoutes(internal)
+		waitlistHandler.RegisterRoutes(internal)
+	}
+
+	// In local development, serve uploaded media straight from disk.
+	if mediaDir != "" {
+		router.Static("/media", mediaDir)
 	}
 
 	// Register Webhook Route (usually at root level or distinct path)
 	// Note: It's NOT under /api/v1 because it's an external callback, not our internal API.
 	webhookHandler.RegisterRoutes(router.Group("/"))
+	// Twitch sends the builder's browser here after they log in to link their channel.
+	twitchHandler.RegisterCallbackRoutes(router.Group("/"))
+	eventSubHandler.RegisterRoutes(router.Group("/"))
 
 
 	// 5. Start the Engine
@@ -0,0 +1,143 @@
+package main
+
+// cmd/migrate is a standalone CLI that rewrites old Firestore documents in place.
+//
+// Usage:
+//   go run ./cmd/migrate -project my-gcp-project status
+//   go run ./cmd/migrate -project my-gcp-project -dry-run up
+//   go run ./cmd/migrate -project my-gcp-project up
+//   go run ./cmd/migrate -project my-gcp-project -only 0001_drops_canonical_schema reset
+//
+// Always run with -dry-run first and read the output before touching production.
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	"cloud.google.com/go/firestore"
+
+	"c500-core-go/internal/migrations"
+	"c500-core-go/internal/sanitize"
+	"c500-core-go/internal/service"
+)
+
+func main() {
+	// 1. Parse flags. The project defaults to the same env var the API server uses.
+	projectID := flag.String("project", os.Getenv("GCP_PROJECT_ID"), "GCP project that owns the Firestore database")
+	dryRun := flag.Bool("dry-run", false, "Print the changes that would be made without writing anything")
+	batchSize := flag.Int("batch", 200, "Number of documents to read and write per batch (max 500)")
+	only := flag.String("only", "", "Run (or reset) a single migration ID instead of all of them")
+	mediaBaseURL := flag.String("media-base-url", os.Getenv("MEDIA_BASE_URL"), "Public media URL, so profile re-sanitizing keeps hosted profile assets")
+	flag.Parse()
+
+	if *projectID == "" {
+		log.Fatal("Missing -project flag (or GCP_PROJECT_ID environment variable)")
+	}
+	if *batchSize > 500 {
+		// Firestore rejects write batches with more than 500 operations.
+		log.Fatal("-batch must be 500 or less")
+	}
+	if *mediaBaseURL != "" {
+		// Must match the API server (see main.go), or hosted assets would be stripped too.
+		sanitize.SetAssetBaseURL(strings.TrimRight(*mediaBaseURL, "/") + "/" + service.ProfileAssetKeyPrefix)
+	}
+
+	command := flag.Arg(0)
+	if command == "" {
+		command = "status"
+	}
+
+	ctx := context.Background()
+
+	// 2. Connect to Firestore directly; the CLI doesn't need the repository layer.
+	client, err := firestore.NewClient(ctx, *projectID)
+	if err != nil {
+		log.Fatalf("Failed to init firestore: %v", err)
+	}
+	defer client.Close()
+
+	// 3. Pick which migrations to work with.
+	selected := migrations.All()
+	if *only != "" {
+		selected = filterMigrations(selected, *only)
+		if len(selected) == 0 {
+			log.Fatalf("Unknown migration ID %q", *only)
+		}
+	}
+
+	runner := migrations.NewRunner(client, *batchSize, *dryRun)
+
+	// Without the media URL the sanitizer treats hosted profile assets as
+	// offsite and would strip every one of them.
+	if command == "up" && *mediaBaseURL == "" {
+		states, err := runner.Status(ctx, selected)
+		if err != nil {
+			log.Fatalf("Failed to read migration status: %v", err)
+		}
+		for _, m := range selected {
+			if m.SanitizesProfiles && !states[m.ID].Completed {
+				log.Fatalf("Migration %s re-sanitizes profiles: set -media-base-url (or MEDIA_BASE_URL) to the API server's MEDIA_BASE_URL", m.ID)
+			}
+		}
+	}
+
+	// 4. Dispatch the subcommand.
+	switch command {
+	case "status":
+		states, err := runner.Status(ctx, selected)
+		if err != nil {
+			log.Fatalf("Failed to read migration status: %v", err)
+		}
+		for _, m := range selected {
+			state, ok := states[m.ID]
+			switch {
+			case !ok:
+				fmt.Printf("%-40s pending\n", m.ID)
+			case state.Completed:
+				fmt.Printf("%-40s completed (%d migrated)\n", m.ID, state.Migrated)
+			default:
+				fmt.Printf("%-40s in progress (cursor=%q, %d migrated)\n", m.ID, state.Cursor, state.Migrated)
+			}
+		}
+
+	case "up":
+		if *dryRun {
+			log.Println("DRY RUN: no documents will be written.")
+		}
+		reports, err := runner.Run(ctx, selected)
+		for _, r := range reports {
+			fmt.Printf("%-40s scanned=%d migrated=%d skipped=%d failed=%d\n",
+				r.MigrationID, r.Scanned, r.Migrated, r.Skipped, r.Failed)
+		}
+		if err != nil {
+			// Progress is checkpointed, so simply re-running "up" resumes from here.
+			log.Fatalf("Migration stopped: %v", err)
+		}
+
+	case "reset":
+		if *only == "" {
+			log.Fatal("reset requires -only <migration ID>")
+		}
+		if err := runner.Reset(ctx, *only); err != nil {
+			log.Fatalf("Failed to reset: %v", err)
+		}
+		fmt.Printf("Reset progress for %s\n", *only)
+
+	default:
+		log.Fatalf("Unknown command %q (expected status, up or reset)", command)
+	}
+}
+
+// filterMigrations returns just the migration with the given ID.
+func filterMigrations(all []migrations.Migration, id string) []migrations.Migration {
+	for _, m := range all {
+		if m.ID == id {
+			return []migrations.Migration{m}
+		}
+	}
+	return nil
+}
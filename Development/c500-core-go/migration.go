@@ -0,0 +1,73 @@
+package migrations
+
+import (
+	"fmt"
+	"sort"
+
+	"cloud.google.com/go/firestore"
+)
+
+// Migration describes one versioned, in-place rewrite of a single collection.
+// Each migration moves documents from FromVersion to ToVersion by returning the
+// exact field updates to apply. Documents already at (or past) ToVersion are skipped,
+// which makes every migration safe to re-run.
+type Migration struct {
+	// ID is the unique, sortable name of the migration, e.g. "0001_drops_price_in_cents".
+	// It is also the document ID used to store progress in the migrations collection.
+	ID string
+
+	// Collection is the Firestore collection this migration rewrites ("drops", "users", ...).
+	Collection string
+
+	// FromVersion and ToVersion are the schema_version values before and after.
+	FromVersion int
+	ToVersion   int
+
+	// Transform inspects the raw document data and returns the updates that bring it
+	// to ToVersion. It must NOT perform any I/O; the Runner handles reads and writes
+	// so that dry-runs are guaranteed to be side-effect free.
+	// The Runner adds the "schema_version" update itself.
+	Transform func(data map[string]interface{}) ([]firestore.Update, error)
+
+	// SanitizesProfiles marks migrations that re-run the profile sanitizer.
+	// They need sanitize.SetAssetBaseURL first, or every hosted image is stripped.
+	SanitizesProfiles bool
+}
+
+// registry holds every known migration. Migrations register themselves from
+// init() functions in this package so that the CLI only has to call All().
+var registry []Migration
+
+// Register adds a migration to the global registry.
+// It panics on duplicate IDs because that is always a programming mistake.
+func Register(m Migration) {
+	for _, existing := range registry {
+		if existing.ID == m.ID {
+			panic(fmt.Sprintf("migrations: duplicate migration ID %q", m.ID))
+		}
+	}
+	registry = append(registry, m)
+}
+
+// All returns every registered migration sorted by ID, which is the order they must run in.
+func All() []Migration {
+	out := make([]Migration, len(registry))
+	copy(out, registry)
+	sort.Slice(out, func(i, j int) bool { return out[i].ID < out[j].ID })
+	return out
+}
+
+// schemaVersionOf reads the "schema_version" field from raw document data.
+// Documents written before versioning existed have no field and count as version 0.
+func schemaVersionOf(data map[string]interface{}) int {
+	switch v := data["schema_version"].(type) {
+	case int64:
+		return int(v)
+	case int:
+		return v
+	case float64:
+		return int(v)
+	default:
+		return 0
+	}
+}
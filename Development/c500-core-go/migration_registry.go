@@ -0,0 +1,187 @@
+package migrations
+
+import (
+	"fmt"
+	"math"
+
+	"cloud.google.com/go/firestore"
+
+	"c500-core-go/internal/domain"
+	"c500-core-go/internal/sanitize"
+)
+
+// =================================================================
+// Registered Migrations
+// Add new migrations at the bottom with the next sequence number.
+// NEVER edit a migration that has already run in production; write a new one.
+// =================================================================
+
+func init() {
+	Register(Migration{
+		ID:          "0001_drops_canonical_schema",
+		Collection:  "drops",
+		FromVersion: 0,
+		ToVersion:   domain.DropSchemaVersion,
+		Transform:   migrateDropToCanonical,
+	})
+
+	Register(Migration{
+		ID:          "0002_users_schema_version",
+		Collection:  "users",
+		FromVersion: 0,
+		ToVersion:   1,
+		Transform:   migrateUserToV1,
+	})
+
+	Register(Migration{
+		ID:          "0003_orders_schema_version",
+		Collection:  "orders",
+		FromVersion: 0,
+		ToVersion:   1,
+		Transform:   migrateOrderToV1,
+	})
+
+	Register(Migration{
+		ID:                "0004_users_sanitize_profiles",
+		Collection:        "users",
+		FromVersion:       1,
+		ToVersion:         2,
+		Transform:         sanitizeUserProfile,
+		SanitizesProfiles: true,
+	})
+
+	Register(Migration{
+		ID:                "0005_users_hosted_profile_assets",
+		Collection:        "users",
+		FromVersion:       2,
+		ToVersion:         domain.BuilderSchemaVersion,
+		Transform:         sanitizeUserProfile, // The sanitizer now drops offsite src and url()
+		SanitizesProfiles: true,
+	})
+
+	Register(Migration{
+		ID:          "0006_orders_production_status",
+		Collection:  "orders",
+		FromVersion: 1,
+		ToVersion:   domain.OrderSchemaVersion,
+		Transform:   migrateOrderProductionStatus,
+	})
+}
+
+// migrateDropToCanonical rewrites both legacy drop shapes into the canonical one:
+//   - float "price" (dollars)      -> int "price_in_cents"
+//   - single "image_url"           -> "image_urls" slice
+//   - status "pending_payment"     -> "pending"
+//   - type "rts"                   -> "ready_to_ship"
+func migrateDropToCanonical(data map[string]interface{}) ([]firestore.Update, error) {
+	var updates []firestore.Update
+
+	// 1. Price: dollars (float) -> cents (int).
+	if raw, ok := data["price"]; ok {
+		if _, hasCents := data["price_in_cents"]; !hasCents {
+			dollars, ok := toFloat(raw)
+			if !ok {
+				return nil, fmt.Errorf("unexpected type %T for price", raw)
+			}
+			// Round instead of truncating so 449.99 becomes 44999, not 44998.
+			cents := int64(math.Round(dollars * 100))
+			updates = append(updates, firestore.Update{Path: "price_in_cents", Value: cents})
+		}
+		updates = append(updates, firestore.Update{Path: "price", Value: firestore.Delete})
+	}
+
+	// 2. Images: single URL -> slice of URLs.
+	if raw, ok := data["image_url"]; ok {
+		if _, hasSlice := data["image_urls"]; !hasSlice {
+			urls := []string{}
+			if url, ok := raw.(string); ok && url != "" {
+				urls = append(urls, url)
+			}
+			updates = append(updates, firestore.Update{Path: "image_urls", Value: urls})
+		}
+		updates = append(updates, firestore.Update{Path: "image_url", Value: firestore.Delete})
+	}
+
+	// 3. Status vocabulary.
+	if s, _ := data["status"].(string); s == "pending_payment" {
+		updates = append(updates, firestore.Update{Path: "status", Value: domain.StatusPending})
+	}
+
+	// 4. Type vocabulary.
+	if t, _ := data["type"].(string); t == "rts" {
+		updates = append(updates, firestore.Update{Path: "type", Value: domain.DropTypeRTS})
+	}
+
+	// 5. Documents from the very first schema never had updated_at.
+	if _, ok := data["updated_at"]; !ok {
+		if created, ok := data["created_at"]; ok {
+			updates = append(updates, firestore.Update{Path: "updated_at", Value: created})
+		}
+	}
+
+	return updates, nil
+}
+
+// migrateUserToV1 stamps the schema version and fills in the nested
+// profile_data map for users created before it was always initialized.
+func migrateUserToV1(data map[string]interface{}) ([]firestore.Update, error) {
+	var updates []firestore.Update
+	if _, ok := data["profile_data"]; !ok {
+		updates = append(updates, firestore.Update{Path: "profile_data", Value: domain.ProfileData{}})
+	}
+	if _, ok := data["is_verified_builder"]; !ok {
+		updates = append(updates, firestore.Update{Path: "is_verified_builder", Value: false})
+	}
+	return updates, nil
+}
+
+// sanitizeUserProfile re-runs stored profiles through the current sanitizer:
+// first for profiles saved before it existed (stored verbatim and rendered
+// unescaped), then to strip images and fonts hotlinked from other sites.
+func sanitizeUserProfile(data map[string]interface{}) ([]firestore.Update, error) {
+	profile, _ := data["profile_data"].(map[string]interface{})
+	rawHTML, _ := profile["html"].(string)
+	rawCSS, _ := profile["css"].(string)
+
+	return []firestore.Update{
+		{Path: "profile_data.html", Value: sanitize.HTML(rawHTML)},
+		{Path: "profile_data.css", Value: sanitize.CSS(rawCSS, sanitize.ProfileScope)},
+	}, nil
+}
+
+// migrateOrderToV1 stamps the schema version and backfills escrow_status
+// for orders written before the field existed.
+func migrateOrderToV1(data map[string]interface{}) ([]firestore.Update, error) {
+	var updates []firestore.Update
+	if _, ok := data["escrow_status"]; !ok {
+		updates = append(updates, firestore.Update{Path: "escrow_status", Value: domain.EscrowHeld})
+	}
+	return updates, nil
+}
+
+// migrateOrderProductionStatus queues unfinished orders for production. Their
+// drop_type stays empty (a migration only sees the one document); the
+// services look it up from the drop before applying the commission rules.
+func migrateOrderProductionStatus(data map[string]interface{}) ([]firestore.Update, error) {
+	if _, ok := data["production_status"]; ok {
+		return nil, nil
+	}
+	if data["escrow_status"] != string(domain.EscrowHeld) {
+		return nil, nil // Paid out or refunded: too late to track production.
+	}
+	return []firestore.Update{{Path: "production_status", Value: domain.ProductionQueued}}, nil
+}
+
+// toFloat normalizes the numeric types Firestore can hand back.
+func toFloat(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case float64:
+		return n, true
+	case int64:
+		return float64(n), true
+	case int:
+		return float64(n), true
+	default:
+		return 0, false
+	}
+}
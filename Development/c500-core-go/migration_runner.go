@@ -0,0 +1,381 @@
+package migrations
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"google.golang.org/api/iterator"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// migrationsCollection stores one progress document per migration ID.
+const migrationsCollection = "schema_migrations"
+
+// Progress is the checkpoint document stored at schema_migrations/{migrationID}.
+// The Cursor is the last document ID that was fully processed, so an interrupted
+// run picks up exactly where it stopped instead of starting over. Documents that
+// failed are behind the cursor, so they're kept in FailedIDs and retried first
+// on the next run; the migration isn't Completed until that list is empty.
+type Progress struct {
+	Cursor      string    `firestore:"cursor"`
+	FailedIDs   []string  `firestore:"failed_ids,omitempty"`
+	Migrated    int       `firestore:"migrated"`
+	Skipped     int       `firestore:"skipped"`
+	Completed   bool      `firestore:"completed"`
+	UpdatedAt   time.Time `firestore:"updated_at"`
+	CompletedAt time.Time `firestore:"completed_at,omitempty"`
+}
+
+// Report summarizes what a single migration did (or would do, in dry-run mode).
+type Report struct {
+	MigrationID string
+	Scanned     int
+	Migrated    int
+	Skipped     int
+	Failed      int
+}
+
+// Runner applies registered migrations against a Firestore project.
+type Runner struct {
+	client    *firestore.Client
+	batchSize int
+	dryRun    bool
+
+	// simulated holds, per collection, the migrations already dry-run in this
+	// Run. Nothing was written, so a chained migration (users 1 -> 2 after
+	// 0 -> 1) replays them on its in-memory copy of each document.
+	simulated map[string][]Migration
+}
+
+// NewRunner is the constructor used by cmd/migrate.
+// In dry-run mode nothing is written: neither documents nor progress checkpoints.
+func NewRunner(client *firestore.Client, batchSize int, dryRun bool) *Runner {
+	if batchSize <= 0 {
+		batchSize = 200
+	}
+	return &Runner{
+		client:    client,
+		batchSize: batchSize,
+		dryRun:    dryRun,
+	}
+}
+
+// Run executes the given migrations in order.
+// It stops at the first migration that reports failed documents, because later
+// migrations usually assume earlier ones finished.
+func (r *Runner) Run(ctx context.Context, migrations []Migration) ([]Report, error) {
+	var reports []Report
+	r.simulated = make(map[string][]Migration)
+	for _, m := range migrations {
+		report, err := r.runOne(ctx, m)
+		reports = append(reports, report)
+		if r.dryRun {
+			r.simulated[m.Collection] = append(r.simulated[m.Collection], m)
+		}
+		if err != nil {
+			return reports, fmt.Errorf("migration %s: %w", m.ID, err)
+		}
+		if report.Failed > 0 {
+			return reports, fmt.Errorf("migration %s: %d documents failed to migrate", m.ID, report.Failed)
+		}
+	}
+	return reports, nil
+}
+
+// runOne walks a single collection in document-ID order, one batch at a time.
+func (r *Runner) runOne(ctx context.Context, m Migration) (Report, error) {
+	report := Report{MigrationID: m.ID}
+
+	// 1. Load the checkpoint so we can resume an interrupted run.
+	state, err := r.loadProgress(ctx, m.ID)
+	if err != nil {
+		return report, err
+	}
+	if state.Completed {
+		log.Printf("[%s] already completed, skipping", m.ID)
+		return report, nil
+	}
+	if len(state.FailedIDs) > 0 {
+		log.Printf("[%s] retrying %d documents that failed last time", m.ID, len(state.FailedIDs))
+		if err := r.retryFailed(ctx, m, &state, &report); err != nil {
+			return report, err
+		}
+	}
+	if state.Cursor != "" {
+		log.Printf("[%s] resuming after document %q", m.ID, state.Cursor)
+	}
+
+	cursor := state.Cursor
+	for {
+		// 2. Build the next page query, ordered by document ID so the cursor is stable.
+		query := r.client.Collection(m.Collection).
+			OrderBy(firestore.DocumentID, firestore.Asc).
+			Limit(r.batchSize)
+		if cursor != "" {
+			query = query.StartAfter(cursor)
+		}
+
+		docs, err := query.Documents(ctx).GetAll()
+		if err != nil {
+			return report, fmt.Errorf("firestore query error: %w", err)
+		}
+		if len(docs) == 0 {
+			break // Finished the collection
+		}
+
+		// 3. Transform and write each document in the page.
+		batch := r.client.Batch()
+		pending, skipped := 0, 0
+		var failed []string
+		for _, doc := range docs {
+			report.Scanned++
+			migrated, err := r.migrateDoc(m, doc, batch)
+			if err != nil {
+				// Log and keep going; one bad document shouldn't block the whole collection.
+				// The cursor moves past it, so it's remembered for the next run.
+				log.Printf("[%s] FAILED %s: %v", m.ID, doc.Ref.ID, err)
+				report.Failed++
+				failed = append(failed, doc.Ref.ID)
+				continue
+			}
+			if migrated {
+				report.Migrated++
+				pending++
+			} else {
+				report.Skipped++
+				skipped++
+			}
+		}
+
+		cursor = docs[len(docs)-1].Ref.ID
+
+		// 4. Commit the page and checkpoint our position. Both are skipped in dry-run mode.
+		if !r.dryRun {
+			if pending > 0 {
+				if _, err := batch.Commit(ctx); err != nil {
+					return report, fmt.Errorf("failed to commit batch ending at %q: %w", cursor, err)
+				}
+			}
+			state.Cursor = cursor
+			state.FailedIDs = append(state.FailedIDs, failed...)
+			state.Migrated += pending
+			state.Skipped += skipped
+			if err := r.saveProgress(ctx, m.ID, state); err != nil {
+				return report, err
+			}
+		}
+
+		if len(docs) < r.batchSize {
+			break // Last page
+		}
+	}
+
+	// 5. Mark the migration as done so future runs skip it entirely.
+	// Any failed document, from this run or an earlier one, keeps it open.
+	if !r.dryRun && len(state.FailedIDs) == 0 && report.Failed == 0 {
+		state.Completed = true
+		state.CompletedAt = time.Now().UTC()
+		if err := r.saveProgress(ctx, m.ID, state); err != nil {
+			return report, err
+		}
+	}
+
+	return report, nil
+}
+
+// retryFailed re-reads the documents that failed on earlier runs and migrates
+// the ones that now succeed. Those still failing stay in state.FailedIDs;
+// deleted ones are dropped since there's nothing left to migrate.
+func (r *Runner) retryFailed(ctx context.Context, m Migration, state *Progress, report *Report) error {
+	var stillFailed []string
+	for start := 0; start < len(state.FailedIDs); start += r.batchSize {
+		end := min(start+r.batchSize, len(state.FailedIDs))
+		refs := make([]*firestore.DocumentRef, 0, end-start)
+		for _, id := range state.FailedIDs[start:end] {
+			refs = append(refs, r.client.Collection(m.Collection).Doc(id))
+		}
+		docs, err := r.client.GetAll(ctx, refs)
+		if err != nil {
+			return fmt.Errorf("failed to fetch previously failed documents: %w", err)
+		}
+
+		batch := r.client.Batch()
+		pending, skipped := 0, 0
+		for _, doc := range docs {
+			if !doc.Exists() {
+				continue
+			}
+			report.Scanned++
+			migrated, err := r.migrateDoc(m, doc, batch)
+			if err != nil {
+				log.Printf("[%s] FAILED again %s: %v", m.ID, doc.Ref.ID, err)
+				report.Failed++
+				stillFailed = append(stillFailed, doc.Ref.ID)
+				continue
+			}
+			if migrated {
+				report.Migrated++
+				pending++
+			} else {
+				report.Skipped++
+				skipped++
+			}
+		}
+
+		if !r.dryRun && pending > 0 {
+			if _, err := batch.Commit(ctx); err != nil {
+				return fmt.Errorf("failed to commit retried documents: %w", err)
+			}
+		}
+		state.Migrated += pending
+		state.Skipped += skipped
+	}
+
+	if r.dryRun {
+		return nil
+	}
+	state.FailedIDs = stillFailed
+	return r.saveProgress(ctx, m.ID, *state)
+}
+
+// migrateDoc decides whether a document needs rewriting and stages the update in the batch.
+// It returns false (and no error) for documents that are already up to date.
+func (r *Runner) migrateDoc(m Migration, doc *firestore.DocumentSnapshot, batch *firestore.WriteBatch) (bool, error) {
+	data := doc.Data()
+	if r.dryRun {
+		if err := r.simulateEarlier(m, data); err != nil {
+			return false, err
+		}
+	}
+
+	version := schemaVersionOf(data)
+	if version >= m.ToVersion {
+		return false, nil
+	}
+	if version < m.FromVersion {
+		return false, fmt.Errorf("document is at schema_version %d, expected %d", version, m.FromVersion)
+	}
+
+	updates, err := m.Transform(data)
+	if err != nil {
+		return false, err
+	}
+	updates = append(updates, firestore.Update{Path: "schema_version", Value: m.ToVersion})
+
+	if r.dryRun {
+		log.Printf("[%s] DRY RUN %s: would apply %d updates", m.ID, doc.Ref.ID, len(updates))
+		for _, u := range updates {
+			log.Printf("    %s = %v", u.Path, u.Value)
+		}
+		return true, nil
+	}
+
+	batch.Update(doc.Ref, updates)
+	return true, nil
+}
+
+// simulateEarlier applies the migrations dry-run before m in this Run to the
+// document's data, as if they had been written.
+func (r *Runner) simulateEarlier(m Migration, data map[string]interface{}) error {
+	for _, earlier := range r.simulated[m.Collection] {
+		version := schemaVersionOf(data)
+		if version < earlier.FromVersion || version >= earlier.ToVersion {
+			continue
+		}
+		updates, err := earlier.Transform(data)
+		if err != nil {
+			return fmt.Errorf("earlier migration %s: %w", earlier.ID, err)
+		}
+		for _, u := range updates {
+			applyUpdate(data, u)
+		}
+		data["schema_version"] = int64(earlier.ToVersion)
+	}
+	return nil
+}
+
+// applyUpdate sets (or deletes) a dotted field path in raw document data.
+func applyUpdate(data map[string]interface{}, u firestore.Update) {
+	parts := strings.Split(u.Path, ".")
+	for _, part := range parts[:len(parts)-1] {
+		child, ok := data[part].(map[string]interface{})
+		if !ok {
+			// Missing, or a struct value only Firestore would have flattened.
+			child = make(map[string]interface{})
+			data[part] = child
+		}
+		data = child
+	}
+	last := parts[len(parts)-1]
+	if u.Value == firestore.Delete {
+		delete(data, last)
+		return
+	}
+	data[last] = u.Value
+}
+
+// loadProgress fetches the checkpoint document; a missing document means "never started".
+func (r *Runner) loadProgress(ctx context.Context, migrationID string) (Progress, error) {
+	var state Progress
+	snap, err := r.client.Collection(migrationsCollection).Doc(migrationID).Get(ctx)
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			return state, nil
+		}
+		return state, fmt.Errorf("failed to load migration progress: %w", err)
+	}
+	if err := snap.DataTo(&state); err != nil {
+		return state, fmt.Errorf("failed to decode migration progress: %w", err)
+	}
+	return state, nil
+}
+
+// saveProgress overwrites the checkpoint document.
+func (r *Runner) saveProgress(ctx context.Context, migrationID string, state Progress) error {
+	state.UpdatedAt = time.Now().UTC()
+	_, err := r.client.Collection(migrationsCollection).Doc(migrationID).Set(ctx, state)
+	if err != nil {
+		return fmt.Errorf("failed to save migration progress: %w", err)
+	}
+	return nil
+}
+
+// Status returns the stored progress for every migration, for the CLI "status" command.
+func (r *Runner) Status(ctx context.Context, migrations []Migration) (map[string]Progress, error) {
+	out := make(map[string]Progress, len(migrations))
+	iter := r.client.Collection(migrationsCollection).Documents(ctx)
+	defer iter.Stop()
+	for {
+		doc, err := iter.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("firestore query iteration error: %w", err)
+		}
+		var state Progress
+		if err := doc.DataTo(&state); err != nil {
+			continue
+		}
+		out[doc.Ref.ID] = state
+	}
+	return out, nil
+}
+
+// Reset deletes the checkpoint for one migration so it can be re-run from the start.
+func (r *Runner) Reset(ctx context.Context, migrationID string) error {
+	if r.dryRun {
+		log.Printf("[%s] DRY RUN: would reset progress", migrationID)
+		return nil
+	}
+	_, err := r.client.Collection(migrationsCollection).Doc(migrationID).Delete(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to reset migration progress: %w", err)
+	}
+	return nil
+}
@@ -13,15 +13,86 @@ const (
 	EscrowRefunded EscrowStatus = "refunded" // Something went wrong, buyer got money back.
 )
 
+// ProductionStatus tracks the build itself, separately from the money (EscrowStatus).
+type ProductionStatus string
+
+const (
+	ProductionQueued       ProductionStatus = "queued"        // Paid, waiting for the builder to start.
+	ProductionPartsOrdered ProductionStatus = "parts_ordered" // The builder ordered parts for it.
+	ProductionInProduction ProductionStatus = "in_production" // The builder has started (e.g. went live on Twitch).
+	ProductionShipped      ProductionStatus = "shipped"       // Handed to the carrier, tracking number recorded.
+	ProductionDelivered    ProductionStatus = "delivered"     // Arrived at the buyer.
+)
+
+// productionSteps lists where each status may go next. Production only moves
+// forward; ordering parts is optional.
+var productionSteps = map[ProductionStatus][]ProductionStatus{
+	ProductionQueued:       {ProductionPartsOrdered, ProductionInProduction, ProductionShipped},
+	ProductionPartsOrdered: {ProductionInProduction},
+	ProductionInProduction: {ProductionShipped},
+	ProductionShipped:      {ProductionDelivered},
+}
+
+// Final reports whether the order has left the builder's hands. Escrow is
+// only released in a final status.
+func (s ProductionStatus) Final() bool {
+	return s == ProductionShipped || s == ProductionDelivered
+}
+
+// ProductionEvent is one timestamped step on an order's production timeline.
+type ProductionEvent struct {
+	Status ProductionStatus `json:"status" firestore:"status"`
+	At     time.Time        `json:"at" firestore:"at"`
+	// Note and PhotoURLs are optional, shown to the buyer.
+	Note      string   `json:"note,omitempty" firestore:"note,omitempty"`
+	PhotoURLs []string `json:"photo_urls,omitempty" firestore:"photo_urls,omitempty"`
+	// By is the Discord ID of who made the change, "" when automatic (e.g. Twitch).
+	By string `json:"by,omitempty" firestore:"by,omitempty"`
+	// Update marks a seller's progress update: Status is unchanged, Note and
+	// PhotoURLs are the news.
+	Update bool `json:"update,omitempty" firestore:"update,omitempty"`
+}
+
+// VODReviewStatus tracks a VOD link that failed automatic verification.
+type VODReviewStatus string
+
+const (
+	VODReviewPending  VODReviewStatus = "pending"  // Escrow stays held until a moderator looks at it.
+	VODReviewApproved VODReviewStatus = "approved" // A moderator accepted the VOD; funds released.
+	VODReviewRejected VODReviewStatus = "rejected" // The seller must submit a real VOD.
+)
+
+// VODReview is a moderator's check of a suspicious live fulfillment.
+type VODReview struct {
+	Status VODReviewStatus `json:"status" firestore:"status"`
+	// Reason says why the VOD wasn't verified automatically.
+	Reason      string     `json:"reason" firestore:"reason"`
+	SubmittedAt time.Time  `json:"submitted_at" firestore:"submitted_at"`
+	ReviewedBy  string     `json:"reviewed_by,omitempty" firestore:"reviewed_by,omitempty"`
+	ReviewedAt  *time.Time `json:"reviewed_at,omitempty" firestore:"reviewed_at,omitempty"`
+}
+
 // Order represents a finalized, paid-for transaction.
 type Order struct {
 	ID string `json:"id" firestore:"id"`
 
+	// SchemaVersion records which shape this document was written with.
+	SchemaVersion int `json:"schema_version" firestore:"schema_version"`
+
 	// Foreign Keys linking the core entities.
 	DropID          string `json:"drop_id" firestore:"drop_id"`
 	BuyerDiscordID  string `json:"buyer_discord_id" firestore:"buyer_discord_id"`
 	SellerDiscordID string `json:"seller_discord_id" firestore:"seller_discord_id"`
 
+	// DropType is copied from the drop: commissions are built live, RTS items ship.
+	DropType DropType `json:"drop_type" firestore:"drop_type"`
+
+	// Production record. Orders from before production tracking have no status.
+	ProductionStatus    ProductionStatus `json:"production_status,omitempty" firestore:"production_status,omitempty"`
+	ProductionStartedAt *time.Time       `json:"production_started_at,omitempty" firestore:"production_started_at,omitempty"`
+	// ProductionTimeline is every status change, oldest first.
+	ProductionTimeline []ProductionEvent `json:"production_timeline,omitempty" firestore:"production_timeline,omitempty"`
+
 	// Financial record.
 	PriceInCents int64        `json:"price_in_cents" firestore:"price_in_cents"`
 	EscrowStatus EscrowStatus `json:"escrow_status" firestore:"escrow_status"`
@@ -31,23 +102,60 @@ type Order struct {
 
 	// Fulfillment details (added later by the seller).
 	TrackingNumber string `json:"tracking_number,omitempty" firestore:"tracking_number,omitempty"`
-	Carrier        string `json:"carrier,omitempty" firestore:"carrier,omitempty"`
+	Carrier        string `json:"carrier,omitempty" firestore:"carrier,omitempty"` // A carrier code, see carrier.go.
 	VODLink        string `json:"vod_link,omitempty" firestore:"vod_link,omitempty"`
+	// Carrier tracking, filled in by the shipment poller once the order ships.
+	TrackingStatus    TrackingStatus  `json:"tracking_status,omitempty" firestore:"tracking_status,omitempty"`
+	TrackingEvents    []TrackingEvent `json:"tracking_events,omitempty" firestore:"tracking_events,omitempty"`
+	TrackingCheckedAt *time.Time      `json:"tracking_checked_at,omitempty" firestore:"tracking_checked_at,omitempty"`
+	// VODReview is set when the VOD link couldn't be verified against Twitch.
+	VODReview *VODReview `json:"vod_review,omitempty" firestore:"vod_review,omitempty"`
+
+	// UpdatesMuted stops progress update DMs; the buyer's order page still shows them.
+	UpdatesMuted bool `json:"updates_muted,omitempty" firestore:"updates_muted,omitempty"`
 
 	CreatedAt time.Time `json:"created_at" firestore:"created_at"`
 	UpdatedAt time.Time `json:"updated_at" firestore:"updated_at"`
 }
 
-// NewOrder is a helper to create a new order object with default "held" status.
-func NewOrder(dropID, buyerID, sellerID, paymentIntentID string, price int64) *Order {
+// CanAdvanceTo reports whether the order's production may move to next.
+// Commissions are built to order, so they must be in production before they
+// can ship; ready-to-ship items may go straight from queued to shipped.
+func (o *Order) CanAdvanceTo(next ProductionStatus) bool {
+	if o.DropType == DropTypeCommission && next == ProductionShipped && o.ProductionStatus != ProductionInProduction {
+		return false
+	}
+	for _, s := range productionSteps[o.ProductionStatus] {
+		if s == next {
+			return true
+		}
+	}
+	return false
+}
+
+// Timeline returns the production timeline. Orders from before timelines
+// start with a "queued" entry at the time they were paid.
+func (o *Order) Timeline() []ProductionEvent {
+	if len(o.ProductionTimeline) > 0 || o.ProductionStatus == "" {
+		return o.ProductionTimeline
+	}
+	return []ProductionEvent{{Status: ProductionQueued, At: o.CreatedAt}}
+}
+
+// NewOrder is a helper to create a new order object with default "held" status, queued for production.
+func NewOrder(dropID, buyerID, sellerID, paymentIntentID string, price int64, dropType DropType) *Order {
 	now := time.Now().UTC()
 	// In a real app, use a UUID library here: id := uuid.New().String()
 	id := "order_" + dropID + "_" + buyerID // Placeholder ID generation
 	return &Order{
 		ID:                    id,
+		SchemaVersion:         OrderSchemaVersion,
 		DropID:                dropID,
 		BuyerDiscordID:        buyerID,
 		SellerDiscordID:       sellerID,
+		DropType:              dropType,
+		ProductionStatus:      ProductionQueued,
+		ProductionTimeline:    []ProductionEvent{{Status: ProductionQueued, At: now}},
 		PriceInCents:          price,
 		EscrowStatus:          EscrowHeld, // Funds start as held.
 		StripePaymentIntentID: paymentIntentID,
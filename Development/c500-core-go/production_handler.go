@@ -0,0 +1,210 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"c500-core-go/internal/domain"
+	"c500-core-go/internal/service"
+)
+
+// ProductionHandler serves order production timelines: sellers advance
+// them and post progress updates, buyers (and sellers) read them.
+type ProductionHandler struct {
+	productionService service.ProductionService
+}
+
+// NewProductionHandler is the constructor.
+func NewProductionHandler(ps service.ProductionService) *ProductionHandler {
+	return &ProductionHandler{
+		productionService: ps,
+	}
+}
+
+// RegisterRoutes connects the HTTP URLs to the handler functions.
+// This is called in main.go.
+func (h *ProductionHandler) RegisterRoutes(router *gin.RouterGroup) {
+	// The buyer's order page on the website, behind the signed link from their DMs.
+	router.GET("/orders/:orderID/page", h.GetOrderPage)
+}
+
+// RegisterInternalRoutes connects the routes that trust the seller_id,
+// buyer_id or viewer_id in the request, which only the bot can vouch for. main.go puts
+// them behind the internal API key.
+func (h *ProductionHandler) RegisterInternalRoutes(router *gin.RouterGroup) {
+	router.GET("/orders/:orderID/timeline", h.GetTimeline)
+	router.POST("/orders/:orderID/production", h.Advance)
+	router.POST("/orders/:orderID/updates", h.PostUpdate)
+	router.PUT("/orders/:orderID/updates/muted", h.SetUpdatesMuted)
+}
+
+// ==========================================
+// Request/Response Structs (Data Contracts)
+// ==========================================
+
+// advanceProductionRequest is sent by the bot when a seller reports progress.
+// Shipping goes through POST /orders/:orderID/fulfill/ship instead.
+type advanceProductionRequest struct {
+	SellerDiscordID string `json:"seller_discord_id" binding:"required"`
+	Status          string `json:"status" binding:"required,oneof=parts_ordered in_production delivered"`
+	Note            string `json:"note"`
+	// PhotoAssetIDs are images uploaded through POST /assets/images.
+	PhotoAssetIDs []string `json:"photo_asset_ids"`
+}
+
+// progressUpdateRequest is a seller's update during the build.
+type progressUpdateRequest struct {
+	SellerDiscordID string   `json:"seller_discord_id" binding:"required"`
+	Note            string   `json:"note"`
+	PhotoAssetIDs   []string `json:"photo_asset_ids"`
+}
+
+// mutedRequest is the buyer switching progress update DMs off or on.
+type mutedRequest struct {
+	BuyerDiscordID string `json:"buyer_discord_id" binding:"required"`
+	Muted          *bool  `json:"muted" binding:"required"`
+}
+
+// timelineResponse is what the buyer sees of their order.
+type timelineResponse struct {
+	OrderID          string                   `json:"order_id"`
+	DropID           string                   `json:"drop_id"`
+	DropType         domain.DropType          `json:"drop_type,omitempty"`
+	SellerDiscordID  string                   `json:"seller_discord_id"`
+	ProductionStatus domain.ProductionStatus  `json:"production_status,omitempty"`
+	EscrowStatus     domain.EscrowStatus      `json:"escrow_status"`
+	Carrier          string                   `json:"carrier,omitempty"`
+	TrackingNumber   string                   `json:"tracking_number,omitempty"`
+	TrackingURL      string                   `json:"tracking_url,omitempty"`
+	TrackingStatus   domain.TrackingStatus    `json:"tracking_status,omitempty"`
+	TrackingEvents   []domain.TrackingEvent   `json:"tracking_events,omitempty"`
+	Timeline         []domain.ProductionEvent `json:"timeline"`
+	UpdatesMuted     bool                     `json:"updates_muted"`
+	// OrderURL is the buyer's order page link; only the buyer gets it.
+	OrderURL string `json:"order_url,omitempty"`
+}
+
+func newTimelineResponse(o *domain.Order) timelineResponse {
+	timeline := o.Timeline()
+	if timeline == nil {
+		timeline = []domain.ProductionEvent{}
+	}
+	resp := timelineResponse{
+		OrderID:          o.ID,
+		DropID:           o.DropID,
+		DropType:         o.DropType,
+		SellerDiscordID:  o.SellerDiscordID,
+		ProductionStatus: o.ProductionStatus,
+		EscrowStatus:     o.EscrowStatus,
+		Carrier:          o.Carrier,
+		TrackingNumber:   o.TrackingNumber,
+		TrackingStatus:   o.TrackingStatus,
+		TrackingEvents:   o.TrackingEvents,
+		Timeline:         timeline,
+		UpdatesMuted:     o.UpdatesMuted,
+	}
+	if carrier, ok := domain.LookupCarrier(o.Carrier); ok && o.TrackingNumber != "" {
+		resp.Carrier = carrier.Name
+		resp.TrackingURL = carrier.TrackingURL(o.TrackingNumber)
+	}
+	return resp
+}
+
+// ==========================================
+// Handler Functions
+// ==========================================
+
+// GetTimeline handles GET /api/v1/orders/:orderID/timeline?viewer_id=
+func (h *ProductionHandler) GetTimeline(c *gin.Context) {
+	viewerID := c.Query("viewer_id")
+	order, err := h.productionService.GetOrder(c.Request.Context(), c.Param("orderID"), viewerID)
+	if err != nil {
+		h.writeError(c, err, "Failed to load order")
+		return
+	}
+	resp := newTimelineResponse(order)
+	if viewerID == order.BuyerDiscordID {
+		resp.OrderURL = h.productionService.OrderPageURL(order)
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// GetOrderPage handles GET /api/v1/orders/:orderID/page?token=
+func (h *ProductionHandler) GetOrderPage(c *gin.Context) {
+	order, err := h.productionService.GetOrderByLink(c.Request.Context(), c.Param("orderID"), c.Query("token"))
+	if err != nil {
+		h.writeError(c, err, "Failed to load order")
+		return
+	}
+	c.JSON(http.StatusOK, newTimelineResponse(order))
+}
+
+// Advance handles POST /api/v1/orders/:orderID/production
+func (h *ProductionHandler) Advance(c *gin.Context) {
+	var req advanceProductionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	order, err := h.productionService.Advance(c.Request.Context(), c.Param("orderID"), req.SellerDiscordID,
+		domain.ProductionStatus(req.Status), req.Note, req.PhotoAssetIDs)
+	if err != nil {
+		h.writeError(c, err, "Failed to update production status")
+		return
+	}
+	c.JSON(http.StatusOK, newTimelineResponse(order))
+}
+
+// PostUpdate handles POST /api/v1/orders/:orderID/updates
+func (h *ProductionHandler) PostUpdate(c *gin.Context) {
+	var req progressUpdateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	order, err := h.productionService.PostUpdate(c.Request.Context(), c.Param("orderID"), req.SellerDiscordID, req.Note, req.PhotoAssetIDs)
+	if err != nil {
+		h.writeError(c, err, "Failed to post progress update")
+		return
+	}
+	c.JSON(http.StatusCreated, newTimelineResponse(order))
+}
+
+// SetUpdatesMuted handles PUT /api/v1/orders/:orderID/updates/muted
+func (h *ProductionHandler) SetUpdatesMuted(c *gin.Context) {
+	var req mutedRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.productionService.SetUpdatesMuted(c.Request.Context(), c.Param("orderID"), req.BuyerDiscordID, *req.Muted); err != nil {
+		h.writeError(c, err, "Failed to update notification settings")
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"updates_muted": *req.Muted})
+}
+
+// writeError maps service errors to HTTP status codes.
+func (h *ProductionHandler) writeError(c *gin.Context, err error, fallback string) {
+	switch {
+	case errors.Is(err, service.ErrOrderNotFound), errors.Is(err, service.ErrAssetNotFound),
+		errors.Is(err, service.ErrInvalidOrderLink):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrUnauthorizedSeller), errors.Is(err, service.ErrNotOrderParty),
+		errors.Is(err, service.ErrAssetNotOwned):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrInvalidProductionStep), errors.Is(err, service.ErrProductionConflict),
+		errors.Is(err, service.ErrUpdatesClosed):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrShipWithTracking), errors.Is(err, service.ErrProductionNoteTooLong),
+		errors.Is(err, service.ErrTooManyImages), errors.Is(err, service.ErrEmptyProgressUpdate):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": fallback})
+	}
+}
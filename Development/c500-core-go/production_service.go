@@ -0,0 +1,293 @@
+package service
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"c500-core-go/internal/domain"
+	"c500-core-go/internal/events"
+)
+
+var (
+	ErrInvalidProductionStep = errors.New("the order can't move to that production status from where it is")
+	ErrProductionConflict    = errors.New("the order's production status just changed, check it and try again")
+	ErrProductionNotFinished = errors.New("the order must be shipped before funds can be released")
+	ErrShipWithTracking      = errors.New("use the ship fulfillment with a tracking number to mark an order shipped")
+	ErrNotOrderParty         = errors.New("only the buyer and the seller can see this order")
+	ErrProductionNoteTooLong = errors.New("production notes can be at most 500 characters")
+	ErrEmptyProgressUpdate   = errors.New("a progress update needs a note or at least one photo")
+	ErrUpdatesClosed         = errors.New("progress updates can only be posted until the order is delivered")
+	ErrInvalidOrderLink      = errors.New("order link is invalid")
+)
+
+// maxProductionNote keeps timeline notes readable in a Discord DM.
+const maxProductionNote = 500
+
+// ProductionRepository defines the DB operations for the production timeline.
+// Implemented in internal/database/firestore.go
+type ProductionRepository interface {
+	// AdvanceProduction moves the order from one status to ev.Status, appends ev
+	// to the timeline and applies extra field updates, in one transaction.
+	// ErrProductionConflict if the order isn't in status from any more.
+	// Progress updates use it with from == ev.Status.
+	AdvanceProduction(ctx context.Context, orderID string, from domain.ProductionStatus, ev domain.ProductionEvent, extra map[string]interface{}) error
+}
+
+// ProductionService defines the methods the production handler uses.
+type ProductionService interface {
+	// Advance moves the seller's order to the next status, with an optional
+	// note and photos (IDs of images the seller uploaded through the asset API).
+	Advance(ctx context.Context, orderID, sellerID string, next domain.ProductionStatus, note string, photoAssetIDs []string) (*domain.Order, error)
+	// GetOrder returns the order for its buyer or seller.
+	GetOrder(ctx context.Context, orderID, viewerID string) (*domain.Order, error)
+
+	// PostUpdate adds a progress update (note and/or photos) to the timeline
+	// and DMs it to the buyer unless they muted updates.
+	PostUpdate(ctx context.Context, orderID, sellerID, note string, photoAssetIDs []string) (*domain.Order, error)
+	// SetUpdatesMuted lets the buyer stop (or restart) progress update DMs.
+	SetUpdatesMuted(ctx context.Context, orderID, buyerID string, muted bool) error
+
+	// OrderPageURL is the buyer's link to their order page on the website.
+	OrderPageURL(order *domain.Order) string
+	// GetOrderByLink returns the order if the token from its page link is valid.
+	GetOrderByLink(ctx context.Context, orderID, token string) (*domain.Order, error)
+}
+
+// productionService is the concrete implementation.
+type productionService struct {
+	orders     OrderRepository
+	repo       ProductionRepository
+	builders   BuilderRepository
+	drops      DropRepository
+	assets     AssetService
+	publisher  events.Publisher
+	linkSecret []byte
+	webBaseURL string
+}
+
+// NewProductionService constructor used in main.go. linkSecret signs the
+// buyers' order page links on webBaseURL.
+func NewProductionService(or OrderRepository, pr ProductionRepository, br BuilderRepository, dr DropRepository, as AssetService, publisher events.Publisher, linkSecret, webBaseURL string) *productionService {
+	return &productionService{
+		orders:     or,
+		repo:       pr,
+		builders:   br,
+		drops:      dr,
+		assets:     as,
+		publisher:  publisher,
+		linkSecret: []byte(linkSecret),
+		webBaseURL: strings.TrimRight(webBaseURL, "/"),
+	}
+}
+
+// ==========================================
+// Business Logic
+// ==========================================
+
+// Advance is how sellers report progress. Shipping goes through the fulfillment
+// service instead, which records the tracking number and releases escrow.
+func (s *productionService) Advance(ctx context.Context, orderID, sellerID string, next domain.ProductionStatus, note string, photoAssetIDs []string) (*domain.Order, error) {
+	if next == domain.ProductionShipped {
+		return nil, ErrShipWithTracking
+	}
+	note = strings.TrimSpace(note)
+	if utf8.RuneCountInString(note) > maxProductionNote {
+		return nil, ErrProductionNoteTooLong
+	}
+
+	order, err := s.orders.GetOrderByID(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	if order.SellerDiscordID != sellerID {
+		return nil, ErrUnauthorizedSeller
+	}
+	if err := resolveDropType(ctx, s.drops, order); err != nil {
+		return nil, err
+	}
+	if order.EscrowStatus == domain.EscrowRefunded || !order.CanAdvanceTo(next) {
+		return nil, ErrInvalidProductionStep
+	}
+
+	photoURLs, err := s.photos(ctx, sellerID, photoAssetIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	ev := domain.ProductionEvent{Status: next, At: now, Note: note, PhotoURLs: photoURLs, By: sellerID}
+	extra := map[string]interface{}{}
+	if next == domain.ProductionInProduction {
+		extra["production_started_at"] = now
+	}
+	if err := s.repo.AdvanceProduction(ctx, orderID, order.ProductionStatus, ev, extra); err != nil {
+		return nil, err
+	}
+	return s.orders.GetOrderByID(ctx, orderID)
+}
+
+// resolveDropType fills in the drop type of orders paid before it was copied
+// onto them (migration 0006 only sees the order), so commissions keep their
+// rules: built before shipped, released on a VOD.
+func resolveDropType(ctx context.Context, drops DropRepository, order *domain.Order) error {
+	if order.DropType != "" {
+		return nil
+	}
+	drop, err := drops.GetDropByID(ctx, order.DropID)
+	if err != nil {
+		return fmt.Errorf("failed to load drop %s for order %s: %w", order.DropID, order.ID, err)
+	}
+	order.DropType = drop.Type
+	return nil
+}
+
+// GetOrder lets the buyer follow their order's timeline; the seller sees it too.
+func (s *productionService) GetOrder(ctx context.Context, orderID, viewerID string) (*domain.Order, error) {
+	order, err := s.orders.GetOrderByID(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	if viewerID == "" || (viewerID != order.BuyerDiscordID && viewerID != order.SellerDiscordID) {
+		return nil, ErrNotOrderParty
+	}
+	return order, nil
+}
+
+// photos resolves uploaded image IDs to URLs.
+func (s *productionService) photos(ctx context.Context, sellerID string, assetIDs []string) ([]string, error) {
+	if len(assetIDs) == 0 {
+		return nil, nil
+	}
+	// SECURITY CHECK: photos must be the seller's own uploads.
+	return s.assets.ResolveForDrop(ctx, sellerID, assetIDs)
+}
+
+// ==========================================
+// Progress Updates
+// ==========================================
+
+// PostUpdate is how sellers keep buyers in the loop during a long build.
+func (s *productionService) PostUpdate(ctx context.Context, orderID, sellerID, note string, photoAssetIDs []string) (*domain.Order, error) {
+	note = strings.TrimSpace(note)
+	if note == "" && len(photoAssetIDs) == 0 {
+		return nil, ErrEmptyProgressUpdate
+	}
+	if utf8.RuneCountInString(note) > maxProductionNote {
+		return nil, ErrProductionNoteTooLong
+	}
+
+	order, err := s.orders.GetOrderByID(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	if order.SellerDiscordID != sellerID {
+		return nil, ErrUnauthorizedSeller
+	}
+	// Orders from before production tracking have no timeline to add to.
+	if order.ProductionStatus == "" || order.ProductionStatus == domain.ProductionDelivered ||
+		order.EscrowStatus == domain.EscrowRefunded {
+		return nil, ErrUpdatesClosed
+	}
+
+	photoURLs, err := s.photos(ctx, sellerID, photoAssetIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	ev := domain.ProductionEvent{
+		Status:    order.ProductionStatus,
+		At:        time.Now().UTC(),
+		Note:      note,
+		PhotoURLs: photoURLs,
+		By:        sellerID,
+		Update:    true,
+	}
+	if err := s.repo.AdvanceProduction(ctx, orderID, order.ProductionStatus, ev, nil); err != nil {
+		return nil, err
+	}
+
+	// The update is saved either way; a failed DM only costs the notification.
+	if !order.UpdatesMuted {
+		if err := s.notifyUpdate(ctx, order, ev); err != nil {
+			log.Printf("production: order %s: %v", orderID, err)
+		}
+	}
+	return s.orders.GetOrderByID(ctx, orderID)
+}
+
+// notifyUpdate publishes the update for the bots to DM to the buyer.
+func (s *productionService) notifyUpdate(ctx context.Context, order *domain.Order, ev domain.ProductionEvent) error {
+	sellerName := order.SellerDiscordID
+	if seller, err := s.builders.GetByID(ctx, order.SellerDiscordID); err == nil && seller.DisplayName != "" {
+		sellerName = seller.DisplayName
+	}
+	data := map[string]interface{}{
+		"order_id":          order.ID,
+		"drop_id":           order.DropID,
+		"buyer_discord_id":  order.BuyerDiscordID,
+		"seller_discord_id": order.SellerDiscordID,
+		"seller_name":       sellerName,
+		"production_status": ev.Status,
+		"note":              ev.Note,
+		"photo_urls":        ev.PhotoURLs,
+		"order_url":         s.OrderPageURL(order),
+		"at":                ev.At.Format(time.RFC3339),
+	}
+	if err := s.publisher.Publish(ctx, events.OrderProgressUpdate, data); err != nil {
+		return fmt.Errorf("failed to publish %s: %w", events.OrderProgressUpdate, err)
+	}
+	return nil
+}
+
+// SetUpdatesMuted is the buyer's switch for progress update DMs.
+func (s *productionService) SetUpdatesMuted(ctx context.Context, orderID, buyerID string, muted bool) error {
+	order, err := s.orders.GetOrderByID(ctx, orderID)
+	if err != nil {
+		return err
+	}
+	if order.BuyerDiscordID != buyerID {
+		return ErrNotOrderParty
+	}
+	return s.orders.UpdateOrderFulfillment(ctx, orderID, map[string]interface{}{
+		"updates_muted": muted,
+	})
+}
+
+// ==========================================
+// Order Page Links
+// ==========================================
+
+// OrderPageURL links the buyer to their order page. The web server has no
+// logins, so the link carries a token binding it to the order's buyer. It
+// doesn't expire: builds can take months and the DMs should keep working.
+func (s *productionService) OrderPageURL(order *domain.Order) string {
+	return s.webBaseURL + "/orders/" + url.PathEscape(order.ID) +
+		"?token=" + url.QueryEscape(s.signOrderLink(order.ID, order.BuyerDiscordID))
+}
+
+// GetOrderByLink checks the token from an order page link.
+func (s *productionService) GetOrderByLink(ctx context.Context, orderID, token string) (*domain.Order, error) {
+	order, err := s.orders.GetOrderByID(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	if !hmac.Equal([]byte(token), []byte(s.signOrderLink(order.ID, order.BuyerDiscordID))) {
+		return nil, ErrInvalidOrderLink
+	}
+	return order, nil
+}
+
+func (s *productionService) signOrderLink(orderID, buyerID string) string {
+	mac := hmac.New(sha256.New, s.linkSecret)
+	mac.Write([]byte("order|" + orderID + "|" + buyerID))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,40 @@
+package domain
+
+import "time"
+
+// ProfileAssetKind groups profile assets by how a page can use them.
+type ProfileAssetKind string
+
+const (
+	ProfileAssetImage ProfileAssetKind = "image" // PNG/JPEG (re-encoded) or GIF (animation kept)
+	ProfileAssetFont  ProfileAssetKind = "font"  // WOFF2, WOFF, TTF or OTF, for @font-face
+)
+
+// ProfileAsset is a file in a builder's profile asset library: images, GIFs and
+// fonts their custom page references. Profiles may only load files hosted this
+// way (see the sanitize package), so visitors never hit third-party servers.
+//
+// Stored in the "users/{discordID}/profile_assets" subcollection.
+type ProfileAsset struct {
+	// ID is the SHA-256 (hex) of the stored bytes. It is also in the URL, so
+	// uploading the same file twice gives the same asset and a URL never changes content.
+	ID        string           `json:"id" firestore:"id"`
+	BuilderID string           `json:"builder_id" firestore:"builder_id"`
+	Kind      ProfileAssetKind `json:"kind" firestore:"kind"`
+
+	// Filename is the name it was uploaded with, only shown in the builder's library.
+	Filename string `json:"filename" firestore:"filename"`
+
+	// StorageKey is the path inside the storage backend,
+	// e.g. "profile-assets/1234.../<sha256>.gif".
+	StorageKey  string `json:"-" firestore:"storage_key"`
+	URL         string `json:"url" firestore:"url"`
+	ContentType string `json:"content_type" firestore:"content_type"`
+	SizeBytes   int64  `json:"size_bytes" firestore:"size_bytes"`
+
+	// Width and Height are set for images.
+	Width  int `json:"width,omitempty" firestore:"width,omitempty"`
+	Height int `json:"height,omitempty" firestore:"height,omitempty"`
+
+	CreatedAt time.Time `json:"created_at" firestore:"created_at"`
+}
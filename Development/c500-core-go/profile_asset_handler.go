@@ -0,0 +1,126 @@
+package http
+
+import (
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"c500-core-go/internal/service"
+)
+
+// ProfileAssetHandler manages builders' profile asset libraries: the images,
+// GIFs and fonts their custom pages are allowed to reference.
+type ProfileAssetHandler struct {
+	profileAssetService service.ProfileAssetService
+}
+
+// NewProfileAssetHandler is the constructor.
+func NewProfileAssetHandler(ps service.ProfileAssetService) *ProfileAssetHandler {
+	return &ProfileAssetHandler{
+		profileAssetService: ps,
+	}
+}
+
+// RegisterRoutes connects the HTTP URLs to the handler functions.
+// This is called in main.go.
+func (h *ProfileAssetHandler) RegisterRoutes(router *gin.RouterGroup) {
+	router.GET("/builders/:discordID/profile/assets", h.List)
+}
+
+// RegisterInternalRoutes connects upload and delete. They act for whichever
+// builder is in the URL, so main.go puts them behind the internal API key.
+func (h *ProfileAssetHandler) RegisterInternalRoutes(router *gin.RouterGroup) {
+	router.POST("/builders/:discordID/profile/assets", h.Upload)
+	router.DELETE("/builders/:discordID/profile/assets/:assetID", h.Delete)
+}
+
+// ==========================================
+// Handler Functions
+// ==========================================
+
+// List handles GET /api/v1/builders/:discordID/profile/assets
+// The response includes quota usage so the editor can show what's left.
+func (h *ProfileAssetHandler) List(c *gin.Context) {
+	library, err := h.profileAssetService.List(c.Request.Context(), c.Param("discordID"))
+	if err != nil {
+		h.writeError(c, err, "Failed to load profile assets")
+		return
+	}
+	c.JSON(http.StatusOK, library)
+}
+
+// Upload handles POST /api/v1/builders/:discordID/profile/assets
+// It expects multipart/form-data with a "file": PNG, JPEG, GIF, WebP, WOFF2,
+// WOFF, TTF or OTF, max 5 MB. The response's "url" is what the profile's
+// HTML or CSS should reference.
+func (h *ProfileAssetHandler) Upload(c *gin.Context) {
+	// 1. Cap the body before reading anything (+1 MB for form overhead).
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, service.MaxProfileAssetBytes+(1<<20))
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		var tooBig *http.MaxBytesError
+		if errors.As(err, &tooBig) {
+			c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": "Files must be 5 MB or smaller"})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": "file is required"})
+		return
+	}
+	if fileHeader.Size > service.MaxProfileAssetBytes {
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": "Files must be 5 MB or smaller"})
+		return
+	}
+
+	// 2. Read the bytes. The type is sniffed from the data; the client's
+	// Content-Type header is ignored and the filename is only used as a label.
+	f, err := fileHeader.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Could not read upload"})
+		return
+	}
+	defer f.Close()
+	data, err := io.ReadAll(io.LimitReader(f, service.MaxProfileAssetBytes+1))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Could not read upload"})
+		return
+	}
+
+	// 3. Call the Service Layer
+	asset, err := h.profileAssetService.Upload(c.Request.Context(), c.Param("discordID"), fileHeader.Filename, data)
+	if err != nil {
+		h.writeError(c, err, "Failed to store profile asset")
+		return
+	}
+	c.JSON(http.StatusCreated, asset)
+}
+
+// Delete handles DELETE /api/v1/builders/:discordID/profile/assets/:assetID
+func (h *ProfileAssetHandler) Delete(c *gin.Context) {
+	if err := h.profileAssetService.Delete(c.Request.Context(), c.Param("discordID"), c.Param("assetID")); err != nil {
+		h.writeError(c, err, "Failed to delete profile asset")
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// writeError maps service errors to HTTP status codes.
+func (h *ProfileAssetHandler) writeError(c *gin.Context, err error, fallback string) {
+	switch {
+	case errors.Is(err, service.ErrProfileAssetNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrBuilderNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Builder not found"})
+	case errors.Is(err, service.ErrProfileAssetInvalid):
+		// Wrong type, corrupt file or too many pixels/frames: tell the builder why.
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrProfileAssetQuota):
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrProfileAssetInUse):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": fallback})
+	}
+}
@@ -0,0 +1,259 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"c500-core-go/internal/domain"
+	"c500-core-go/internal/media"
+	"c500-core-go/internal/storage"
+)
+
+var (
+	ErrProfileAssetNotFound = errors.New("profile asset not found")
+	ErrProfileAssetInvalid  = errors.New("invalid profile asset")
+	ErrProfileAssetQuota    = errors.New("profile asset library is full, delete something first")
+	ErrProfileAssetInUse    = errors.New("your live profile still uses this asset")
+)
+
+// ProfileAssetKeyPrefix is where profile assets live in the BlobStore. main.go
+// gives the sanitizer the matching public URL, so profiles can reference
+// exactly these files and nothing else.
+const ProfileAssetKeyPrefix = "profile-assets/"
+
+const (
+	// MaxProfileAssetBytes caps one upload; profile pages should stay light.
+	MaxProfileAssetBytes = 5 << 20 // 5 MB
+
+	// Per-builder quota: total stored bytes and number of files.
+	profileAssetQuotaBytes = 25 << 20 // 25 MB
+	maxProfileAssets       = 200
+
+	// maxProfileImageWidth is plenty for a profile page; larger images are scaled down.
+	maxProfileImageWidth = 2048
+
+	maxAssetFilename = 100
+)
+
+// profileAssetExtensions maps stored content types to URL extensions.
+// The sanitizer only accepts URLs ending in one of these.
+var profileAssetExtensions = map[string]string{
+	"image/png":  ".png",
+	"image/jpeg": ".jpg",
+	"image/gif":  ".gif",
+	"font/woff2": ".woff2",
+	"font/woff":  ".woff",
+	"font/ttf":   ".ttf",
+	"font/otf":   ".otf",
+}
+
+// ProfileAssetRepository persists profile asset metadata (the bytes live in the BlobStore).
+// Implemented in internal/database/firestore.go
+type ProfileAssetRepository interface {
+	GetProfileAsset(ctx context.Context, builderID, assetID string) (*domain.ProfileAsset, error)
+	// ListProfileAssets returns a builder's whole library, newest first.
+	ListProfileAssets(ctx context.Context, builderID string) ([]domain.ProfileAsset, error)
+	// CreateProfileAsset records an asset and adds it to the builder's usage in one
+	// transaction, failing with ErrProfileAssetQuota if either limit would be exceeded.
+	// Recording an asset that already exists is a no-op.
+	CreateProfileAsset(ctx context.Context, asset *domain.ProfileAsset, quotaBytes int64, maxCount int) error
+	// DeleteProfileAsset removes an asset and its usage, returning what was deleted.
+	DeleteProfileAsset(ctx context.Context, builderID, assetID string) (*domain.ProfileAsset, error)
+}
+
+// ProfileAssetLibrary is a builder's assets and how much of their quota is left.
+type ProfileAssetLibrary struct {
+	Assets     []domain.ProfileAsset `json:"assets"`
+	UsedBytes  int64                 `json:"used_bytes"`
+	QuotaBytes int64                 `json:"quota_bytes"`
+	Count      int                   `json:"count"`
+	MaxCount   int                   `json:"max_count"`
+}
+
+// ProfileAssetService defines the methods handlers use for profile asset libraries.
+type ProfileAssetService interface {
+	Upload(ctx context.Context, builderID, filename string, data []byte) (*domain.ProfileAsset, error)
+	List(ctx context.Context, builderID string) (*ProfileAssetLibrary, error)
+	Delete(ctx context.Context, builderID, assetID string) error
+}
+
+// profileAssetService is the concrete implementation.
+type profileAssetService struct {
+	builders BuilderRepository
+	assets   ProfileAssetRepository
+	store    storage.BlobStore
+}
+
+// NewProfileAssetService constructor used in main.go.
+func NewProfileAssetService(br BuilderRepository, pr ProfileAssetRepository, store storage.BlobStore) *profileAssetService {
+	return &profileAssetService{
+		builders: br,
+		assets:   pr,
+		store:    store,
+	}
+}
+
+// ==========================================
+// Business Logic
+// ==========================================
+
+// Upload validates a file, stores it under its content hash and adds it to the
+// builder's library. Uploading a file they already have returns the existing asset.
+func (s *profileAssetService) Upload(ctx context.Context, builderID, filename string, data []byte) (*domain.ProfileAsset, error) {
+	if len(data) > MaxProfileAssetBytes {
+		return nil, fmt.Errorf("%w: files must be %d MB or smaller", ErrProfileAssetInvalid, MaxProfileAssetBytes>>20)
+	}
+	builder, err := s.builders.GetByID(ctx, builderID)
+	if err != nil {
+		return nil, err
+	}
+
+	// 1. Validate and normalize. All media errors are user errors (bad file), so wrap them.
+	asset, body, err := prepareProfileAsset(data)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrProfileAssetInvalid, err)
+	}
+	sum := sha256.Sum256(body)
+	asset.ID = hex.EncodeToString(sum[:])
+	asset.BuilderID = builderID
+	asset.Filename = cleanAssetFilename(filename)
+	asset.SizeBytes = int64(len(body))
+	asset.StorageKey = ProfileAssetKeyPrefix + builderID + "/" + asset.ID + profileAssetExtensions[asset.ContentType]
+	asset.URL = s.store.PublicURL(asset.StorageKey)
+	asset.CreatedAt = time.Now().UTC()
+
+	// 2. Same bytes, same asset.
+	if existing, err := s.assets.GetProfileAsset(ctx, builderID, asset.ID); err == nil {
+		return existing, nil
+	} else if !errors.Is(err, ErrProfileAssetNotFound) {
+		return nil, err
+	}
+
+	// 3. Cheap quota check before uploading; the repository enforces it for real.
+	if builder.ProfileAssetBytes+asset.SizeBytes > profileAssetQuotaBytes || builder.ProfileAssetCount >= maxProfileAssets {
+		return nil, ErrProfileAssetQuota
+	}
+
+	// 4. Store, then record. The key is content-addressed, so re-uploading is harmless.
+	if err := s.store.Put(ctx, asset.StorageKey, asset.ContentType, body); err != nil {
+		return nil, fmt.Errorf("failed to store profile asset: %w", err)
+	}
+	if err := s.assets.CreateProfileAsset(ctx, asset, profileAssetQuotaBytes, maxProfileAssets); err != nil {
+		if errors.Is(err, ErrProfileAssetQuota) {
+			// Lost a race with another upload; nothing references this object.
+			_ = s.store.Delete(ctx, asset.StorageKey)
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to save profile asset: %w", err)
+	}
+	return asset, nil
+}
+
+// List returns a builder's library and quota usage.
+func (s *profileAssetService) List(ctx context.Context, builderID string) (*ProfileAssetLibrary, error) {
+	builder, err := s.builders.GetByID(ctx, builderID)
+	if err != nil {
+		return nil, err
+	}
+	assets, err := s.assets.ListProfileAssets(ctx, builderID)
+	if err != nil {
+		return nil, err
+	}
+	return &ProfileAssetLibrary{
+		Assets:     assets,
+		UsedBytes:  builder.ProfileAssetBytes,
+		QuotaBytes: profileAssetQuotaBytes,
+		Count:      builder.ProfileAssetCount,
+		MaxCount:   maxProfileAssets,
+	}, nil
+}
+
+// Delete removes an asset and frees its quota. Assets the live profile still
+// references are refused, so deleting can't break the published page; drafts
+// and old revisions aren't checked.
+func (s *profileAssetService) Delete(ctx context.Context, builderID, assetID string) error {
+	builder, err := s.builders.GetByID(ctx, builderID)
+	if err != nil {
+		return err
+	}
+	asset, err := s.assets.GetProfileAsset(ctx, builderID, assetID)
+	if err != nil {
+		return err
+	}
+	if strings.Contains(builder.Profile.HTML, asset.URL) || strings.Contains(builder.Profile.CSS, asset.URL) {
+		return ErrProfileAssetInUse
+	}
+
+	if _, err := s.assets.DeleteProfileAsset(ctx, builderID, assetID); err != nil {
+		return err
+	}
+	// Best effort: an orphaned object costs storage, not correctness.
+	_ = s.store.Delete(ctx, asset.StorageKey)
+	return nil
+}
+
+// prepareProfileAsset sniffs the file type from the bytes and returns the asset
+// (type, kind, dimensions) with the bytes to store.
+//
+//   - GIFs keep their animation but are re-encoded, dropping everything but the frames.
+//   - Other images go through media.Process like product photos: EXIF/GPS stripped,
+//     stored as PNG (transparency) or JPEG.
+//   - Fonts are stored as uploaded.
+func prepareProfileAsset(data []byte) (*domain.ProfileAsset, []byte, error) {
+	switch ct := http.DetectContentType(data); {
+	case ct == "image/gif":
+		r, err := media.ProcessAnimatedGIF(data)
+		if err != nil {
+			return nil, nil, err
+		}
+		return renditionAsset(r), r.Data, nil
+
+	case strings.HasPrefix(ct, "image/"):
+		renditions, err := media.Process(data, []media.VariantSpec{{Name: domain.VariantOriginal, MaxWidth: maxProfileImageWidth}})
+		if err != nil {
+			return nil, nil, err
+		}
+		return renditionAsset(&renditions[0]), renditions[0].Data, nil
+
+	default:
+		ct, err := media.SniffFont(data)
+		if err != nil {
+			return nil, nil, err
+		}
+		return &domain.ProfileAsset{Kind: domain.ProfileAssetFont, ContentType: ct}, data, nil
+	}
+}
+
+func renditionAsset(r *media.Rendition) *domain.ProfileAsset {
+	return &domain.ProfileAsset{
+		Kind:        domain.ProfileAssetImage,
+		ContentType: r.ContentType,
+		Width:       r.Width,
+		Height:      r.Height,
+	}
+}
+
+// cleanAssetFilename keeps the uploaded name readable in the library without
+// trusting it: path and control characters are dropped and the length capped.
+func cleanAssetFilename(name string) string {
+	if i := strings.LastIndexAny(name, `/\`); i >= 0 {
+		name = name[i+1:]
+	}
+	name = strings.Map(func(r rune) rune {
+		if r < 0x20 || r == 0x7f || r == utf8.RuneError {
+			return -1
+		}
+		return r
+	}, name)
+	if r := []rune(name); len(r) > maxAssetFilename {
+		name = string(r[:maxAssetFilename])
+	}
+	return strings.TrimSpace(name)
+}
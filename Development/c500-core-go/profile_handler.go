@@ -0,0 +1,289 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"c500-core-go/internal/domain"
+	"c500-core-go/internal/service"
+)
+
+// ProfileHandler serves builder profile pages: the live page for the web server,
+// and saving, history, rollback and previews for the bot.
+type ProfileHandler struct {
+	profileService service.ProfileService
+	slugService    service.SlugService
+	webBaseURL     string
+}
+
+// NewProfileHandler is the constructor. webBaseURL (e.g. https://c500.store)
+// is used to build preview links the bot can hand straight to the builder.
+func NewProfileHandler(ps service.ProfileService, ss service.SlugService, webBaseURL string) *ProfileHandler {
+	return &ProfileHandler{
+		profileService: ps,
+		slugService:    ss,
+		webBaseURL:     strings.TrimRight(webBaseURL, "/"),
+	}
+}
+
+// RegisterRoutes connects the HTTP URLs to the handler functions.
+// This is called in main.go.
+func (h *ProfileHandler) RegisterRoutes(router *gin.RouterGroup) {
+	// The web server's /builder/:username page. Accepts slugs and Discord IDs.
+	router.GET("/profiles/:slug", h.GetProfileBySlug)
+
+	router.GET("/builders/:discordID/profile", h.GetPublishedProfile)
+
+	// Live numbers for the web server's profile widgets ([[sold count]]).
+	router.GET("/builders/:discordID/stats", h.GetStats)
+
+	// The web server calls this to render a preview; it needs the signed token.
+	router.GET("/builders/:discordID/profile/revisions/:revisionID", h.GetPreview)
+}
+
+// RegisterInternalRoutes connects the routes that change a page, mint
+// preview links or list drafts (with their content). They trust
+// author_discord_id, so main.go puts them behind the internal API key.
+func (h *ProfileHandler) RegisterInternalRoutes(router *gin.RouterGroup) {
+	router.GET("/builders/:discordID/profile/revisions", h.ListRevisions)
+	router.PUT("/builders/:discordID/profile", h.SaveProfile)
+	router.POST("/builders/:discordID/profile/revisions/:revisionID/rollback", h.Rollback)
+	router.POST("/builders/:discordID/profile/revisions/:revisionID/preview", h.CreatePreviewLink)
+}
+
+// ==========================================
+// Request/Response Structs (Data Contracts)
+// ==========================================
+
+// saveProfileRequest is sent by the bot when a builder edits their page.
+type saveProfileRequest struct {
+	HTML string `json:"html"`
+	CSS  string `json:"css"`
+	// Publish=false saves a draft that can only be seen through a preview link.
+	Publish bool `json:"publish"`
+	// AuthorDiscordID is who made the change (the builder, or an admin).
+	AuthorDiscordID string `json:"author_discord_id" binding:"required"`
+}
+
+// rollbackRequest is sent by the bot when a builder restores an old revision.
+type rollbackRequest struct {
+	AuthorDiscordID string `json:"author_discord_id" binding:"required"`
+}
+
+// previewLinkRequest is sent by the bot when a builder wants to preview a revision.
+type previewLinkRequest struct {
+	AuthorDiscordID string `json:"author_discord_id" binding:"required"`
+}
+
+// publicProfileResponse is what the web server needs to render a profile.
+// It deliberately leaves out Stripe IDs and other private fields.
+type publicProfileResponse struct {
+	DiscordID   string             `json:"discord_id"`
+	DisplayName string             `json:"display_name"`
+	Slug        string             `json:"slug,omitempty"`
+	Profile     domain.ProfileData `json:"profile_data"`
+	RevisionID  string             `json:"revision_id,omitempty"`
+	// TwitchLogin is the builder's verified channel (twitch.tv/<login>), if linked.
+	TwitchLogin string `json:"twitch_login,omitempty"`
+	// TwitchLive is set while they're streaming (per Twitch EventSub).
+	TwitchLive bool `json:"twitch_live,omitempty"`
+}
+
+// twitchLogin returns the builder's linked channel name, or "".
+func twitchLogin(b *domain.Builder) string {
+	if b.Twitch == nil {
+		return ""
+	}
+	return b.Twitch.Login
+}
+
+// twitchLive reports whether the builder's linked channel is live right now.
+func twitchLive(b *domain.Builder) bool {
+	return b.Twitch != nil && b.Twitch.LiveSince != nil
+}
+
+// previewLinkResponse is returned to the bot to DM to the builder.
+type previewLinkResponse struct {
+	URL       string `json:"url"`
+	Token     string `json:"token"`
+	ExpiresAt string `json:"expires_at"`
+}
+
+// ==========================================
+// Handler Functions
+// ==========================================
+
+// GetPublishedProfile handles GET /api/v1/builders/:discordID/profile
+func (h *ProfileHandler) GetPublishedProfile(c *gin.Context) {
+	builder, err := h.profileService.GetPublishedProfile(c.Request.Context(), c.Param("discordID"))
+	if err != nil {
+		h.writeError(c, err, "Failed to load profile")
+		return
+	}
+	c.JSON(http.StatusOK, publicProfileResponse{
+		DiscordID:   builder.DiscordID,
+		DisplayName: builder.DisplayName,
+		Slug:        builder.Slug,
+		Profile:     builder.Profile,
+		RevisionID:  builder.PublishedRevisionID,
+		TwitchLogin: twitchLogin(builder),
+		TwitchLive:  twitchLive(builder),
+	})
+}
+
+// GetProfileBySlug handles GET /api/v1/profiles/:slug
+// The response's "slug" is canonical; if it differs from the one requested
+// (renamed builder, different case, Discord ID), the web server 301s to it.
+func (h *ProfileHandler) GetProfileBySlug(c *gin.Context) {
+	res, err := h.slugService.Resolve(c.Request.Context(), c.Param("slug"))
+	if err != nil {
+		if errors.Is(err, service.ErrSlugNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Builder not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load profile"})
+		return
+	}
+
+	builder, err := h.profileService.GetPublishedProfile(c.Request.Context(), res.BuilderID)
+	if err != nil {
+		h.writeError(c, err, "Failed to load profile")
+		return
+	}
+	c.JSON(http.StatusOK, publicProfileResponse{
+		DiscordID:   builder.DiscordID,
+		DisplayName: builder.DisplayName,
+		Slug:        res.Slug,
+		Profile:     builder.Profile,
+		RevisionID:  builder.PublishedRevisionID,
+		TwitchLogin: twitchLogin(builder),
+		TwitchLive:  twitchLive(builder),
+	})
+}
+
+// GetStats handles GET /api/v1/builders/:discordID/stats
+func (h *ProfileHandler) GetStats(c *gin.Context) {
+	stats, err := h.profileService.GetStats(c.Request.Context(), c.Param("discordID"))
+	if err != nil {
+		h.writeError(c, err, "Failed to load stats")
+		return
+	}
+	c.JSON(http.StatusOK, stats)
+}
+
+// SaveProfile handles PUT /api/v1/builders/:discordID/profile
+func (h *ProfileHandler) SaveProfile(c *gin.Context) {
+	var req saveProfileRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	rev, err := h.profileService.SaveProfile(c.Request.Context(), c.Param("discordID"), req.AuthorDiscordID, req.HTML, req.CSS, req.Publish)
+	if err != nil {
+		h.writeError(c, err, "Failed to save profile")
+		return
+	}
+	c.JSON(http.StatusCreated, rev)
+}
+
+// ListRevisions handles GET /api/v1/builders/:discordID/profile/revisions?limit=
+func (h *ProfileHandler) ListRevisions(c *gin.Context) {
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if err != nil || limit < 1 || limit > 100 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "limit must be between 1 and 100"})
+		return
+	}
+
+	revs, err := h.profileService.ListRevisions(c.Request.Context(), c.Param("discordID"), limit)
+	if err != nil {
+		h.writeError(c, err, "Failed to list revisions")
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"revisions": revs})
+}
+
+// Rollback handles POST /api/v1/builders/:discordID/profile/revisions/:revisionID/rollback
+func (h *ProfileHandler) Rollback(c *gin.Context) {
+	var req rollbackRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	rev, err := h.profileService.Rollback(c.Request.Context(), c.Param("discordID"), c.Param("revisionID"), req.AuthorDiscordID)
+	if err != nil {
+		h.writeError(c, err, "Failed to roll back profile")
+		return
+	}
+	c.JSON(http.StatusCreated, rev)
+}
+
+// CreatePreviewLink handles POST /api/v1/builders/:discordID/profile/revisions/:revisionID/preview
+func (h *ProfileHandler) CreatePreviewLink(c *gin.Context) {
+	var req previewLinkRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	builderID, revisionID := c.Param("discordID"), c.Param("revisionID")
+
+	token, expires, err := h.profileService.PreviewToken(c.Request.Context(), builderID, revisionID, req.AuthorDiscordID)
+	if err != nil {
+		h.writeError(c, err, "Failed to create preview link")
+		return
+	}
+	c.JSON(http.StatusOK, previewLinkResponse{
+		URL: h.webBaseURL + "/builder/" + url.PathEscape(builderID) +
+			"/preview/" + url.PathEscape(revisionID) + "?token=" + url.QueryEscape(token),
+		Token:     token,
+		ExpiresAt: expires.Format(time.RFC3339),
+	})
+}
+
+// GetPreview handles GET /api/v1/builders/:discordID/profile/revisions/:revisionID?token=
+func (h *ProfileHandler) GetPreview(c *gin.Context) {
+	builderID := c.Param("discordID")
+
+	rev, err := h.profileService.GetPreview(c.Request.Context(), builderID, c.Param("revisionID"), c.Query("token"))
+	if err != nil {
+		h.writeError(c, err, "Failed to load preview")
+		return
+	}
+	builder, err := h.profileService.GetPublishedProfile(c.Request.Context(), builderID)
+	if err != nil {
+		h.writeError(c, err, "Failed to load preview")
+		return
+	}
+	c.JSON(http.StatusOK, publicProfileResponse{
+		DiscordID:   builder.DiscordID,
+		DisplayName: builder.DisplayName,
+		Slug:        builder.Slug,
+		Profile:     rev.Profile,
+		RevisionID:  rev.ID,
+		TwitchLogin: twitchLogin(builder),
+		TwitchLive:  twitchLive(builder),
+	})
+}
+
+// writeError maps service errors to HTTP status codes.
+func (h *ProfileHandler) writeError(c *gin.Context, err error, fallback string) {
+	switch {
+	case errors.Is(err, service.ErrBuilderNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Builder not found"})
+	case errors.Is(err, service.ErrRevisionNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Revision not found"})
+	case errors.Is(err, service.ErrInvalidPreviewToken), errors.Is(err, service.ErrNotProfileAuthor):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrProfileTooLarge):
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": fallback})
+	}
+}
@@ -0,0 +1,29 @@
+package domain
+
+import "time"
+
+// ProfileRevision is one saved version of a builder's profile page.
+// Every save creates a revision; the live page is whichever revision the
+// builder last published (Builder.PublishedRevisionID), and its content is
+// copied into Builder.Profile so the public page is still a single read.
+//
+// Stored in the "users/{discordID}/profile_revisions" subcollection.
+type ProfileRevision struct {
+	// ID sorts by creation time, so listing newest-first is a plain ordered query.
+	ID        string `json:"id" firestore:"id"`
+	BuilderID string `json:"builder_id" firestore:"builder_id"`
+
+	// Profile is the sanitized content, exactly as it would be rendered.
+	Profile ProfileData `json:"profile_data" firestore:"profile_data"`
+
+	// AuthorDiscordID is who saved it: the builder, or an admin fixing a page.
+	AuthorDiscordID string `json:"author_discord_id" firestore:"author_discord_id"`
+
+	// RestoredFromID is set when this revision was created by rolling back.
+	RestoredFromID string `json:"restored_from_id,omitempty" firestore:"restored_from_id,omitempty"`
+
+	// Published is true once this revision has been the live page at some point.
+	Published bool `json:"published" firestore:"published"`
+
+	CreatedAt time.Time `json:"created_at" firestore:"created_at"`
+}
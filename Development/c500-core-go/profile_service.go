@@ -0,0 +1,260 @@
+package service
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"c500-core-go/internal/domain"
+	"c500-core-go/internal/sanitize"
+)
+
+var (
+	ErrProfileTooLarge     = errors.New("profile content exceeds size limits")
+	ErrRevisionNotFound    = errors.New("profile revision not found")
+	ErrInvalidPreviewToken = errors.New("preview link is invalid or has expired")
+	// ErrNotProfileAuthor means someone other than the builder (or an admin) tried to change their page.
+	ErrNotProfileAuthor = errors.New("only the builder or a C500 admin can change this profile")
+)
+
+// Limits for profile content, measured on the raw input.
+const (
+	maxProfileHTML = 10000
+	maxProfileCSS  = 5000
+)
+
+// previewTTL is how long a preview link works. Long enough to share with a
+// friend for feedback, short enough that leaked links die on their own.
+const previewTTL = 24 * time.Hour
+
+// ProfileRepository defines DB operations for profile revisions.
+// Implemented in internal/database/firestore.go
+type ProfileRepository interface {
+	CreateProfileRevision(ctx context.Context, rev *domain.ProfileRevision) error
+	GetProfileRevision(ctx context.Context, builderID, revisionID string) (*domain.ProfileRevision, error)
+	// ListProfileRevisions returns the newest revisions first.
+	ListProfileRevisions(ctx context.Context, builderID string, limit int) ([]domain.ProfileRevision, error)
+	// PublishProfileRevision copies rev into the builder's live profile and marks it published.
+	PublishProfileRevision(ctx context.Context, rev *domain.ProfileRevision) error
+	// CountSoldDrops powers the [[sold count]] profile widget.
+	CountSoldDrops(ctx context.Context, sellerDiscordID string) (int64, error)
+}
+
+// ProfileStats is the live data behind profile widgets that isn't a drop list.
+type ProfileStats struct {
+	SoldCount int64 `json:"sold_count"`
+}
+
+// ProfileService defines the methods handlers use for builder profile pages.
+type ProfileService interface {
+	SaveProfile(ctx context.Context, builderID, authorID, rawHTML, rawCSS string, publish bool) (*domain.ProfileRevision, error)
+	ListRevisions(ctx context.Context, builderID string, limit int) ([]domain.ProfileRevision, error)
+	Rollback(ctx context.Context, builderID, revisionID, authorID string) (*domain.ProfileRevision, error)
+	PreviewToken(ctx context.Context, builderID, revisionID, authorID string) (string, time.Time, error)
+	GetPreview(ctx context.Context, builderID, revisionID, token string) (*domain.ProfileRevision, error)
+	GetPublishedProfile(ctx context.Context, builderID string) (*domain.Builder, error)
+	GetStats(ctx context.Context, builderID string) (*ProfileStats, error)
+}
+
+// profileService is the concrete implementation.
+type profileService struct {
+	builders      BuilderRepository
+	revisions     ProfileRepository
+	previewSecret []byte
+	admins        map[string]bool
+}
+
+// NewProfileService constructor used in main.go. previewSecret signs preview
+// links (PROFILE_PREVIEW_SECRET); it must be the same on every Core instance.
+// adminIDs may change any builder's page, not just their own.
+func NewProfileService(br BuilderRepository, pr ProfileRepository, previewSecret string, adminIDs []string) *profileService {
+	admins := make(map[string]bool, len(adminIDs))
+	for _, id := range adminIDs {
+		if id = strings.TrimSpace(id); id != "" {
+			admins[id] = true
+		}
+	}
+	return &profileService{
+		builders:      br,
+		revisions:     pr,
+		previewSecret: []byte(previewSecret),
+		admins:        admins,
+	}
+}
+
+// ==========================================
+// Business Logic
+// ==========================================
+
+// SaveProfile stores a new revision. With publish=false it's a draft the builder
+// can preview; with publish=true it also becomes the live page.
+// Nothing is ever overwritten, so a bad save can always be rolled back.
+func (s *profileService) SaveProfile(ctx context.Context, builderID, authorID, rawHTML, rawCSS string, publish bool) (*domain.ProfileRevision, error) {
+	// 1. Validate inputs.
+	if !s.canEdit(builderID, authorID) {
+		return nil, ErrNotProfileAuthor
+	}
+	if len(rawHTML) > maxProfileHTML || len(rawCSS) > maxProfileCSS {
+		return nil, ErrProfileTooLarge
+	}
+	if _, err := s.builders.GetByID(ctx, builderID); err != nil {
+		return nil, err
+	}
+
+	// 2. SECURITY SANITIZATION
+	// The web server renders this content unescaped on c500.store, so this is the
+	// ONLY thing standing between a builder's page and stored XSS.
+	// HTML goes through a strict allowlist; CSS is re-parsed, stripped of
+	// @import, expression(), fixed/sticky positioning and any url() that isn't a
+	// hosted profile asset, and scoped so every rule only applies inside the profile box.
+	rev, err := newRevision(builderID, authorID, sanitizeProfile(domain.ProfileData{HTML: rawHTML, CSS: rawCSS}))
+	if err != nil {
+		return nil, err
+	}
+
+	// 3. Save, then optionally go live.
+	if err := s.revisions.CreateProfileRevision(ctx, rev); err != nil {
+		return nil, fmt.Errorf("failed to save profile revision: %w", err)
+	}
+	if publish {
+		if err := s.revisions.PublishProfileRevision(ctx, rev); err != nil {
+			return nil, fmt.Errorf("failed to publish profile revision: %w", err)
+		}
+		rev.Published = true
+	}
+	return rev, nil
+}
+
+// ListRevisions returns a builder's revision history, newest first.
+func (s *profileService) ListRevisions(ctx context.Context, builderID string, limit int) ([]domain.ProfileRevision, error) {
+	return s.revisions.ListProfileRevisions(ctx, builderID, limit)
+}
+
+// Rollback makes an old revision live again. It doesn't rewrite history: the
+// restored content is saved as a NEW revision pointing back at the old one,
+// so rolling back a rollback works the same way.
+func (s *profileService) Rollback(ctx context.Context, builderID, revisionID, authorID string) (*domain.ProfileRevision, error) {
+	if !s.canEdit(builderID, authorID) {
+		return nil, ErrNotProfileAuthor
+	}
+	old, err := s.revisions.GetProfileRevision(ctx, builderID, revisionID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Old revisions were sanitized under the rules of their day; re-run today's.
+	rev, err := newRevision(builderID, authorID, sanitizeProfile(old.Profile))
+	if err != nil {
+		return nil, err
+	}
+	rev.RestoredFromID = old.ID
+
+	if err := s.revisions.CreateProfileRevision(ctx, rev); err != nil {
+		return nil, fmt.Errorf("failed to save restored revision: %w", err)
+	}
+	if err := s.revisions.PublishProfileRevision(ctx, rev); err != nil {
+		return nil, fmt.Errorf("failed to publish restored revision: %w", err)
+	}
+	rev.Published = true
+	return rev, nil
+}
+
+// GetPublishedProfile returns the builder whose live profile the web server renders.
+// Builder.Profile always holds the published revision, so this is a single read.
+func (s *profileService) GetPublishedProfile(ctx context.Context, builderID string) (*domain.Builder, error) {
+	return s.builders.GetByID(ctx, builderID)
+}
+
+// GetStats returns the numbers shown by profile widgets.
+func (s *profileService) GetStats(ctx context.Context, builderID string) (*ProfileStats, error) {
+	sold, err := s.revisions.CountSoldDrops(ctx, builderID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count sold drops: %w", err)
+	}
+	return &ProfileStats{SoldCount: sold}, nil
+}
+
+// ==========================================
+// Preview Links
+// ==========================================
+
+// PreviewToken signs a short-lived token that lets the web server render an
+// unpublished revision. Revision IDs alone aren't secret enough: they appear
+// in the revision list the bot shows. Only people who could publish the
+// revision may mint one.
+func (s *profileService) PreviewToken(ctx context.Context, builderID, revisionID, authorID string) (string, time.Time, error) {
+	if !s.canEdit(builderID, authorID) {
+		return "", time.Time{}, ErrNotProfileAuthor
+	}
+	if _, err := s.revisions.GetProfileRevision(ctx, builderID, revisionID); err != nil {
+		return "", time.Time{}, err
+	}
+	expires := time.Now().UTC().Add(previewTTL).Truncate(time.Second)
+	exp := strconv.FormatInt(expires.Unix(), 10)
+	return exp + "." + s.sign(builderID, revisionID, exp), expires, nil
+}
+
+// GetPreview returns a revision (published or not) if the token is valid.
+func (s *profileService) GetPreview(ctx context.Context, builderID, revisionID, token string) (*domain.ProfileRevision, error) {
+	exp, sig, ok := strings.Cut(token, ".")
+	if !ok {
+		return nil, ErrInvalidPreviewToken
+	}
+	expUnix, err := strconv.ParseInt(exp, 10, 64)
+	if err != nil || time.Now().Unix() > expUnix {
+		return nil, ErrInvalidPreviewToken
+	}
+	if !hmac.Equal([]byte(sig), []byte(s.sign(builderID, revisionID, exp))) {
+		return nil, ErrInvalidPreviewToken
+	}
+	rev, err := s.revisions.GetProfileRevision(ctx, builderID, revisionID)
+	if err != nil {
+		return nil, err
+	}
+	rev.Profile = sanitizeProfile(rev.Profile)
+	return rev, nil
+}
+
+func (s *profileService) sign(builderID, revisionID, exp string) string {
+	mac := hmac.New(sha256.New, s.previewSecret)
+	mac.Write([]byte(builderID + "|" + revisionID + "|" + exp))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// canEdit reports whether authorID may change builderID's page: their own, or any as an admin.
+func (s *profileService) canEdit(builderID, authorID string) bool {
+	return authorID != "" && (authorID == builderID || s.admins[authorID])
+}
+
+// sanitizeProfile runs content through the sanitizer. It is idempotent, so
+// re-running it on stored revisions only removes what today's rules forbid.
+func sanitizeProfile(p domain.ProfileData) domain.ProfileData {
+	return domain.ProfileData{
+		HTML: sanitize.HTML(p.HTML),
+		CSS:  sanitize.CSS(p.CSS, sanitize.ProfileScope),
+	}
+}
+
+// newRevision builds a revision with a time-ordered ID.
+func newRevision(builderID, authorID string, profile domain.ProfileData) (*domain.ProfileRevision, error) {
+	b := make([]byte, 4)
+	if _, err := rand.Read(b); err != nil {
+		return nil, fmt.Errorf("failed to generate revision id: %w", err)
+	}
+	now := time.Now().UTC()
+	return &domain.ProfileRevision{
+		ID:              fmt.Sprintf("%020d-%s", now.UnixNano(), hex.EncodeToString(b)),
+		BuilderID:       builderID,
+		Profile:         profile,
+		AuthorDiscordID: authorID,
+		CreatedAt:       now,
+	}, nil
+}
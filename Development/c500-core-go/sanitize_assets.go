@@ -0,0 +1,61 @@
+package sanitize
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Profiles may only load files from the hosted profile asset libraries
+// (see service/profile_asset_service.go): every other <img src> and CSS url()
+// is dropped. Anything else would let a profile hotlink third-party servers
+// and leak every visitor's IP address and browser to them.
+//
+// assetURL matches a hosted asset URL exactly: the storage prefix, the
+// builder's Discord ID, the content hash and a known extension. Until
+// SetAssetBaseURL is called nothing matches, so no URL is allowed at all.
+var assetURL *regexp.Regexp
+
+// SetAssetBaseURL sets the public URL prefix of hosted profile assets, e.g.
+// "https://media.c500.store/profile-assets/". Call it once from main before
+// sanitizing anything; it is not safe to change while requests are served.
+func SetAssetBaseURL(prefix string) {
+	if prefix == "" {
+		assetURL = nil
+		return
+	}
+	assetURL = regexp.MustCompile(`^` + regexp.QuoteMeta(prefix) +
+		`[0-9]{1,20}/[0-9a-f]{64}\.(?:png|jpg|gif|woff2|woff|ttf|otf)$`)
+}
+
+// hostedAsset returns raw, with whitespace removed, if it is a hosted asset URL.
+func hostedAsset(raw string) (string, bool) {
+	u := strings.TrimSpace(raw)
+	if assetURL == nil || !assetURL.MatchString(u) {
+		return "", false
+	}
+	return u, true
+}
+
+// cssURL matches url(...) with a double-quoted, single-quoted or bare argument.
+// The argument can't contain parens, so "url(url(x))" only matches the inner call.
+var cssURL = regexp.MustCompile(`(?i)url\(\s*(?:"([^"]*)"|'([^']*)'|([^\s"'()]*))\s*\)`)
+
+// rewriteAssetURLs normalizes every url() in a CSS value to url("<hosted asset>").
+// ok=false if any of them points anywhere else. stripped is the value with the
+// url() calls removed, for the function allowlist check.
+func rewriteAssetURLs(value string) (rewritten, stripped string, ok bool) {
+	ok = true
+	rewritten = cssURL.ReplaceAllStringFunc(value, func(call string) string {
+		m := cssURL.FindStringSubmatch(call)
+		u, hosted := hostedAsset(m[1] + m[2] + m[3])
+		if !hosted {
+			ok = false
+			return call
+		}
+		return `url("` + u + `")`
+	})
+	if !ok {
+		return "", "", false
+	}
+	return rewritten, cssURL.ReplaceAllString(rewritten, ""), true
+}
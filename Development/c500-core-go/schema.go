@@ -0,0 +1,25 @@
+package domain
+
+// Schema versions for every Firestore collection we own.
+// Whenever the shape of a struct changes in a way that old documents can't
+// decode cleanly, bump the version here AND register a migration in
+// internal/migrations that rewrites old documents up to the new version.
+//
+// Documents written before versioning existed have no "schema_version"
+// field at all, which Firestore decodes as 0.
+const (
+	// DropSchemaVersion 1: price_in_cents (int), image_urls (slice), status "pending".
+	// Unversioned documents may still use the old float "price" / "image_url" /
+	// "pending_payment" shape.
+	DropSchemaVersion = 1
+
+	// BuilderSchemaVersion 1: first versioned shape of the "users" collection.
+	// BuilderSchemaVersion 2: profile_data html/css stored sanitized and scoped.
+	// BuilderSchemaVersion 3: profile_data only loads hosted profile assets (no hotlinking);
+	// profile_asset_bytes/profile_asset_count track the asset library quota.
+	BuilderSchemaVersion = 3
+
+	// OrderSchemaVersion 1: first versioned shape of the "orders" collection.
+	// OrderSchemaVersion 2: production_status (separate from escrow_status) and drop_type.
+	OrderSchemaVersion = 2
+)
@@ -0,0 +1,131 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"c500-core-go/internal/domain"
+	"c500-core-go/internal/search"
+)
+
+// SearchHandler serves the public drop search used by the web shop and the bot.
+type SearchHandler struct {
+	searcher search.Searcher
+}
+
+// NewSearchHandler is the constructor.
+func NewSearchHandler(s search.Searcher) *SearchHandler {
+	return &SearchHandler{
+		searcher: s,
+	}
+}
+
+// RegisterRoutes connects the HTTP URLs to the handler functions.
+// This is called in main.go.
+func (h *SearchHandler) RegisterRoutes(router *gin.RouterGroup) {
+	router.GET("/drops", h.SearchDrops)
+}
+
+// ==========================================
+// Handler Functions
+// ==========================================
+
+// SearchDrops handles GET /api/v1/drops
+//
+// Query parameters (all optional, repeatable ones are OR'd together):
+//
+//	q=holy panda          free text over title, description and specs
+//	layout=65%&layout=75%
+//	switches=Gateron Yellow
+//	kind=keyboard          keyboard | keycap_set | switch_pack
+//	type=commission        commission | ready_to_ship
+//	seller=<discord id>
+//	min_price=10000&max_price=50000   (in cents)
+//	sort=newest            newest | price_asc | price_desc
+//	limit=24&cursor=<next_cursor from previous page>
+func (h *SearchHandler) SearchDrops(c *gin.Context) {
+	// 1. Parse and validate the query string.
+	q := search.Query{
+		Text:     strings.TrimSpace(c.Query("q")),
+		SellerID: c.Query("seller"),
+		Sort:     c.DefaultQuery("sort", search.SortNewest),
+		Cursor:   c.Query("cursor"),
+	}
+
+	switch q.Sort {
+	case search.SortNewest, search.SortPriceAsc, search.SortPriceDesc:
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "sort must be newest, price_asc or price_desc"})
+		return
+	}
+
+	// Filters use the same vocabulary as stored specs, so "60" or "gat yellows" work.
+	for _, raw := range c.QueryArray("layout") {
+		if v, ok := domain.LayoutVocab.Canonicalize(raw); ok {
+			q.Layouts = append(q.Layouts, v)
+		} else {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "unknown layout: " + raw})
+			return
+		}
+	}
+	for _, raw := range c.QueryArray("switches") {
+		if v, ok := domain.SwitchVocab.Canonicalize(raw); ok {
+			q.Switches = append(q.Switches, v)
+		} else {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "unknown switch: " + raw})
+			return
+		}
+	}
+	for _, raw := range c.QueryArray("kind") {
+		q.Kinds = append(q.Kinds, domain.ProductKind(raw))
+	}
+	for _, raw := range c.QueryArray("type") {
+		q.Types = append(q.Types, domain.DropType(raw))
+	}
+
+	var err error
+	if q.MinCents, err = parseCents(c.Query("min_price")); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "min_price must be a whole number of cents"})
+		return
+	}
+	if q.MaxCents, err = parseCents(c.Query("max_price")); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "max_price must be a whole number of cents"})
+		return
+	}
+	if limit := c.Query("limit"); limit != "" {
+		if q.Limit, err = strconv.Atoi(limit); err != nil || q.Limit <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "limit must be a positive number"})
+			return
+		}
+	}
+
+	// 2. Run the search.
+	result, err := h.searcher.Search(c.Request.Context(), q)
+	if err != nil {
+		if errors.Is(err, search.ErrInvalidCursor) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid or expired cursor; start again from the first page"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Search failed"})
+		return
+	}
+
+	// 3. Success.
+	c.JSON(http.StatusOK, result)
+}
+
+// parseCents parses an optional non-negative integer price filter.
+func parseCents(raw string) (int64, error) {
+	if raw == "" {
+		return 0, nil
+	}
+	v, err := strconv.ParseInt(raw, 10, 64)
+	if err != nil || v < 0 {
+		return 0, errors.New("invalid price")
+	}
+	return v, nil
+}
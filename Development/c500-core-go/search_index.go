@@ -0,0 +1,440 @@
+package search
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"c500-core-go/internal/domain"
+)
+
+// ErrInvalidCursor is returned when a client sends a cursor we didn't issue
+// (or one from a different sort order). The handler maps it to HTTP 400.
+var ErrInvalidCursor = errors.New("invalid pagination cursor")
+
+// Sort orders supported by the search API.
+const (
+	SortNewest    = "newest"
+	SortPriceAsc  = "price_asc"
+	SortPriceDesc = "price_desc"
+)
+
+// Facet names returned in every search response.
+const (
+	FacetLayout   = "layout"
+	FacetSwitches = "switches"
+	FacetKind     = "kind"
+	FacetType     = "type"
+	FacetSeller   = "seller"
+)
+
+// Query is everything a buyer can filter on.
+// Empty fields mean "don't filter on this".
+type Query struct {
+	Text     string
+	Layouts  []string // OR within a facet: 60% OR 65%
+	Switches []string
+	Kinds    []domain.ProductKind
+	Types    []domain.DropType
+	SellerID string
+	MinCents int64 // 0 = no minimum
+	MaxCents int64 // 0 = no maximum
+	Sort     string
+	Limit    int
+	Cursor   string
+}
+
+// Result is one page of hits plus facet counts for the WHOLE filtered result set.
+type Result struct {
+	Drops      []domain.Drop             `json:"drops"`
+	Total      int                       `json:"total"`
+	Facets     map[string]map[string]int `json:"facets"`
+	NextCursor string                    `json:"next_cursor,omitempty"`
+}
+
+// Searcher is the read side used by the HTTP handler.
+type Searcher interface {
+	Search(ctx context.Context, q Query) (*Result, error)
+}
+
+// Indexer is the write side used by the sync loop.
+type Indexer interface {
+	Upsert(drop domain.Drop)
+	Remove(dropID string)
+	// Replace swaps the whole index for the given drops, e.g. after a
+	// reconnect, when removals during the gap were never seen.
+	Replace(drops []domain.Drop)
+}
+
+// entry is the denormalized, pre-tokenized form of a drop we keep in memory.
+type entry struct {
+	drop     domain.Drop
+	tokens   map[string]struct{}
+	layout   string
+	switches string
+	kind     string
+}
+
+// MemoryIndex is an in-process inverted index over AVAILABLE drops.
+// The marketplace is small enough (thousands, not millions, of live drops)
+// that every Cloud Run instance can hold its own copy, kept fresh by a
+// Firestore snapshot listener (see sync.go). If that stops being true,
+// swap this for a hosted index behind the same Searcher/Indexer interfaces.
+type MemoryIndex struct {
+	mu       sync.RWMutex
+	entries  map[string]*entry
+	postings map[string]map[string]struct{} // token -> set of drop IDs
+}
+
+// NewMemoryIndex constructor.
+func NewMemoryIndex() *MemoryIndex {
+	return &MemoryIndex{
+		entries:  make(map[string]*entry),
+		postings: make(map[string]map[string]struct{}),
+	}
+}
+
+// =================================================================
+// Write side
+// =================================================================
+
+// Upsert adds or replaces a drop. Drops that aren't available are removed,
+// so callers can blindly forward every change they see.
+func (idx *MemoryIndex) Upsert(drop domain.Drop) {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	idx.upsertLocked(drop)
+}
+
+// Replace rebuilds the index from scratch. Searches running meanwhile see
+// either the old contents or the new ones, never an empty index.
+func (idx *MemoryIndex) Replace(drops []domain.Drop) {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	idx.entries = make(map[string]*entry, len(drops))
+	idx.postings = make(map[string]map[string]struct{})
+	for _, drop := range drops {
+		idx.upsertLocked(drop)
+	}
+}
+
+func (idx *MemoryIndex) upsertLocked(drop domain.Drop) {
+	idx.removeLocked(drop.ID)
+	if drop.Status != domain.StatusAvailable {
+		return
+	}
+
+	e := &entry{drop: drop, tokens: make(map[string]struct{})}
+	if drop.Spec != nil {
+		e.kind = string(drop.Spec.Kind)
+		switch {
+		case drop.Spec.Keyboard != nil:
+			e.layout = drop.Spec.Keyboard.Layout
+			e.switches = drop.Spec.Keyboard.Switches
+		case drop.Spec.SwitchPack != nil:
+			e.switches = drop.Spec.SwitchPack.Switch
+		}
+	}
+
+	// Index the title, description and every spec value so "holy panda 65" finds
+	// a 65% board with Holy Pandas even if the title doesn't mention either.
+	text := []string{drop.Title, drop.Description}
+	for _, f := range drop.Spec.DisplayFields() {
+		text = append(text, f.Value)
+	}
+	for _, tok := range tokenize(strings.Join(text, " ")) {
+		e.tokens[tok] = struct{}{}
+		if idx.postings[tok] == nil {
+			idx.postings[tok] = make(map[string]struct{})
+		}
+		idx.postings[tok][drop.ID] = struct{}{}
+	}
+
+	idx.entries[drop.ID] = e
+}
+
+// Remove deletes a drop from the index (sold, archived, deleted...).
+func (idx *MemoryIndex) Remove(dropID string) {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	idx.removeLocked(dropID)
+}
+
+func (idx *MemoryIndex) removeLocked(dropID string) {
+	e, ok := idx.entries[dropID]
+	if !ok {
+		return
+	}
+	for tok := range e.tokens {
+		delete(idx.postings[tok], dropID)
+		if len(idx.postings[tok]) == 0 {
+			delete(idx.postings, tok)
+		}
+	}
+	delete(idx.entries, dropID)
+}
+
+// =================================================================
+// Read side
+// =================================================================
+
+// Search filters, facets, sorts and paginates in one pass over the candidates.
+func (idx *MemoryIndex) Search(ctx context.Context, q Query) (*Result, error) {
+	if q.Sort == "" {
+		q.Sort = SortNewest
+	}
+	if q.Limit <= 0 || q.Limit > 100 {
+		q.Limit = 24
+	}
+	after, err := decodeCursor(q.Cursor, q.Sort)
+	if err != nil {
+		return nil, err
+	}
+
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+
+	// 1. Text search narrows the candidate set using the postings lists.
+	candidates := idx.textCandidates(q.Text)
+
+	// 2. Apply filters. Facet counts are "disjunctive": each facet is counted with
+	// every filter applied EXCEPT its own, so picking "65%" still shows how many
+	// 75% boards exist instead of collapsing the layout facet to one value.
+	facets := map[string]map[string]int{
+		FacetLayout: {}, FacetSwitches: {}, FacetKind: {}, FacetType: {}, FacetSeller: {},
+	}
+	var hits []*entry
+	for _, e := range candidates {
+		failed := idx.failedFilters(e, q)
+		if len(failed) == 0 {
+			hits = append(hits, e)
+		}
+		countFacet(facets, FacetLayout, e.layout, failed)
+		countFacet(facets, FacetSwitches, e.switches, failed)
+		countFacet(facets, FacetKind, e.kind, failed)
+		countFacet(facets, FacetType, string(e.drop.Type), failed)
+		countFacet(facets, FacetSeller, e.drop.SellerDiscordID, failed)
+	}
+
+	// 3. Sort with the drop ID as a tie-breaker so the order (and the cursor) is stable.
+	sort.Slice(hits, func(i, j int) bool { return less(hits[i], hits[j], q.Sort) })
+
+	// 4. Seek past the cursor (keyset pagination: stable even as new drops arrive).
+	start := 0
+	if after != nil {
+		start = sort.Search(len(hits), func(i int) bool { return after.before(hits[i], q.Sort) })
+	}
+	end := start + q.Limit
+	if end > len(hits) {
+		end = len(hits)
+	}
+
+	result := &Result{Total: len(hits), Facets: facets, Drops: make([]domain.Drop, 0, end-start)}
+	for _, e := range hits[start:end] {
+		result.Drops = append(result.Drops, e.drop)
+	}
+	if end < len(hits) {
+		result.NextCursor = encodeCursor(hits[end-1], q.Sort)
+	}
+	return result, nil
+}
+
+// textCandidates returns every entry matching ALL query tokens (prefix match on the last one,
+// so "holy pan" matches while the buyer is still typing). It intersects the
+// postings lists, walking the smallest one.
+func (idx *MemoryIndex) textCandidates(text string) []*entry {
+	tokens := tokenize(text)
+	if len(tokens) == 0 {
+		all := make([]*entry, 0, len(idx.entries))
+		for _, e := range idx.entries {
+			all = append(all, e)
+		}
+		return all
+	}
+
+	sets := make([]map[string]struct{}, 0, len(tokens))
+	for _, tok := range tokens[:len(tokens)-1] {
+		ids := idx.postings[tok]
+		if len(ids) == 0 {
+			return nil
+		}
+		sets = append(sets, ids)
+	}
+	last := idx.prefixPostings(tokens[len(tokens)-1])
+	if len(last) == 0 {
+		return nil
+	}
+	sets = append(sets, last)
+	sort.Slice(sets, func(i, j int) bool { return len(sets[i]) < len(sets[j]) })
+
+	var out []*entry
+	for id := range sets[0] {
+		matched := true
+		for _, ids := range sets[1:] {
+			if _, ok := ids[id]; !ok {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			out = append(out, idx.entries[id])
+		}
+	}
+	return out
+}
+
+// prefixPostings returns the IDs of drops with any token starting with prefix
+// (the exact token included).
+func (idx *MemoryIndex) prefixPostings(prefix string) map[string]struct{} {
+	union := make(map[string]struct{})
+	for tok, ids := range idx.postings {
+		if strings.HasPrefix(tok, prefix) {
+			for id := range ids {
+				union[id] = struct{}{}
+			}
+		}
+	}
+	return union
+}
+
+// failedFilters returns the set of facet names (plus "other") whose filter rejected e.
+func (idx *MemoryIndex) failedFilters(e *entry, q Query) map[string]bool {
+	failed := map[string]bool{}
+	if len(q.Layouts) > 0 && !containsFold(q.Layouts, e.layout) {
+		failed[FacetLayout] = true
+	}
+	if len(q.Switches) > 0 && !containsFold(q.Switches, e.switches) {
+		failed[FacetSwitches] = true
+	}
+	if len(q.Kinds) > 0 {
+		ok := false
+		for _, k := range q.Kinds {
+			ok = ok || string(k) == e.kind
+		}
+		if !ok {
+			failed[FacetKind] = true
+		}
+	}
+	if len(q.Types) > 0 {
+		ok := false
+		for _, t := range q.Types {
+			ok = ok || t == e.drop.Type
+		}
+		if !ok {
+			failed[FacetType] = true
+		}
+	}
+	if q.SellerID != "" && q.SellerID != e.drop.SellerDiscordID {
+		failed[FacetSeller] = true
+	}
+	if (q.MinCents > 0 && e.drop.PriceInCents < q.MinCents) || (q.MaxCents > 0 && e.drop.PriceInCents > q.MaxCents) {
+		failed["price"] = true
+	}
+	return failed
+}
+
+// countFacet counts value under facet if the entry passed every filter except (possibly) facet's own.
+func countFacet(facets map[string]map[string]int, facet, value string, failed map[string]bool) {
+	if value == "" {
+		return
+	}
+	if len(failed) == 0 || (len(failed) == 1 && failed[facet]) {
+		facets[facet][value]++
+	}
+}
+
+// =================================================================
+// Sorting & cursors
+// =================================================================
+
+func less(a, b *entry, order string) bool {
+	switch order {
+	case SortPriceAsc:
+		if a.drop.PriceInCents != b.drop.PriceInCents {
+			return a.drop.PriceInCents < b.drop.PriceInCents
+		}
+	case SortPriceDesc:
+		if a.drop.PriceInCents != b.drop.PriceInCents {
+			return a.drop.PriceInCents > b.drop.PriceInCents
+		}
+	default: // newest
+		if !a.drop.CreatedAt.Equal(b.drop.CreatedAt) {
+			return a.drop.CreatedAt.After(b.drop.CreatedAt)
+		}
+	}
+	return a.drop.ID < b.drop.ID
+}
+
+// cursor is the sort key of the last hit on the previous page.
+// It's opaque to clients (base64 JSON) and tied to the sort order it was issued for.
+type cursor struct {
+	Sort      string    `json:"s"`
+	ID        string    `json:"id"`
+	Price     int64     `json:"p,omitempty"`
+	CreatedAt time.Time `json:"c,omitempty"`
+}
+
+// before reports whether e sorts strictly after the cursor position.
+func (c *cursor) before(e *entry, order string) bool {
+	pivot := &entry{drop: domain.Drop{ID: c.ID, PriceInCents: c.Price, CreatedAt: c.CreatedAt}}
+	return less(pivot, e, order)
+}
+
+func encodeCursor(e *entry, order string) string {
+	raw, _ := json.Marshal(cursor{Sort: order, ID: e.drop.ID, Price: e.drop.PriceInCents, CreatedAt: e.drop.CreatedAt})
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+func decodeCursor(s, order string) (*cursor, error) {
+	if s == "" {
+		return nil, nil
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	var c cursor
+	if err := json.Unmarshal(raw, &c); err != nil || c.ID == "" {
+		return nil, ErrInvalidCursor
+	}
+	if c.Sort != order {
+		return nil, fmt.Errorf("%w: cursor was issued for sort %q", ErrInvalidCursor, c.Sort)
+	}
+	return &c, nil
+}
+
+// =================================================================
+// Helpers
+// =================================================================
+
+// tokenize lowercases and splits on anything that isn't a letter, digit or '%'
+// (so "65%" stays a searchable token).
+func tokenize(s string) []string {
+	fields := strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '%'
+	})
+	out := fields[:0]
+	for _, f := range fields {
+		if len(f) > 1 || unicode.IsDigit(rune(f[0])) {
+			out = append(out, f)
+		}
+	}
+	return out
+}
+
+func containsFold(values []string, v string) bool {
+	for _, candidate := range values {
+		if strings.EqualFold(candidate, v) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,107 @@
+package search
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"c500-core-go/internal/domain"
+)
+
+// SyncFromFirestore keeps an Indexer in step with the "drops" collection.
+//
+// It opens a Firestore snapshot listener, which first delivers every existing
+// document (the initial load) and then streams each add/modify/remove as it
+// happens. Because the listener sees writes from EVERY instance of the Core,
+// a drop marked "pending" by one Cloud Run instance disappears from search on
+// all of them, without any service having to remember to re-index.
+//
+// It blocks until ctx is cancelled, reconnecting with backoff if the stream drops.
+// Run it in its own goroutine from main.go.
+func SyncFromFirestore(ctx context.Context, client *firestore.Client, idx Indexer) {
+	backoff := time.Second
+	for {
+		err := listen(ctx, client, idx)
+		if ctx.Err() != nil {
+			return // Shutting down
+		}
+		log.Printf("search sync: listener stopped (%v), reconnecting in %s", err, backoff)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+		if backoff < time.Minute {
+			backoff *= 2
+		}
+	}
+}
+
+// listen runs one snapshot stream until it errors.
+//
+// The first snapshot of every stream holds every document, so the index is
+// rebuilt from it: after a reconnect, drops deleted while we were away would
+// otherwise never get their DocumentRemoved and stay searchable forever.
+func listen(ctx context.Context, client *firestore.Client, idx Indexer) error {
+	// We listen to the whole collection (not just status == available) so we SEE
+	// the transition from available -> pending/sold and can remove the drop.
+	iter := client.Collection("drops").Snapshots(ctx)
+	defer iter.Stop()
+
+	first := true
+	for {
+		snap, err := iter.Next()
+		if err != nil {
+			if status.Code(err) == codes.Canceled {
+				return nil
+			}
+			return fmt.Errorf("snapshot iteration error: %w", err)
+		}
+
+		if first {
+			first = false
+			var drops []domain.Drop
+			for _, change := range snap.Changes {
+				if drop, ok := decodeDrop(change.Doc); ok {
+					drops = append(drops, drop)
+				}
+			}
+			// Replace drops non-available drops itself.
+			idx.Replace(drops)
+			continue
+		}
+
+		for _, change := range snap.Changes {
+			switch change.Kind {
+			case firestore.DocumentAdded, firestore.DocumentModified:
+				drop, ok := decodeDrop(change.Doc)
+				if !ok {
+					idx.Remove(change.Doc.Ref.ID)
+					continue
+				}
+				// Upsert removes non-available drops itself.
+				idx.Upsert(drop)
+			case firestore.DocumentRemoved:
+				idx.Remove(change.Doc.Ref.ID)
+			}
+		}
+	}
+}
+
+// decodeDrop reads a drop document, logging the ones that can't be indexed.
+func decodeDrop(doc *firestore.DocumentSnapshot) (domain.Drop, bool) {
+	var drop domain.Drop
+	if err := doc.DataTo(&drop); err != nil {
+		// Old-schema documents that haven't been migrated yet land here.
+		log.Printf("search sync: skipping drop %s: %v", doc.Ref.ID, err)
+		return drop, false
+	}
+	drop.ID = doc.Ref.ID
+	return drop, true
+}
@@ -0,0 +1,36 @@
+package domain
+
+import "time"
+
+// Slug maps a vanity username (c500.store/builder/<slug>) to a builder.
+// Stored in the "slugs" collection, keyed by the case-folded slug, so
+// uniqueness is enforced by Firestore document IDs.
+//
+// When a builder renames, their old slug isn't deleted: it's retired and keeps
+// redirecting to the new one until RedirectUntil, so links shared on stream
+// don't break. After that it can be claimed by anyone.
+type Slug struct {
+	// Slug is the case-folded slug (also the document ID).
+	Slug      string `json:"slug" firestore:"slug"`
+	BuilderID string `json:"builder_id" firestore:"builder_id"`
+
+	CreatedAt time.Time `json:"created_at" firestore:"created_at"`
+
+	// Set when the builder moved to a different slug.
+	RetiredAt     *time.Time `json:"retired_at,omitempty" firestore:"retired_at,omitempty"`
+	RedirectUntil *time.Time `json:"redirect_until,omitempty" firestore:"redirect_until,omitempty"`
+}
+
+// Redirects reports whether a retired slug should still send visitors to its builder.
+func (s *Slug) Redirects(now time.Time) bool {
+	return s.RetiredAt != nil && s.RedirectUntil != nil && now.Before(*s.RedirectUntil)
+}
+
+// Blocks reports whether this slug stops builderID from claiming it.
+// A builder can always take back their own old slug.
+func (s *Slug) Blocks(builderID string, now time.Time) bool {
+	if s.BuilderID == builderID {
+		return false
+	}
+	return s.RetiredAt == nil || s.Redirects(now)
+}
@@ -0,0 +1,80 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"c500-core-go/internal/service"
+)
+
+// SlugHandler exposes the vanity username registry.
+type SlugHandler struct {
+	slugService service.SlugService
+}
+
+// NewSlugHandler is the constructor.
+func NewSlugHandler(ss service.SlugService) *SlugHandler {
+	return &SlugHandler{
+		slugService: ss,
+	}
+}
+
+// RegisterRoutes connects the HTTP URLs to the handler functions.
+// This is called in main.go.
+func (h *SlugHandler) RegisterRoutes(router *gin.RouterGroup) {
+	router.GET("/slugs/:slug", h.Resolve)
+}
+
+// RegisterInternalRoutes connects the rename route. It acts for whichever
+// builder is in the URL, so main.go puts it behind the internal API key.
+func (h *SlugHandler) RegisterInternalRoutes(router *gin.RouterGroup) {
+	router.PUT("/builders/:discordID/slug", h.ClaimSlug)
+}
+
+// claimSlugRequest is sent by the bot when a builder picks or changes their username.
+type claimSlugRequest struct {
+	Slug string `json:"slug" binding:"required"`
+}
+
+// ClaimSlug handles PUT /api/v1/builders/:discordID/slug
+func (h *SlugHandler) ClaimSlug(c *gin.Context) {
+	var req claimSlugRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	slug, err := h.slugService.ClaimSlug(c.Request.Context(), c.Param("discordID"), req.Slug)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrSlugInvalid), errors.Is(err, service.ErrSlugReserved):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case errors.Is(err, service.ErrSlugTaken):
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		case errors.Is(err, service.ErrSlugCooldown):
+			c.JSON(http.StatusTooManyRequests, gin.H{"error": err.Error()})
+		case errors.Is(err, service.ErrBuilderNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": "Builder not found"})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to claim username"})
+		}
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"slug": slug})
+}
+
+// Resolve handles GET /api/v1/slugs/:slug
+func (h *SlugHandler) Resolve(c *gin.Context) {
+	res, err := h.slugService.Resolve(c.Request.Context(), c.Param("slug"))
+	if err != nil {
+		if errors.Is(err, service.ErrSlugNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to resolve username"})
+		return
+	}
+	c.JSON(http.StatusOK, res)
+}
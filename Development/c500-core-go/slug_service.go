@@ -0,0 +1,225 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"c500-core-go/internal/domain"
+)
+
+var (
+	ErrSlugInvalid  = errors.New("usernames must be 3-32 characters: letters, numbers and single dashes")
+	ErrSlugReserved = errors.New("that username is reserved")
+	ErrSlugTaken    = errors.New("that username is already taken")
+	ErrSlugCooldown = errors.New("you changed your username recently, try again later")
+	ErrSlugNotFound = errors.New("no builder has that username")
+)
+
+const (
+	// slugRedirectGrace is how long a retired slug keeps redirecting (and stays
+	// unclaimable by others) after a rename. Long enough for VODs and pinned
+	// Discord messages to age out.
+	slugRedirectGrace = 90 * 24 * time.Hour
+
+	// slugRenameCooldown stops one builder from hoarding names by renaming in a loop,
+	// since every retired slug is held for the grace period.
+	slugRenameCooldown = 30 * 24 * time.Hour
+)
+
+// reservedSlugs can never be claimed: site routes, and names that would let
+// someone impersonate staff or the platform.
+var reservedSlugs = map[string]bool{
+	"about": true, "admin": true, "api": true, "assets": true, "builder": true,
+	"builders": true, "cancel": true, "checkout": true, "drop": true, "drops": true,
+	"edit": true, "help": true, "login": true, "logout": true, "me": true,
+	"media": true, "mod": true, "mods": true, "new": true, "null": true,
+	"preview": true, "privacy": true, "root": true, "settings": true, "staff": true,
+	"static": true, "stripe": true, "success": true, "support": true, "system": true,
+	"terms": true, "twitch": true, "discord": true, "undefined": true, "www": true,
+}
+
+// reservedSlugParts are rejected anywhere in a slug ("c500-official", "xadminx").
+var reservedSlugParts = []string{"c500", "admin", "moderator", "official"}
+
+// SlugRepository defines DB operations for the slug registry.
+// Implemented in internal/database/firestore.go
+type SlugRepository interface {
+	// GetSlug returns ErrSlugNotFound if nobody ever claimed it.
+	GetSlug(ctx context.Context, slug string) (*domain.Slug, error)
+	// ClaimSlug atomically points slug at builderID, retires the builder's
+	// previous slug until redirectUntil, and returns ErrSlugTaken if another
+	// builder holds it (see domain.Slug.Blocks).
+	ClaimSlug(ctx context.Context, builderID, slug string, now, redirectUntil time.Time) error
+}
+
+// SlugResolution is where a requested slug points.
+type SlugResolution struct {
+	BuilderID string `json:"builder_id"`
+	// Slug is the builder's canonical slug. When it differs from what was
+	// requested (old slug, different case), the web server should 301 to it.
+	Slug     string `json:"slug"`
+	Redirect bool   `json:"redirect"`
+}
+
+// SlugService defines the methods handlers use for vanity usernames.
+type SlugService interface {
+	ClaimSlug(ctx context.Context, builderID, desired string) (string, error)
+	Resolve(ctx context.Context, requested string) (*SlugResolution, error)
+}
+
+// slugService is the concrete implementation.
+type slugService struct {
+	slugs    SlugRepository
+	builders BuilderRepository
+}
+
+// NewSlugService constructor used in main.go.
+func NewSlugService(sr SlugRepository, br BuilderRepository) *slugService {
+	return &slugService{
+		slugs:    sr,
+		builders: br,
+	}
+}
+
+// NormalizeSlug case-folds a requested slug and validates it.
+// Only ASCII is allowed, so look-alike Unicode can't impersonate another builder.
+func NormalizeSlug(raw string) (string, error) {
+	slug := strings.ToLower(strings.TrimSpace(raw))
+	if len(slug) < 3 || len(slug) > 32 {
+		return "", ErrSlugInvalid
+	}
+
+	allDigits := true
+	for i := 0; i < len(slug); i++ {
+		c := slug[i]
+		switch {
+		case c >= '0' && c <= '9':
+		case c >= 'a' && c <= 'z':
+			allDigits = false
+		case c == '-':
+			allDigits = false
+			if i == 0 || i == len(slug)-1 || slug[i-1] == '-' {
+				return "", ErrSlugInvalid
+			}
+		default:
+			return "", ErrSlugInvalid
+		}
+	}
+	// All-digit paths are Discord IDs (see Resolve), so they can't be slugs.
+	if allDigits {
+		return "", ErrSlugReserved
+	}
+
+	if reservedSlugs[slug] {
+		return "", ErrSlugReserved
+	}
+	for _, part := range reservedSlugParts {
+		if strings.Contains(slug, part) {
+			return "", ErrSlugReserved
+		}
+	}
+	return slug, nil
+}
+
+// ==========================================
+// Business Logic
+// ==========================================
+
+// ClaimSlug gives a builder a vanity username, or renames them.
+// Returns the normalized slug that was claimed.
+func (s *slugService) ClaimSlug(ctx context.Context, builderID, desired string) (string, error) {
+	slug, err := NormalizeSlug(desired)
+	if err != nil {
+		return "", err
+	}
+
+	builder, err := s.builders.GetByID(ctx, builderID)
+	if err != nil {
+		return "", err
+	}
+	if builder.Slug == slug {
+		return slug, nil
+	}
+
+	now := time.Now().UTC()
+	// The first claim is free; renames are rate limited.
+	if builder.Slug != "" && builder.SlugChangedAt != nil && now.Sub(*builder.SlugChangedAt) < slugRenameCooldown {
+		return "", ErrSlugCooldown
+	}
+
+	if err := s.slugs.ClaimSlug(ctx, builderID, slug, now, now.Add(slugRedirectGrace)); err != nil {
+		if errors.Is(err, ErrSlugTaken) || errors.Is(err, ErrBuilderNotFound) {
+			return "", err
+		}
+		return "", fmt.Errorf("failed to claim slug: %w", err)
+	}
+	return slug, nil
+}
+
+// Resolve turns the :username in a profile URL into a builder.
+// It accepts the current slug in any case, a retired slug inside its grace
+// period, or a raw Discord ID (for builders who haven't picked a slug yet).
+func (s *slugService) Resolve(ctx context.Context, requested string) (*SlugResolution, error) {
+	folded := strings.ToLower(strings.TrimSpace(requested))
+	if folded == "" {
+		return nil, ErrSlugNotFound
+	}
+
+	// Discord IDs: always valid, but redirect to the slug once there is one.
+	if isSnowflake(folded) {
+		builder, err := s.builders.GetByID(ctx, folded)
+		if err != nil {
+			if errors.Is(err, ErrBuilderNotFound) {
+				return nil, ErrSlugNotFound
+			}
+			return nil, err
+		}
+		if builder.Slug == "" {
+			return &SlugResolution{BuilderID: builder.ID, Slug: builder.ID}, nil
+		}
+		return &SlugResolution{BuilderID: builder.ID, Slug: builder.Slug, Redirect: true}, nil
+	}
+
+	rec, err := s.slugs.GetSlug(ctx, folded)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	if rec.RetiredAt == nil {
+		return &SlugResolution{BuilderID: rec.BuilderID, Slug: rec.Slug, Redirect: folded != requested}, nil
+	}
+	if !rec.Redirects(now) {
+		return nil, ErrSlugNotFound
+	}
+
+	// Retired slug: look up where the builder lives now.
+	builder, err := s.builders.GetByID(ctx, rec.BuilderID)
+	if err != nil {
+		if errors.Is(err, ErrBuilderNotFound) {
+			return nil, ErrSlugNotFound
+		}
+		return nil, err
+	}
+	canonical := builder.Slug
+	if canonical == "" {
+		canonical = builder.ID
+	}
+	return &SlugResolution{BuilderID: builder.ID, Slug: canonical, Redirect: true}, nil
+}
+
+// isSnowflake spots Discord IDs (17-20 digits).
+func isSnowflake(s string) bool {
+	if len(s) < 17 || len(s) > 20 {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,240 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ProductKind says WHAT is being sold, independent of the sales model (DropType).
+// A commission can be a full keyboard build, and an RTS drop can be a keycap set.
+type ProductKind string
+
+const (
+	KindKeyboard   ProductKind = "keyboard"
+	KindKeycapSet  ProductKind = "keycap_set"
+	KindSwitchPack ProductKind = "switch_pack"
+)
+
+// SolderType is the hot-swap vs soldered distinction buyers care most about.
+type SolderType string
+
+const (
+	SolderHotSwap  SolderType = "hot_swap"
+	SolderSoldered SolderType = "soldered"
+)
+
+// ErrInvalidSpec is wrapped by every spec validation failure so the handler
+// layer can map it to HTTP 400 with errors.Is.
+var ErrInvalidSpec = errors.New("invalid drop spec")
+
+// KeyboardSpec describes a complete keyboard (built or commissioned).
+type KeyboardSpec struct {
+	Layout      string     `json:"layout" firestore:"layout"`
+	Case        string     `json:"case" firestore:"case"`
+	Plate       string     `json:"plate" firestore:"plate"`
+	Switches    string     `json:"switches" firestore:"switches"`
+	Stabilizers string     `json:"stabilizers,omitempty" firestore:"stabilizers,omitempty"`
+	Lube        string     `json:"lube,omitempty" firestore:"lube,omitempty"`
+	Keycaps     string     `json:"keycaps,omitempty" firestore:"keycaps,omitempty"` // Keycap profile, e.g. "Cherry"
+	Solder      SolderType `json:"solder" firestore:"solder"`
+}
+
+// KeycapSetSpec describes a standalone keycap set.
+type KeycapSetSpec struct {
+	Name     string   `json:"name" firestore:"name"` // e.g. "GMK Olivia++"; free text, not a vocabulary
+	Profile  string   `json:"profile" firestore:"profile"`
+	Material string   `json:"material" firestore:"material"`
+	Kits     []string `json:"kits,omitempty" firestore:"kits,omitempty"` // e.g. "Base", "Novelties"
+}
+
+// SwitchPackSpec describes a pack of loose switches.
+type SwitchPackSpec struct {
+	Switch   string `json:"switch" firestore:"switch"`
+	Type     string `json:"type" firestore:"type"` // Linear / Tactile / Clicky
+	Quantity int    `json:"quantity" firestore:"quantity"`
+	Lube     string `json:"lube,omitempty" firestore:"lube,omitempty"`
+	Filmed   bool   `json:"filmed" firestore:"filmed"`
+}
+
+// DropSpec is the typed spec attached to a Drop.
+// Exactly ONE of the pointers must be set, and it must match Kind.
+type DropSpec struct {
+	Kind       ProductKind     `json:"kind" firestore:"kind"`
+	Keyboard   *KeyboardSpec   `json:"keyboard,omitempty" firestore:"keyboard,omitempty"`
+	KeycapSet  *KeycapSetSpec  `json:"keycap_set,omitempty" firestore:"keycap_set,omitempty"`
+	SwitchPack *SwitchPackSpec `json:"switch_pack,omitempty" firestore:"switch_pack,omitempty"`
+}
+
+// Normalize validates the spec AND rewrites every vocabulary field to its
+// canonical spelling in place. Call this before saving a drop; after it
+// returns nil, the spec is safe to store and filter on.
+func (s *DropSpec) Normalize() error {
+	// 1. Exactly one sub-spec, matching the declared kind.
+	set := 0
+	for _, present := range []bool{s.Keyboard != nil, s.KeycapSet != nil, s.SwitchPack != nil} {
+		if present {
+			set++
+		}
+	}
+	if set != 1 {
+		return fmt.Errorf("%w: exactly one of keyboard, keycap_set or switch_pack must be provided", ErrInvalidSpec)
+	}
+
+	// 2. Canonicalize the kind-specific fields.
+	switch s.Kind {
+	case KindKeyboard:
+		if s.Keyboard == nil {
+			return fmt.Errorf("%w: kind is keyboard but keyboard spec is missing", ErrInvalidSpec)
+		}
+		return s.Keyboard.normalize()
+	case KindKeycapSet:
+		if s.KeycapSet == nil {
+			return fmt.Errorf("%w: kind is keycap_set but keycap_set spec is missing", ErrInvalidSpec)
+		}
+		return s.KeycapSet.normalize()
+	case KindSwitchPack:
+		if s.SwitchPack == nil {
+			return fmt.Errorf("%w: kind is switch_pack but switch_pack spec is missing", ErrInvalidSpec)
+		}
+		return s.SwitchPack.normalize()
+	default:
+		return fmt.Errorf("%w: unknown kind %q", ErrInvalidSpec, s.Kind)
+	}
+}
+
+func (k *KeyboardSpec) normalize() error {
+	var err error
+	// Required fields
+	if k.Layout, err = canonical(LayoutVocab, "layout", k.Layout, true); err != nil {
+		return err
+	}
+	if k.Case, err = canonical(CaseMaterialVocab, "case", k.Case, true); err != nil {
+		return err
+	}
+	if k.Plate, err = canonical(PlateVocab, "plate", k.Plate, true); err != nil {
+		return err
+	}
+	if k.Switches, err = canonical(SwitchVocab, "switches", k.Switches, true); err != nil {
+		return err
+	}
+	// Optional fields
+	if k.Stabilizers, err = canonical(StabilizerVocab, "stabilizers", k.Stabilizers, false); err != nil {
+		return err
+	}
+	if k.Lube, err = canonical(LubeVocab, "lube", k.Lube, false); err != nil {
+		return err
+	}
+	if k.Keycaps, err = canonical(KeycapProfileVocab, "keycaps", k.Keycaps, false); err != nil {
+		return err
+	}
+	if k.Solder != SolderHotSwap && k.Solder != SolderSoldered {
+		return fmt.Errorf("%w: solder must be %q or %q", ErrInvalidSpec, SolderHotSwap, SolderSoldered)
+	}
+	return nil
+}
+
+func (k *KeycapSetSpec) normalize() error {
+	var err error
+	k.Name = strings.TrimSpace(k.Name)
+	if k.Name == "" || len(k.Name) > 80 {
+		return fmt.Errorf("%w: keycap set name is required (max 80 characters)", ErrInvalidSpec)
+	}
+	if k.Profile, err = canonical(KeycapProfileVocab, "profile", k.Profile, true); err != nil {
+		return err
+	}
+	if k.Material, err = canonical(KeycapMaterialVocab, "material", k.Material, true); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (p *SwitchPackSpec) normalize() error {
+	var err error
+	if p.Switch, err = canonical(SwitchVocab, "switch", p.Switch, true); err != nil {
+		return err
+	}
+	if p.Type, err = canonical(SwitchTypeVocab, "type", p.Type, true); err != nil {
+		return err
+	}
+	if p.Lube, err = canonical(LubeVocab, "lube", p.Lube, false); err != nil {
+		return err
+	}
+	if p.Quantity <= 0 || p.Quantity > 1000 {
+		return fmt.Errorf("%w: switch quantity must be between 1 and 1000", ErrInvalidSpec)
+	}
+	return nil
+}
+
+// canonical is the shared helper for one vocabulary-backed field.
+func canonical(v *Vocabulary, field, value string, required bool) (string, error) {
+	if strings.TrimSpace(value) == "" {
+		if required {
+			return "", fmt.Errorf("%w: %s is required", ErrInvalidSpec, field)
+		}
+		return "", nil
+	}
+	c, ok := v.Canonicalize(value)
+	if !ok {
+		return "", fmt.Errorf("%w: unrecognized %s %q", ErrInvalidSpec, field, value)
+	}
+	return c, nil
+}
+
+// =================================================================
+// Display
+// Discord embeds and web product pages both render specs from this one
+// ordered list, so a spec always reads the same wherever buyers see it.
+// =================================================================
+
+// SpecField is one "label: value" line for display.
+type SpecField struct {
+	Label string `json:"label" firestore:"label"`
+	Value string `json:"value" firestore:"value"`
+}
+
+// DisplayFields returns the spec as ordered label/value pairs, skipping empty values.
+func (s *DropSpec) DisplayFields() []SpecField {
+	if s == nil {
+		return nil
+	}
+	var fields []SpecField
+	add := func(label, value string) {
+		if value != "" {
+			fields = append(fields, SpecField{Label: label, Value: value})
+		}
+	}
+
+	switch {
+	case s.Keyboard != nil:
+		k := s.Keyboard
+		add("Layout", k.Layout)
+		add("Case", k.Case)
+		add("Plate", k.Plate)
+		add("Switches", k.Switches)
+		add("Stabilizers", k.Stabilizers)
+		add("Lube", k.Lube)
+		add("Keycaps", k.Keycaps)
+		if k.Solder == SolderHotSwap {
+			add("PCB", "Hot-swap")
+		} else if k.Solder == SolderSoldered {
+			add("PCB", "Soldered")
+		}
+	case s.KeycapSet != nil:
+		k := s.KeycapSet
+		add("Set", k.Name)
+		add("Profile", k.Profile)
+		add("Material", k.Material)
+		add("Kits", strings.Join(k.Kits, ", "))
+	case s.SwitchPack != nil:
+		p := s.SwitchPack
+		add("Switch", p.Switch)
+		add("Type", p.Type)
+		add("Quantity", fmt.Sprintf("%d", p.Quantity))
+		add("Lube", p.Lube)
+		if p.Filmed {
+			add("Filmed", "Yes")
+		}
+	}
+	return fields
+}
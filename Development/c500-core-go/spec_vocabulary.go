@@ -0,0 +1,193 @@
+package domain
+
+import (
+	"strings"
+	"unicode"
+)
+
+// =================================================================
+// Canonical Spec Vocabulary
+// Every builder writes specs differently ("Gat yellows", "Gateron Yellow",
+// "gateron yellow switches"). Each vocabulary below lists the ONE canonical
+// spelling we store, plus the aliases we accept and fold into it.
+// Adding a new switch or layout is just a new line here.
+// =================================================================
+
+// Term is one canonical vocabulary entry and the aliases that map to it.
+type Term struct {
+	Canonical string
+	Aliases   []string
+}
+
+// Vocabulary is a lookup table from normalized alias -> canonical term.
+type Vocabulary struct {
+	name   string
+	terms  []Term
+	lookup map[string]string
+}
+
+// NewVocabulary builds the lookup table once at startup.
+func NewVocabulary(name string, terms []Term) *Vocabulary {
+	v := &Vocabulary{name: name, terms: terms, lookup: make(map[string]string)}
+	for _, t := range terms {
+		v.lookup[vocabKey(t.Canonical)] = t.Canonical
+		for _, alias := range t.Aliases {
+			v.lookup[vocabKey(alias)] = t.Canonical
+		}
+	}
+	return v
+}
+
+// Canonicalize returns the canonical spelling for any accepted alias.
+// The second return value is false if the input isn't in the vocabulary.
+func (v *Vocabulary) Canonicalize(input string) (string, bool) {
+	key := vocabKey(input)
+	if key == "" {
+		return "", false
+	}
+	if canonical, ok := v.lookup[key]; ok {
+		return canonical, true
+	}
+	// Forgive plurals: "Gat yellows" -> "gatyellow".
+	if strings.HasSuffix(key, "s") {
+		if canonical, ok := v.lookup[strings.TrimSuffix(key, "s")]; ok {
+			return canonical, true
+		}
+	}
+	// Forgive a trailing generic noun: "gateron yellow switches", "gmk keycaps".
+	for _, noun := range []string{"switches", "switch", "keycaps", "keycap", "stabs", "stabilizers", "case", "plate", "layout"} {
+		if trimmed := strings.TrimSuffix(key, noun); trimmed != key && trimmed != "" {
+			if canonical, ok := v.lookup[trimmed]; ok {
+				return canonical, true
+			}
+		}
+	}
+	return "", false
+}
+
+// Values returns every canonical term, in declaration order (used for bot autocomplete).
+func (v *Vocabulary) Values() []string {
+	out := make([]string, 0, len(v.terms))
+	for _, t := range v.terms {
+		out = append(out, t.Canonical)
+	}
+	return out
+}
+
+// vocabKey lowercases and strips everything that isn't a letter or digit,
+// so "Gateron Yellow", "gateron-yellow" and "GATERON_YELLOW" all match.
+func vocabKey(s string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(s) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// --- The vocabularies buyers filter on ---
+
+var LayoutVocab = NewVocabulary("layout", []Term{
+	{"40%", []string{"40", "forty", "fortypercent"}},
+	{"60%", []string{"60", "sixty", "sixtypercent", "hhkb"}},
+	{"65%", []string{"65", "sixtyfive", "sixtyfivepercent"}},
+	{"75%", []string{"75", "seventyfive", "seventyfivepercent"}},
+	{"TKL", []string{"80%", "80", "tenkeyless", "87key"}},
+	{"96%", []string{"96", "1800", "1800compact"}},
+	{"Full-size", []string{"100%", "100", "fullsize", "full", "104key"}},
+	{"Alice", []string{"arisu", "alicelayout"}},
+	{"Split", []string{"splitkeyboard", "ergo", "ergonomic"}},
+	{"Numpad", []string{"macropad", "numberpad"}},
+})
+
+var CaseMaterialVocab = NewVocabulary("case", []Term{
+	{"Aluminum", []string{"alu", "aluminium", "al", "cnc aluminum"}},
+	{"Polycarbonate", []string{"pc", "poly"}},
+	{"Acrylic", []string{"acryl", "stacked acrylic"}},
+	{"Plastic", []string{"abs", "injection molded", "stock plastic"}},
+	{"Wood", []string{"wooden", "walnut", "oak"}},
+	{"Brass", []string{}},
+	{"Stainless Steel", []string{"steel", "ss"}},
+})
+
+var PlateVocab = NewVocabulary("plate", []Term{
+	{"Aluminum", []string{"alu", "aluminium", "al"}},
+	{"Brass", []string{}},
+	{"FR4", []string{"fr-4", "fiberglass"}},
+	{"Polycarbonate", []string{"pc", "poly"}},
+	{"POM", []string{"delrin", "acetal"}},
+	{"Carbon Fiber", []string{"cf", "carbon"}},
+	{"Steel", []string{"stainless", "stainless steel"}},
+	{"Plateless", []string{"none", "no plate", "pcb mount"}},
+})
+
+var SwitchVocab = NewVocabulary("switches", []Term{
+	{"Gateron Yellow", []string{"gat yellow", "g yellow", "gateron yellow pro", "gat yellow pro"}},
+	{"Gateron Red", []string{"gat red", "g red"}},
+	{"Gateron Brown", []string{"gat brown", "g brown"}},
+	{"Gateron Black Ink", []string{"black ink", "gat black ink", "ink black"}},
+	{"Gateron Milky Yellow", []string{"milky yellow", "milky yellows", "gat milky yellow"}},
+	{"Cherry MX Red", []string{"mx red", "cherry red"}},
+	{"Cherry MX Brown", []string{"mx brown", "cherry brown"}},
+	{"Cherry MX Blue", []string{"mx blue", "cherry blue"}},
+	{"Cherry MX Black", []string{"mx black", "cherry black", "vintage black"}},
+	{"Holy Panda", []string{"holy pandas", "hp", "drop holy panda"}},
+	{"Boba U4T", []string{"u4t", "gazzew u4t", "boba u4 thocky"}},
+	{"Boba U4", []string{"u4", "gazzew u4", "boba u4 silent"}},
+	{"Oil King", []string{"gateron oil king", "oil kings"}},
+	{"Alpaca", []string{"alpacas", "primekb alpaca"}},
+	{"Tangerine", []string{"c3 tangerine", "tangerines"}},
+	{"Durock T1", []string{"t1", "durock t1 tactile"}},
+	{"Kailh Box Jade", []string{"box jade", "kailh jade"}},
+	{"Akko CS Jelly Pink", []string{"jelly pink", "akko jelly pink"}},
+	{"Other", []string{"custom", "mixed"}},
+})
+
+var StabilizerVocab = NewVocabulary("stabilizers", []Term{
+	{"Durock V2", []string{"durock", "durock v2 screw-in", "durock screw in"}},
+	{"Cherry Clip-in", []string{"cherry clip", "cherry stabs", "clip-in"}},
+	{"Cherry Screw-in", []string{"cherry screw", "cherry screw in"}},
+	{"GMK Screw-in", []string{"gmk stabs", "gmk screw in"}},
+	{"TX AP", []string{"tx", "tx stabs", "tx ap stabs"}},
+	{"Everglide Panda", []string{"everglide", "panda stabs"}},
+	{"Plate-mount", []string{"plate mount", "plate mounted", "costar"}},
+	{"Stock", []string{"factory", "default"}},
+})
+
+var LubeVocab = NewVocabulary("lube", []Term{
+	{"Unlubed", []string{"none", "no lube", "stock", "dry"}},
+	{"Krytox 205g0", []string{"205g0", "205", "krytox 205"}},
+	{"Tribosys 3203", []string{"3203", "tribosys", "trib 3203"}},
+	{"Tribosys 3204", []string{"3204", "trib 3204"}},
+	{"Krytox GPL 105", []string{"gpl105", "105 oil", "krytox 105"}},
+	{"Dielectric Grease", []string{"dielectric", "permatex"}},
+	{"Factory Lubed", []string{"factory lube", "prelubed", "pre-lubed"}},
+})
+
+var KeycapProfileVocab = NewVocabulary("keycap_profile", []Term{
+	{"Cherry", []string{"cherry profile", "gmk", "gmk profile"}},
+	{"OEM", []string{"oem profile"}},
+	{"SA", []string{"sa profile", "spherical all"}},
+	{"DSA", []string{"dsa profile"}},
+	{"XDA", []string{"xda profile"}},
+	{"MT3", []string{"mt3 profile", "drop mt3"}},
+	{"KAT", []string{"kat profile"}},
+	{"KAM", []string{"kam profile"}},
+	{"MDA", []string{"mda profile"}},
+})
+
+var KeycapMaterialVocab = NewVocabulary("keycap_material", []Term{
+	{"PBT", []string{"dye-sub pbt", "dyesub pbt", "doubleshot pbt"}},
+	{"ABS", []string{"doubleshot abs", "double shot abs"}},
+	{"POM", []string{}},
+	{"Resin", []string{"artisan resin"}},
+})
+
+var SwitchTypeVocab = NewVocabulary("switch_type", []Term{
+	{"Linear", []string{"linears", "smooth"}},
+	{"Tactile", []string{"tactiles", "bumpy"}},
+	{"Clicky", []string{"clickies", "click"}},
+	{"Silent Linear", []string{"silent linears"}},
+	{"Silent Tactile", []string{"silent tactiles"}},
+})
@@ -0,0 +1,85 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// BlobStore is where uploaded files physically live.
+// The service layer only talks to this interface, so dev machines use the
+// local filesystem and production uses a Cloud Storage bucket with no code changes.
+type BlobStore interface {
+	// Put writes (or overwrites) an object.
+	Put(ctx context.Context, key, contentType string, data []byte) error
+	// Delete removes an object. Deleting a missing key is not an error.
+	Delete(ctx context.Context, key string) error
+	// PublicURL is the URL browsers and Discord use to fetch the object.
+	PublicURL(key string) string
+}
+
+// =================================================================
+// LocalStore (development)
+// =================================================================
+
+// LocalStore writes files under a directory on disk. main.go serves that
+// directory with router.Static so PublicURL works on localhost.
+type LocalStore struct {
+	root    string
+	baseURL string // e.g. "http://localhost:8080/media"
+}
+
+// NewLocalStore creates the root directory if needed.
+func NewLocalStore(root, baseURL string) (*LocalStore, error) {
+	if err := os.MkdirAll(root, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create local storage dir: %w", err)
+	}
+	return &LocalStore{root: root, baseURL: strings.TrimRight(baseURL, "/")}, nil
+}
+
+// Put writes to a temp file first and renames it, so a crash never leaves a half-written image.
+func (s *LocalStore) Put(ctx context.Context, key, contentType string, data []byte) error {
+	path, err := s.pathFor(key)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("local storage mkdir error: %w", err)
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return fmt.Errorf("local storage write error: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("local storage rename error: %w", err)
+	}
+	return nil
+}
+
+// Delete removes the file if it exists.
+func (s *LocalStore) Delete(ctx context.Context, key string) error {
+	path, err := s.pathFor(key)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("local storage delete error: %w", err)
+	}
+	return nil
+}
+
+// PublicURL joins the configured base URL with the key.
+func (s *LocalStore) PublicURL(key string) string {
+	return s.baseURL + "/" + key
+}
+
+// pathFor maps a key to a path, refusing anything that would escape the root ("../../etc/passwd").
+func (s *LocalStore) pathFor(key string) (string, error) {
+	clean := filepath.Clean("/" + key)
+	if strings.Contains(key, "..") || clean == "/" {
+		return "", fmt.Errorf("invalid storage key %q", key)
+	}
+	return filepath.Join(s.root, clean), nil
+}
@@ -0,0 +1,67 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	gcs "cloud.google.com/go/storage"
+)
+
+// =================================================================
+// GCSStore (production)
+// =================================================================
+
+// GCSStore writes objects to a Google Cloud Storage bucket.
+// The bucket sits behind our CDN domain (e.g. https://media.c500.store),
+// so stored URLs never expire the way Discord CDN links do.
+type GCSStore struct {
+	bucket  *gcs.BucketHandle
+	baseURL string
+}
+
+// NewGCSStore constructor. Credentials come from the Cloud Run service account,
+// the same way the Firestore client authenticates.
+func NewGCSStore(ctx context.Context, bucketName, baseURL string) (*GCSStore, error) {
+	client, err := gcs.NewClient(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create storage client: %w", err)
+	}
+	return &GCSStore{
+		bucket:  client.Bucket(bucketName),
+		baseURL: strings.TrimRight(baseURL, "/"),
+	}, nil
+}
+
+// Put uploads the object with a long cache lifetime. Keys are never reused for
+// different content, so it's safe for the CDN to cache them "forever".
+func (s *GCSStore) Put(ctx context.Context, key, contentType string, data []byte) error {
+	w := s.bucket.Object(key).NewWriter(ctx)
+	w.ContentType = contentType
+	w.CacheControl = "public, max-age=31536000, immutable"
+
+	if _, err := w.Write(data); err != nil {
+		w.Close()
+		return fmt.Errorf("gcs write error: %w", err)
+	}
+	// The upload is only committed when Close returns nil.
+	if err := w.Close(); err != nil {
+		return fmt.Errorf("gcs commit error: %w", err)
+	}
+	return nil
+}
+
+// Delete removes the object; a missing object is treated as already deleted.
+func (s *GCSStore) Delete(ctx context.Context, key string) error {
+	err := s.bucket.Object(key).Delete(ctx)
+	if err != nil && !errors.Is(err, gcs.ErrObjectNotExist) {
+		return fmt.Errorf("gcs delete error: %w", err)
+	}
+	return nil
+}
+
+// PublicURL returns the CDN URL for the key.
+func (s *GCSStore) PublicURL(key string) string {
+	return s.baseURL + "/" + key
+}
@@ -0,0 +1,198 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"c500-core-go/internal/domain"
+	"c500-core-go/internal/events"
+)
+
+// eventSubMessageTTL is how long EventSub message IDs are remembered to drop
+// Twitch's retries. Twitch gives up retrying well within a day.
+const eventSubMessageTTL = 24 * time.Hour
+
+// StreamRepository defines the DB operations for reacting to Twitch streams.
+// Implemented in internal/database/firestore.go
+type StreamRepository interface {
+	// GetBuilderByTwitchID finds the builder who linked the Twitch user (ErrBuilderNotFound if none).
+	GetBuilderByTwitchID(ctx context.Context, twitchUserID string) (*domain.Builder, error)
+	// SetTwitchLive records when the builder's stream started, nil when it ended.
+	SetTwitchLive(ctx context.Context, builderID string, liveSince *time.Time) error
+	// ListSellerOrders returns the seller's orders in one production status, oldest first.
+	ListSellerOrders(ctx context.Context, sellerID string, status domain.ProductionStatus) ([]domain.Order, error)
+	// MarkEventSubMessage records a message ID, returning false if it was already seen.
+	MarkEventSubMessage(ctx context.Context, messageID string, expires time.Time) (bool, error)
+	ForgetEventSubMessage(ctx context.Context, messageID string) error
+}
+
+// StreamService defines the methods the EventSub webhook handler uses.
+type StreamService interface {
+	// FirstDelivery reports whether an EventSub message is new (Twitch retries).
+	FirstDelivery(ctx context.Context, messageID string) (bool, error)
+	// Redeliver forgets a message that failed to process, so Twitch's retry is handled.
+	Redeliver(ctx context.Context, messageID string)
+
+	StreamOnline(ctx context.Context, twitchUserID string, startedAt time.Time) error
+	StreamOffline(ctx context.Context, twitchUserID string) error
+}
+
+// streamService is the concrete implementation.
+type streamService struct {
+	repo       StreamRepository
+	production ProductionRepository
+	drops      DropRepository
+	publisher  events.Publisher
+}
+
+// NewStreamService constructor used in main.go.
+func NewStreamService(repo StreamRepository, production ProductionRepository, drops DropRepository, publisher events.Publisher) *streamService {
+	return &streamService{
+		repo:       repo,
+		production: production,
+		drops:      drops,
+		publisher:  publisher,
+	}
+}
+
+// ==========================================
+// Delivery
+// ==========================================
+
+// FirstDelivery marks the message ID as seen.
+func (s *streamService) FirstDelivery(ctx context.Context, messageID string) (bool, error) {
+	return s.repo.MarkEventSubMessage(ctx, messageID, time.Now().UTC().Add(eventSubMessageTTL))
+}
+
+// Redeliver forgets the message ID. Best effort: if it fails, the retry is dropped.
+func (s *streamService) Redeliver(ctx context.Context, messageID string) {
+	if err := s.repo.ForgetEventSubMessage(ctx, messageID); err != nil {
+		log.Printf("eventsub: failed to forget message %s: %v", messageID, err)
+	}
+}
+
+// ==========================================
+// Stream Events
+// ==========================================
+
+// StreamOnline moves the builder's commissions that haven't started (queued,
+// or waiting on parts) into production and tells every buyer whose commission
+// is in production where to watch.
+func (s *streamService) StreamOnline(ctx context.Context, twitchUserID string, startedAt time.Time) error {
+	builder, err := s.repo.GetBuilderByTwitchID(ctx, twitchUserID)
+	if err != nil {
+		return err
+	}
+	if startedAt.IsZero() {
+		startedAt = time.Now().UTC()
+	}
+	if err := s.repo.SetTwitchLive(ctx, builder.ID, &startedAt); err != nil {
+		return fmt.Errorf("failed to mark builder live: %w", err)
+	}
+
+	// 1. Not started yet -> in production.
+	now := time.Now().UTC()
+	started := map[string]bool{}
+	for _, from := range []domain.ProductionStatus{domain.ProductionQueued, domain.ProductionPartsOrdered} {
+		waiting, err := s.commissions(ctx, builder.ID, from)
+		if err != nil {
+			return err
+		}
+		for _, order := range waiting {
+			ev := domain.ProductionEvent{
+				Status: domain.ProductionInProduction,
+				At:     now,
+				Note:   "Building live on " + builder.Twitch.ChannelURL(),
+			}
+			err := s.production.AdvanceProduction(ctx, order.ID, from, ev, map[string]interface{}{
+				"production_started_at": now,
+			})
+			if errors.Is(err, ErrProductionConflict) {
+				continue // The seller moved it themselves meanwhile.
+			}
+			if err != nil {
+				return fmt.Errorf("failed to start production of order %s: %w", order.ID, err)
+			}
+			started[order.ID] = true
+		}
+	}
+
+	// 2. Notify everyone whose board is on the bench.
+	building, err := s.commissions(ctx, builder.ID, domain.ProductionInProduction)
+	if err != nil {
+		return err
+	}
+	return s.notify(ctx, events.BuildStreamOnline, builder, building, started)
+}
+
+// StreamOffline tells the buyers whose commission is in production that the
+// stream ended. Orders stay in production until they're fulfilled.
+func (s *streamService) StreamOffline(ctx context.Context, twitchUserID string) error {
+	builder, err := s.repo.GetBuilderByTwitchID(ctx, twitchUserID)
+	if err != nil {
+		return err
+	}
+	if err := s.repo.SetTwitchLive(ctx, builder.ID, nil); err != nil {
+		return fmt.Errorf("failed to mark builder offline: %w", err)
+	}
+
+	building, err := s.commissions(ctx, builder.ID, domain.ProductionInProduction)
+	if err != nil {
+		return err
+	}
+	return s.notify(ctx, events.BuildStreamOffline, builder, building, nil)
+}
+
+// commissions lists the seller's paid, unfulfilled commission orders in one
+// production status. Orders from before drop_type was stored on orders look
+// it up from the drop.
+func (s *streamService) commissions(ctx context.Context, sellerID string, status domain.ProductionStatus) ([]domain.Order, error) {
+	orders, err := s.repo.ListSellerOrders(ctx, sellerID, status)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list %s orders: %w", status, err)
+	}
+	var out []domain.Order
+	for _, order := range orders {
+		if order.EscrowStatus != domain.EscrowHeld {
+			continue // Already shipped or refunded.
+		}
+		if err := resolveDropType(ctx, s.drops, &order); err != nil {
+			log.Printf("stream: skipping order: %v", err)
+			continue
+		}
+		if order.DropType == domain.DropTypeCommission {
+			out = append(out, order)
+		}
+	}
+	return out, nil
+}
+
+// notify publishes one event listing every buyer to DM.
+func (s *streamService) notify(ctx context.Context, eventType string, builder *domain.Builder, orders []domain.Order, started map[string]bool) error {
+	if len(orders) == 0 {
+		return nil
+	}
+	targets := make([]map[string]interface{}, 0, len(orders))
+	for _, order := range orders {
+		targets = append(targets, map[string]interface{}{
+			"order_id":           order.ID,
+			"drop_id":            order.DropID,
+			"buyer_discord_id":   order.BuyerDiscordID,
+			"production_started": started[order.ID],
+		})
+	}
+	data := map[string]interface{}{
+		"seller_discord_id": builder.DiscordID,
+		"seller_name":       builder.DisplayName,
+		"twitch_login":      builder.Twitch.Login,
+		"stream_url":        builder.Twitch.ChannelURL(),
+		"orders":            targets,
+	}
+	if err := s.publisher.Publish(ctx, eventType, data); err != nil {
+		return fmt.Errorf("failed to publish %s: %w", eventType, err)
+	}
+	return nil
+}
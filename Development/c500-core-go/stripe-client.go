@@ -46,8 +46,7 @@ func (c *Client) CreateCheckoutSession(ctx context.Context, drop *domain.Drop, b
 					Currency: stripe.String(string(stripe.CurrencyUSD)),
 					ProductData: &stripe.CheckoutSessionLineItemPriceDataProductDataParams{
 						Name: stripe.String(drop.Title),
-						// We could add the main image URL here too so it shows on checkout page
-						// Images: []*string{stripe.String(drop.ImageURLs[0])},
+						// The main image URL is added below, if the drop has one.
 					},
 					// IMPORTANT: Stripe expects amounts in cents (e.g., 45000 for $450.00).
 					// Our domain model already stores it this way, so it's a direct mapping.
@@ -67,10 +66,15 @@ func (c *Client) CreateCheckoutSession(ctx context.Context, drop *domain.Drop, b
 			"buyer_discord_id":  buyerDiscordID,
 			"seller_discord_id": drop.SellerDiscordID,
 			// We track drop type so we know if fulfillment needs shipping or a VOD link later.
-			"drop_type":         drop.Type,
+			"drop_type":         string(drop.Type),
 		},
 	}
 
+	// Show the hosted embed-size photo on the Stripe checkout page.
+	if len(drop.ImageURLs) > 0 {
+		params.LineItems[0].PriceData.ProductData.Images = []*string{stripe.String(drop.ImageURLs[0])}
+	}
+
 	// 3. Perform the network call to Stripe's servers.
 	s, err := session.New(params)
 	if err != nil {
@@ -0,0 +1,50 @@
+package domain
+
+import "time"
+
+// TrackingStatus is where a shipment is, as reported by its carrier.
+type TrackingStatus string
+
+const (
+	TrackingPreTransit     TrackingStatus = "pre_transit"      // Label created, carrier doesn't have it yet.
+	TrackingInTransit      TrackingStatus = "in_transit"       // Moving through the carrier's network.
+	TrackingOutForDelivery TrackingStatus = "out_for_delivery" // On the truck today.
+	TrackingDelivered      TrackingStatus = "delivered"        // The carrier says it arrived.
+	TrackingException      TrackingStatus = "exception"        // Delayed, returned, address problem...
+	TrackingNotFound       TrackingStatus = "not_found"        // The carrier doesn't know the number (yet).
+)
+
+// TrackingEvent is one scan or status update from the carrier.
+type TrackingEvent struct {
+	At          time.Time      `json:"at" firestore:"at"`
+	Status      TrackingStatus `json:"status" firestore:"status"`
+	Description string         `json:"description" firestore:"description"`
+	Location    string         `json:"location,omitempty" firestore:"location,omitempty"`
+}
+
+// SameAs reports whether two events are the same carrier update. Timestamps
+// are left out: some carriers re-stamp old scans when they're queried again.
+func (e TrackingEvent) SameAs(o TrackingEvent) bool {
+	return e.Status == o.Status && e.Description == o.Description && e.Location == o.Location
+}
+
+// MergeTrackingEvents appends the incoming events that aren't already
+// recorded, keeping the order they arrived in. It returns the merged list
+// and just the new ones.
+func MergeTrackingEvents(existing, incoming []TrackingEvent) (merged, added []TrackingEvent) {
+	merged = existing
+	for _, ev := range incoming {
+		seen := false
+		for _, old := range merged {
+			if old.SameAs(ev) {
+				seen = true
+				break
+			}
+		}
+		if !seen {
+			merged = append(merged, ev)
+			added = append(added, ev)
+		}
+	}
+	return merged, added
+}
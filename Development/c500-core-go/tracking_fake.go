@@ -0,0 +1,66 @@
+package tracking
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"c500-core-go/internal/domain"
+)
+
+// FakeClient simulates carriers for local runs: every tracking number it is
+// asked about walks through label -> pickup -> transit -> out for delivery ->
+// delivered, one step per Step after the first time it was queried.
+//
+// Its descriptions don't change between runs, so the poller (which
+// de-duplicates by status, description and location) doesn't record a
+// shipment twice when the fake restarts and replays it.
+type FakeClient struct {
+	// Step is the simulated time between carrier scans.
+	Step time.Duration
+
+	mu        sync.Mutex
+	firstSeen map[string]time.Time
+}
+
+// NewFakeClient constructor used in main.go (TRACKING_PROVIDER=fake).
+func NewFakeClient(step time.Duration) *FakeClient {
+	return &FakeClient{
+		Step:      step,
+		firstSeen: make(map[string]time.Time),
+	}
+}
+
+// fakeScans is the simulated journey, one entry per Step.
+var fakeScans = []domain.TrackingEvent{
+	{Status: domain.TrackingPreTransit, Description: "Shipping label created"},
+	{Status: domain.TrackingInTransit, Description: "Picked up by carrier", Location: "Origin facility"},
+	{Status: domain.TrackingInTransit, Description: "Arrived at sort facility", Location: "Regional hub"},
+	{Status: domain.TrackingOutForDelivery, Description: "Out for delivery", Location: "Local delivery office"},
+	{Status: domain.TrackingDelivered, Description: "Delivered", Location: "Front door"},
+}
+
+// Track returns the scans so far, oldest first.
+func (c *FakeClient) Track(ctx context.Context, carrier, number string) ([]domain.TrackingEvent, error) {
+	now := time.Now().UTC()
+	key := carrier + ":" + number
+
+	c.mu.Lock()
+	start, ok := c.firstSeen[key]
+	if !ok {
+		start = now
+		c.firstSeen[key] = start
+	}
+	c.mu.Unlock()
+
+	var events []domain.TrackingEvent
+	for i, scan := range fakeScans {
+		at := start.Add(time.Duration(i) * c.Step)
+		if at.After(now) {
+			break
+		}
+		scan.At = at
+		events = append(events, scan)
+	}
+	return events, nil
+}
@@ -0,0 +1,123 @@
+package scheduler
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"c500-core-go/internal/domain"
+)
+
+// maxShipmentsPerTick bounds one polling pass; the rest wait for the next tick.
+const maxShipmentsPerTick = 200
+
+// TrackingClient asks a carrier (or an aggregator covering several) where a
+// shipment is. The local fake lives in internal/integrations/tracking/fake.go.
+type TrackingClient interface {
+	// Track returns every event the carrier has for the number, oldest first.
+	Track(ctx context.Context, carrier, number string) ([]domain.TrackingEvent, error)
+}
+
+// ShipmentRepository defines the DB operations the tracking poller needs.
+// Implemented in internal/database/firestore.go
+type ShipmentRepository interface {
+	// ListShippedOrders returns orders in production status "shipped", least
+	// recently checked first.
+	ListShippedOrders(ctx context.Context, limit int) ([]domain.Order, error)
+	// RecordTracking merges events into the order's tracking history in one
+	// transaction and returns the ones that were new. If delivered is set and
+	// the order is still "shipped", it also moves production to delivered.
+	RecordTracking(ctx context.Context, orderID string, events []domain.TrackingEvent, checkedAt time.Time, delivered *domain.ProductionEvent) ([]domain.TrackingEvent, error)
+}
+
+// TrackingPoller follows shipped orders with their carrier, records transit
+// events on the order and marks it delivered when the carrier does.
+//
+// Like the DropScheduler, every Core instance runs one: recording is a
+// transaction that skips events already stored, so two instances polling the
+// same shipment don't duplicate anything.
+type TrackingPoller struct {
+	repo     ShipmentRepository
+	client   TrackingClient
+	interval time.Duration
+}
+
+// NewTrackingPoller constructor used in main.go.
+func NewTrackingPoller(repo ShipmentRepository, client TrackingClient, interval time.Duration) *TrackingPoller {
+	return &TrackingPoller{
+		repo:     repo,
+		client:   client,
+		interval: interval,
+	}
+}
+
+// Run ticks until ctx is cancelled. Start it in a goroutine from main.go.
+func (p *TrackingPoller) Run(ctx context.Context) {
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+
+	p.tick(ctx)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			p.tick(ctx)
+		}
+	}
+}
+
+// tick checks every shipment that wasn't checked within the last interval.
+func (p *TrackingPoller) tick(ctx context.Context) {
+	orders, err := p.repo.ListShippedOrders(ctx, maxShipmentsPerTick)
+	if err != nil {
+		log.Printf("tracking poller: failed to list shipped orders: %v", err)
+		return
+	}
+
+	// Half an interval of slack so ticks that drift slightly still poll.
+	recheckAfter := time.Now().UTC().Add(-p.interval / 2)
+	for _, order := range orders {
+		if ctx.Err() != nil {
+			return
+		}
+		if order.TrackingCheckedAt != nil && order.TrackingCheckedAt.After(recheckAfter) {
+			continue
+		}
+		p.poll(ctx, order)
+	}
+}
+
+func (p *TrackingPoller) poll(ctx context.Context, order domain.Order) {
+	// Orders shipped before carrier validation may have free-text carriers.
+	carrier, ok := domain.LookupCarrier(order.Carrier)
+	if !ok || order.TrackingNumber == "" {
+		return
+	}
+
+	events, err := p.client.Track(ctx, carrier.Code, order.TrackingNumber)
+	if err != nil {
+		log.Printf("tracking poller: failed to track order %s (%s %s): %v", order.ID, carrier.Name, order.TrackingNumber, err)
+		return
+	}
+
+	var delivered *domain.ProductionEvent
+	for _, ev := range events {
+		if ev.Status == domain.TrackingDelivered {
+			delivered = &domain.ProductionEvent{
+				Status: domain.ProductionDelivered,
+				At:     ev.At,
+				Note:   "Delivered by " + carrier.Name,
+			}
+		}
+	}
+
+	added, err := p.repo.RecordTracking(ctx, order.ID, events, time.Now().UTC(), delivered)
+	if err != nil {
+		log.Printf("tracking poller: failed to record tracking for order %s: %v", order.ID, err)
+		return
+	}
+	if len(added) > 0 {
+		log.Printf("tracking poller: order %s: %d new %s events, now %s", order.ID, len(added), carrier.Name, added[len(added)-1].Status)
+	}
+}
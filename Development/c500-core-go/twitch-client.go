@@ -0,0 +1,209 @@
+package twitch
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"c500-core-go/internal/domain"
+)
+
+// Default endpoints. Config can point both somewhere else, e.g. at the local
+// stand-in server in cmd/twitchstandin, so the whole link flow runs offline.
+const (
+	DefaultAuthBaseURL = "https://id.twitch.tv/oauth2"
+	DefaultAPIBaseURL  = "https://api.twitch.tv/helix"
+)
+
+// ErrInvalidCode means Twitch rejected the authorization code (expired,
+// already used, or issued for a different redirect URL).
+var ErrInvalidCode = errors.New("twitch rejected the authorization code")
+
+// Config holds the Twitch application credentials from the developer console.
+type Config struct {
+	ClientID     string
+	ClientSecret string
+	// RedirectURL must match one registered for the application exactly.
+	RedirectURL string
+
+	AuthBaseURL string // Defaults to DefaultAuthBaseURL
+	APIBaseURL  string // Defaults to DefaultAPIBaseURL
+
+	// EventSubCallbackURL is our public /webhooks/twitch URL and EventSubSecret
+	// the secret Twitch signs notifications with (10-100 ASCII characters).
+	EventSubCallbackURL string
+	EventSubSecret      string
+}
+
+// Client is our wrapper around Twitch's OAuth and Helix APIs.
+type Client struct {
+	cfg  Config
+	http *http.Client
+
+	// App access token (client credentials) for EventSub subscriptions.
+	mu          sync.Mutex
+	appToken    string
+	appTokenExp time.Time
+}
+
+// NewClient creates a new instance.
+func NewClient(cfg Config) *Client {
+	if cfg.AuthBaseURL == "" {
+		cfg.AuthBaseURL = DefaultAuthBaseURL
+	}
+	if cfg.APIBaseURL == "" {
+		cfg.APIBaseURL = DefaultAPIBaseURL
+	}
+	cfg.AuthBaseURL = strings.TrimRight(cfg.AuthBaseURL, "/")
+	cfg.APIBaseURL = strings.TrimRight(cfg.APIBaseURL, "/")
+	return &Client{
+		cfg:  cfg,
+		http: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// AuthorizeURL fulfills the TwitchIntegration interface in the Service layer.
+// No scopes are requested: logging in is enough to prove who owns the channel.
+// force_verify makes Twitch ask every time, so a re-link can pick another account.
+func (c *Client) AuthorizeURL(state string) string {
+	q := url.Values{
+		"client_id":     {c.cfg.ClientID},
+		"redirect_uri":  {c.cfg.RedirectURL},
+		"response_type": {"code"},
+		"scope":         {""},
+		"state":         {state},
+		"force_verify":  {"true"},
+	}
+	return c.cfg.AuthBaseURL + "/authorize?" + q.Encode()
+}
+
+// ExchangeCode trades an authorization code for a user token, then asks Helix
+// whose token it is. The token itself is thrown away: we only need the identity.
+func (c *Client) ExchangeCode(ctx context.Context, code string) (*domain.TwitchAccount, error) {
+	// 1. Code -> user access token.
+	form := url.Values{
+		"client_id":     {c.cfg.ClientID},
+		"client_secret": {c.cfg.ClientSecret},
+		"code":          {code},
+		"grant_type":    {"authorization_code"},
+		"redirect_uri":  {c.cfg.RedirectURL},
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.cfg.AuthBaseURL+"/token", strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	var token struct {
+		AccessToken string `json:"access_token"`
+	}
+	if err := c.doJSON(req, &token); err != nil {
+		var statusErr *statusError
+		if errors.As(err, &statusErr) && statusErr.code == http.StatusBadRequest {
+			return nil, ErrInvalidCode
+		}
+		return nil, fmt.Errorf("twitch token exchange failed: %w", err)
+	}
+
+	// 2. Token -> the user who authorized it (GET /users with no parameters).
+	req, err = http.NewRequestWithContext(ctx, http.MethodGet, c.cfg.APIBaseURL+"/users", nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+token.AccessToken)
+	req.Header.Set("Client-Id", c.cfg.ClientID)
+
+	var users struct {
+		Data []struct {
+			ID          string `json:"id"`
+			Login       string `json:"login"`
+			DisplayName string `json:"display_name"`
+		} `json:"data"`
+	}
+	if err := c.doJSON(req, &users); err != nil {
+		return nil, fmt.Errorf("twitch get user failed: %w", err)
+	}
+	if len(users.Data) != 1 || users.Data[0].ID == "" {
+		return nil, errors.New("twitch returned no user for the token")
+	}
+
+	u := users.Data[0]
+	return &domain.TwitchAccount{
+		UserID:      u.ID,
+		Login:       strings.ToLower(u.Login),
+		DisplayName: u.DisplayName,
+	}, nil
+}
+
+// GetVideo fulfills the TwitchVideos interface in the Service layer. It
+// returns nil, nil if there's no such video (deleted, or never existed).
+func (c *Client) GetVideo(ctx context.Context, videoID string) (*domain.TwitchVideo, error) {
+	var videos struct {
+		Data []struct {
+			ID        string    `json:"id"`
+			UserID    string    `json:"user_id"`
+			UserLogin string    `json:"user_login"`
+			Type      string    `json:"type"`
+			CreatedAt time.Time `json:"created_at"`
+			Duration  string    `json:"duration"` // e.g. "3h8m33s"
+		} `json:"data"`
+	}
+	err := c.helix(ctx, http.MethodGet, "/videos?id="+url.QueryEscape(videoID), nil, &videos)
+	var statusErr *statusError
+	if errors.As(err, &statusErr) && statusErr.code == http.StatusNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("twitch get video failed: %w", err)
+	}
+	if len(videos.Data) == 0 {
+		return nil, nil
+	}
+
+	v := videos.Data[0]
+	duration, _ := time.ParseDuration(v.Duration)
+	return &domain.TwitchVideo{
+		ID:        v.ID,
+		UserID:    v.UserID,
+		UserLogin: strings.ToLower(v.UserLogin),
+		Type:      v.Type,
+		CreatedAt: v.CreatedAt,
+		Duration:  duration,
+	}, nil
+}
+
+// statusError is a non-2xx response from Twitch.
+type statusError struct {
+	code int
+	body string
+}
+
+func (e *statusError) Error() string {
+	return fmt.Sprintf("twitch returned %d: %s", e.code, e.body)
+}
+
+func (c *Client) doJSON(req *http.Request, out interface{}) error {
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		var body struct {
+			Message string `json:"message"`
+		}
+		json.NewDecoder(resp.Body).Decode(&body)
+		return &statusError{code: resp.StatusCode, body: body.Message}
+	}
+	if out == nil || resp.StatusCode == http.StatusNoContent {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
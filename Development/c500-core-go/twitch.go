@@ -0,0 +1,45 @@
+package domain
+
+import "time"
+
+// TwitchAccount is the Twitch channel a builder has proven they own by logging
+// in to Twitch (see service/twitch_service.go). It is what makes their streams
+// count as "proof of work" for the drops they sell.
+//
+// Stored nested in the Builder document. The "twitch_links" collection maps
+// each Twitch user ID back to its builder, so one channel can't be claimed
+// by two builders.
+type TwitchAccount struct {
+	// UserID is Twitch's permanent numeric user ID. Logins can be renamed; this can't.
+	UserID string `json:"user_id" firestore:"user_id"`
+
+	// Login is the lowercase channel name (twitch.tv/<login>) at link time.
+	Login       string `json:"login" firestore:"login"`
+	DisplayName string `json:"display_name" firestore:"display_name"`
+
+	LinkedAt time.Time `json:"linked_at" firestore:"linked_at"`
+
+	// LiveSince is when their current stream started, nil while offline.
+	// Kept up to date by Twitch EventSub (see service/stream_service.go).
+	LiveSince *time.Time `json:"live_since,omitempty" firestore:"live_since,omitempty"`
+}
+
+// TwitchVideo is a video on a Twitch channel, as Helix reports it.
+type TwitchVideo struct {
+	ID        string
+	UserID    string // The channel it belongs to
+	UserLogin string
+	// Type is "archive" (a past broadcast), "highlight" or "upload".
+	Type string
+	// CreatedAt is when the stream started, for archives.
+	CreatedAt time.Time
+	Duration  time.Duration
+}
+
+// TwitchVideoArchive is the type of a stream's automatic VOD.
+const TwitchVideoArchive = "archive"
+
+// ChannelURL is the public link to the builder's channel.
+func (a *TwitchAccount) ChannelURL() string {
+	return "https://www.twitch.tv/" + a.Login
+}
@@ -0,0 +1,129 @@
+package http
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"c500-core-go/internal/service"
+)
+
+// TwitchHandler runs the Twitch account link flow. The bot starts it and
+// unlinks; Twitch sends the builder's browser back to the public callback.
+type TwitchHandler struct {
+	twitchService service.TwitchService
+}
+
+// NewTwitchHandler is the constructor.
+func NewTwitchHandler(ts service.TwitchService) *TwitchHandler {
+	return &TwitchHandler{
+		twitchService: ts,
+	}
+}
+
+// RegisterRoutes connects the internal API URLs to the handler functions.
+// This is called in main.go, on the internal group: starting a link and
+// unlinking act for whichever builder is in the path.
+func (h *TwitchHandler) RegisterRoutes(router *gin.RouterGroup) {
+	router.GET("/builders/:discordID/twitch", h.GetLink)
+	router.POST("/builders/:discordID/twitch/link", h.StartLink)
+	router.DELETE("/builders/:discordID/twitch", h.Unlink)
+}
+
+// RegisterCallbackRoutes registers the OAuth redirect URL. Like the Stripe
+// webhook it is NOT under /api/v1: it's opened by the builder's browser.
+// Called in main.go.
+func (h *TwitchHandler) RegisterCallbackRoutes(router *gin.RouterGroup) {
+	router.GET("/twitch/callback", h.Callback)
+}
+
+// ==========================================
+// Request/Response Structs (Data Contracts)
+// ==========================================
+
+// startLinkResponse is returned to the bot to DM to the builder.
+type startLinkResponse struct {
+	URL       string `json:"url"`
+	ExpiresAt string `json:"expires_at"`
+}
+
+// ==========================================
+// Handler Functions
+// ==========================================
+
+// GetLink handles GET /api/v1/builders/:discordID/twitch
+func (h *TwitchHandler) GetLink(c *gin.Context) {
+	account, err := h.twitchService.GetLink(c.Request.Context(), c.Param("discordID"))
+	if err != nil {
+		h.writeError(c, err, "Failed to load Twitch account")
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"twitch":      account,
+		"channel_url": account.ChannelURL(),
+	})
+}
+
+// StartLink handles POST /api/v1/builders/:discordID/twitch/link
+// Also used to re-link: the old account is replaced once the new login succeeds.
+func (h *TwitchHandler) StartLink(c *gin.Context) {
+	url, expires, err := h.twitchService.StartLink(c.Request.Context(), c.Param("discordID"))
+	if err != nil {
+		h.writeError(c, err, "Failed to start Twitch link")
+		return
+	}
+	c.JSON(http.StatusOK, startLinkResponse{
+		URL:       url,
+		ExpiresAt: expires.Format(time.RFC3339),
+	})
+}
+
+// Unlink handles DELETE /api/v1/builders/:discordID/twitch
+func (h *TwitchHandler) Unlink(c *gin.Context) {
+	if err := h.twitchService.Unlink(c.Request.Context(), c.Param("discordID")); err != nil {
+		h.writeError(c, err, "Failed to unlink Twitch account")
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// Callback handles GET /twitch/callback?code=...&state=...
+// The builder sees this page in their browser, so it answers in plain text.
+func (h *TwitchHandler) Callback(c *gin.Context) {
+	if c.Query("error") != "" {
+		// They pressed "Cancel" on Twitch's consent screen.
+		c.String(http.StatusOK, "Twitch linking was cancelled. Nothing was changed.")
+		return
+	}
+
+	builder, err := h.twitchService.CompleteLink(c.Request.Context(), c.Query("code"), c.Query("state"))
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrInvalidTwitchState):
+			c.String(http.StatusBadRequest, "This link is invalid or has expired. Run !twitch link in Discord to get a new one.")
+		case errors.Is(err, service.ErrTwitchAccountTaken):
+			c.String(http.StatusConflict, "That Twitch account is already linked to another C500 builder. Unlink it there first.")
+		default:
+			log.Printf("twitch callback: %v", err)
+			c.String(http.StatusBadGateway, "We couldn't confirm your Twitch login. Please try again.")
+		}
+		return
+	}
+	c.String(http.StatusOK, "Linked "+builder.Twitch.ChannelURL()+" to "+builder.DisplayName+
+		"'s C500 profile. You can close this tab.")
+}
+
+// writeError maps service errors to HTTP status codes.
+func (h *TwitchHandler) writeError(c *gin.Context, err error, fallback string) {
+	switch {
+	case errors.Is(err, service.ErrBuilderNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Builder not found"})
+	case errors.Is(err, service.ErrTwitchNotLinked):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": fallback})
+	}
+}
@@ -0,0 +1,185 @@
+package service
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"c500-core-go/internal/domain"
+)
+
+var (
+	ErrTwitchNotLinked    = errors.New("no twitch account is linked")
+	ErrTwitchAccountTaken = errors.New("that twitch account is already linked to another builder")
+	ErrInvalidTwitchState = errors.New("this twitch link is invalid or has expired, ask the bot for a new one")
+	ErrTwitchError        = errors.New("twitch integration error")
+)
+
+// twitchLinkTTL is how long a link from !twitch link can be used.
+const twitchLinkTTL = 15 * time.Minute
+
+// TwitchRepository keeps Builder.Twitch and the Twitch ID registry in step.
+// Implemented in internal/database/firestore.go
+type TwitchRepository interface {
+	// LinkTwitch sets the builder's account, replacing (and releasing) any
+	// previous one. ErrTwitchAccountTaken if another builder holds it.
+	LinkTwitch(ctx context.Context, builderID string, account *domain.TwitchAccount) error
+	// UnlinkTwitch clears the builder's account and returns it. ErrTwitchNotLinked if none.
+	UnlinkTwitch(ctx context.Context, builderID string) (*domain.TwitchAccount, error)
+}
+
+// TwitchIntegration defines the interface for Twitch's OAuth login.
+// This implementation lives in internal/integrations/twitch/client.go
+type TwitchIntegration interface {
+	// AuthorizeURL is the Twitch login page; Twitch sends the browser back to
+	// our callback with a code and the state we passed.
+	AuthorizeURL(state string) string
+	// ExchangeCode verifies the code with Twitch and returns whose account it is.
+	ExchangeCode(ctx context.Context, code string) (*domain.TwitchAccount, error)
+	// SubscribeStreamEvents has Twitch send the channel's stream.online and
+	// stream.offline to our EventSub webhook (see stream_service.go).
+	SubscribeStreamEvents(ctx context.Context, broadcasterID string) error
+	UnsubscribeStreamEvents(ctx context.Context, broadcasterID string) error
+}
+
+// TwitchService defines the methods handlers and other services use.
+type TwitchService interface {
+	// StartLink returns the Twitch login URL the bot DMs to the builder.
+	StartLink(ctx context.Context, builderID string) (string, time.Time, error)
+	// CompleteLink handles Twitch's redirect back and returns the linked builder.
+	CompleteLink(ctx context.Context, code, state string) (*domain.Builder, error)
+	Unlink(ctx context.Context, builderID string) error
+	GetLink(ctx context.Context, builderID string) (*domain.TwitchAccount, error)
+}
+
+// twitchService is the concrete implementation.
+type twitchService struct {
+	builders    BuilderRepository
+	repo        TwitchRepository
+	twitch      TwitchIntegration
+	stateSecret []byte
+}
+
+// NewTwitchService constructor used in main.go. stateSecret signs the OAuth
+// state, binding each login to the builder who asked for it.
+func NewTwitchService(br BuilderRepository, tr TwitchRepository, twitch TwitchIntegration, stateSecret string) *twitchService {
+	return &twitchService{
+		builders:    br,
+		repo:        tr,
+		twitch:      twitch,
+		stateSecret: []byte(stateSecret),
+	}
+}
+
+// ==========================================
+// Linking
+// ==========================================
+
+// StartLink signs a state of "<builderID>.<expiry>.<nonce>.<signature>". The
+// callback trusts the builder ID in it, so the URL must only ever be sent to
+// that builder (the bot DMs it).
+func (s *twitchService) StartLink(ctx context.Context, builderID string) (string, time.Time, error) {
+	if _, err := s.builders.GetByID(ctx, builderID); err != nil {
+		return "", time.Time{}, err
+	}
+	expires := time.Now().UTC().Add(twitchLinkTTL).Truncate(time.Second)
+	payload := builderID + "." + strconv.FormatInt(expires.Unix(), 10) + "." + rand.Text()[:16]
+	return s.twitch.AuthorizeURL(payload + "." + s.sign(payload)), expires, nil
+}
+
+// CompleteLink checks the state, has Twitch confirm the code, and links the
+// account it belongs to. Linking the account that's already linked is a no-op.
+func (s *twitchService) CompleteLink(ctx context.Context, code, state string) (*domain.Builder, error) {
+	builderID, err := s.verifyState(state)
+	if err != nil {
+		return nil, err
+	}
+	if code == "" {
+		return nil, ErrInvalidTwitchState
+	}
+
+	account, err := s.twitch.ExchangeCode(ctx, code)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrTwitchError, err)
+	}
+	account.LinkedAt = time.Now().UTC()
+
+	previous, err := s.builders.GetByID(ctx, builderID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repo.LinkTwitch(ctx, builderID, account); err != nil {
+		return nil, err
+	}
+
+	// The link stands even if Twitch is down; going live just won't start
+	// production until the builder re-links.
+	if previous.Twitch != nil && previous.Twitch.UserID != account.UserID {
+		if err := s.twitch.UnsubscribeStreamEvents(ctx, previous.Twitch.UserID); err != nil {
+			log.Printf("twitch: failed to unsubscribe old channel %s of %s: %v", previous.Twitch.UserID, builderID, err)
+		}
+	}
+	if err := s.twitch.SubscribeStreamEvents(ctx, account.UserID); err != nil {
+		log.Printf("twitch: failed to subscribe to stream events of %s for %s: %v", account.UserID, builderID, err)
+	}
+	return s.builders.GetByID(ctx, builderID)
+}
+
+// Unlink removes the builder's Twitch account; they can link one again at any time.
+func (s *twitchService) Unlink(ctx context.Context, builderID string) error {
+	account, err := s.repo.UnlinkTwitch(ctx, builderID)
+	if err != nil {
+		return err
+	}
+	if err := s.twitch.UnsubscribeStreamEvents(ctx, account.UserID); err != nil {
+		log.Printf("twitch: failed to unsubscribe unlinked channel %s of %s: %v", account.UserID, builderID, err)
+	}
+	return nil
+}
+
+// GetLink returns the builder's linked account.
+func (s *twitchService) GetLink(ctx context.Context, builderID string) (*domain.TwitchAccount, error) {
+	builder, err := s.builders.GetByID(ctx, builderID)
+	if err != nil {
+		return nil, err
+	}
+	if builder.Twitch == nil {
+		return nil, ErrTwitchNotLinked
+	}
+	return builder.Twitch, nil
+}
+
+func (s *twitchService) verifyState(state string) (string, error) {
+	cut := strings.LastIndexByte(state, '.')
+	if cut < 0 {
+		return "", ErrInvalidTwitchState
+	}
+	payload, sig := state[:cut], state[cut+1:]
+	if !hmac.Equal([]byte(sig), []byte(s.sign(payload))) {
+		return "", ErrInvalidTwitchState
+	}
+
+	parts := strings.Split(payload, ".")
+	if len(parts) != 3 {
+		return "", ErrInvalidTwitchState
+	}
+	expUnix, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil || time.Now().Unix() > expUnix {
+		return "", ErrInvalidTwitchState
+	}
+	return parts[0], nil
+}
+
+func (s *twitchService) sign(payload string) string {
+	mac := hmac.New(sha256.New, s.stateSecret)
+	mac.Write([]byte("twitch-link|" + payload))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,489 @@
+package main
+
+// cmd/twitchstandin is a local stand-in for Twitch's OAuth and Helix APIs, so
+// the Twitch account link flow can be run end to end without a Twitch app.
+//
+// Usage:
+//   go run ./cmd/twitchstandin -addr :9090 -user-id 141981764 -login keyzbuilds
+//
+// Then start the Core with:
+//   TWITCH_AUTH_URL=http://localhost:9090/oauth2
+//   TWITCH_API_URL=http://localhost:9090/helix
+//   TWITCH_CLIENT_ID=standin TWITCH_CLIENT_SECRET=standin
+//   TWITCH_REDIRECT_URL=http://localhost:8080/twitch/callback
+//
+//   TWITCH_EVENTSUB_CALLBACK_URL=http://localhost:8080/webhooks/twitch
+//   TWITCH_EVENTSUB_SECRET=standin-eventsub-secret
+//
+// and open the URL from POST /api/v1/builders/:discordID/twitch/link. The
+// stand-in "logs in" as the configured user straight away and redirects back
+// to the Core. Add ?deny=1 to the authorize URL to simulate pressing Cancel.
+//
+// Linking subscribes the Core to stream.online/offline; the stand-in verifies
+// the callback with a challenge like Twitch does. Then fake a stream with:
+//   curl -X POST localhost:9090/standin/streams/141981764/online
+//   curl -X POST localhost:9090/standin/streams/141981764/offline
+// Each signed notification goes to every enabled subscription for the user.
+//
+// Streams are recorded as past broadcasts (GET /helix/videos), so their VOD
+// links (printed in the response) pass /fulfill live. To try a suspicious one:
+//   curl -X POST localhost:9090/standin/videos -d '{"user_id":"999","type":"upload"}'
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"c500-core-go/internal/integrations/twitch"
+)
+
+type standin struct {
+	clientID, clientSecret string
+	userID, login, name    string
+
+	mu        sync.Mutex
+	codes     map[string]string // code -> redirect_uri it was issued for
+	tokens    map[string]bool   // user access tokens
+	appTokens map[string]bool   // client credentials tokens
+	subs      map[string]*subscription
+
+	videos     map[string]*video
+	liveVideos map[string]*video // user ID -> archive of the stream in progress
+	nextVideo  int
+}
+
+// video is a Helix video. Duration is only known once the stream ends.
+type video struct {
+	ID        string    `json:"id"`
+	UserID    string    `json:"user_id"`
+	UserLogin string    `json:"user_login"`
+	Type      string    `json:"type"`
+	CreatedAt time.Time `json:"created_at"`
+	Duration  string    `json:"duration"`
+	URL       string    `json:"url"`
+}
+
+// subscription is an EventSub subscription as Helix returns it, plus the
+// transport secret (which Helix never returns).
+type subscription struct {
+	ID        string            `json:"id"`
+	Status    string            `json:"status"`
+	Type      string            `json:"type"`
+	Version   string            `json:"version"`
+	Condition map[string]string `json:"condition"`
+	CreatedAt time.Time         `json:"created_at"`
+	Transport struct {
+		Method   string `json:"method"`
+		Callback string `json:"callback"`
+		Secret   string `json:"secret,omitempty"`
+	} `json:"transport"`
+}
+
+func main() {
+	addr := flag.String("addr", ":9090", "Listen address")
+	clientID := flag.String("client-id", "standin", "Client ID the Core must send")
+	clientSecret := flag.String("client-secret", "standin", "Client secret the Core must send")
+	userID := flag.String("user-id", "141981764", "Twitch user ID to log in as")
+	login := flag.String("login", "keyzbuilds", "Twitch login to log in as")
+	flag.Parse()
+
+	s := &standin{
+		clientID:     *clientID,
+		clientSecret: *clientSecret,
+		userID:       *userID,
+		login:        *login,
+		name:         *login,
+		codes:        map[string]string{},
+		tokens:       map[string]bool{},
+		appTokens:    map[string]bool{},
+		subs:         map[string]*subscription{},
+		videos:       map[string]*video{},
+		liveVideos:   map[string]*video{},
+		nextVideo:    2000000000,
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /oauth2/authorize", s.authorize)
+	mux.HandleFunc("POST /oauth2/token", s.token)
+	mux.HandleFunc("GET /helix/users", s.users)
+	mux.HandleFunc("POST /helix/eventsub/subscriptions", s.createSubscription)
+	mux.HandleFunc("GET /helix/eventsub/subscriptions", s.listSubscriptions)
+	mux.HandleFunc("DELETE /helix/eventsub/subscriptions", s.deleteSubscription)
+	mux.HandleFunc("GET /helix/videos", s.getVideos)
+	mux.HandleFunc("POST /standin/streams/{userID}/{state}", s.triggerStream)
+	mux.HandleFunc("POST /standin/videos", s.createVideo)
+
+	log.Printf("Twitch stand-in on %s, logging in as %s (%s)", *addr, s.login, s.userID)
+	log.Fatal(http.ListenAndServe(*addr, mux))
+}
+
+// authorize skips the login page and redirects back with a one-time code.
+func (s *standin) authorize(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	redirect, err := url.Parse(q.Get("redirect_uri"))
+	if err != nil || redirect.Scheme == "" || q.Get("client_id") != s.clientID || q.Get("response_type") != "code" {
+		http.Error(w, "bad authorize request", http.StatusBadRequest)
+		return
+	}
+
+	back := redirect.Query()
+	back.Set("state", q.Get("state"))
+	if q.Get("deny") != "" {
+		back.Set("error", "access_denied")
+	} else {
+		code := rand.Text()
+		s.mu.Lock()
+		s.codes[code] = q.Get("redirect_uri")
+		s.mu.Unlock()
+		back.Set("code", code)
+	}
+	redirect.RawQuery = back.Encode()
+	http.Redirect(w, r, redirect.String(), http.StatusFound)
+}
+
+// token exchanges a code once, like Twitch does, or issues an app token.
+func (s *standin) token(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"message": "bad form"})
+		return
+	}
+	if r.Form.Get("client_id") != s.clientID || r.Form.Get("client_secret") != s.clientSecret {
+		writeJSON(w, http.StatusForbidden, map[string]string{"message": "invalid client"})
+		return
+	}
+	if r.Form.Get("grant_type") == "client_credentials" {
+		token := rand.Text()
+		s.mu.Lock()
+		s.appTokens[token] = true
+		s.mu.Unlock()
+		writeJSON(w, http.StatusOK, map[string]interface{}{
+			"access_token": token,
+			"expires_in":   5000000,
+			"token_type":   "bearer",
+		})
+		return
+	}
+
+	s.mu.Lock()
+	redirect, ok := s.codes[r.Form.Get("code")]
+	delete(s.codes, r.Form.Get("code"))
+	s.mu.Unlock()
+	if !ok || redirect != r.Form.Get("redirect_uri") || r.Form.Get("grant_type") != "authorization_code" {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"message": "Invalid authorization code"})
+		return
+	}
+
+	token := rand.Text()
+	s.mu.Lock()
+	s.tokens[token] = true
+	s.mu.Unlock()
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"access_token": token,
+		"expires_in":   14400,
+		"token_type":   "bearer",
+	})
+}
+
+// users returns the logged-in user for a user token.
+func (s *standin) users(w http.ResponseWriter, r *http.Request) {
+	token, _ := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	s.mu.Lock()
+	ok := s.tokens[token]
+	s.mu.Unlock()
+	if !ok || r.Header.Get("Client-Id") != s.clientID {
+		writeJSON(w, http.StatusUnauthorized, map[string]string{"message": "Invalid OAuth token"})
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"data": []map[string]string{{"id": s.userID, "login": s.login, "display_name": s.name}},
+	})
+}
+
+// ==========================================
+// EventSub
+// ==========================================
+
+// appAuthorized checks the request carries an app access token.
+func (s *standin) appAuthorized(w http.ResponseWriter, r *http.Request) bool {
+	token, _ := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	s.mu.Lock()
+	ok := s.appTokens[token]
+	s.mu.Unlock()
+	if !ok || r.Header.Get("Client-Id") != s.clientID {
+		writeJSON(w, http.StatusUnauthorized, map[string]string{"message": "Invalid OAuth token"})
+		return false
+	}
+	return true
+}
+
+// createSubscription stores the subscription and, like Twitch, verifies the
+// callback in the background before enabling it.
+func (s *standin) createSubscription(w http.ResponseWriter, r *http.Request) {
+	if !s.appAuthorized(w, r) {
+		return
+	}
+	var sub subscription
+	if err := json.NewDecoder(r.Body).Decode(&sub); err != nil || sub.Transport.Method != "webhook" ||
+		sub.Condition["broadcaster_user_id"] == "" || len(sub.Transport.Secret) < 10 {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"message": "invalid subscription"})
+		return
+	}
+
+	s.mu.Lock()
+	for _, existing := range s.subs {
+		if existing.Type == sub.Type && existing.Condition["broadcaster_user_id"] == sub.Condition["broadcaster_user_id"] &&
+			existing.Transport.Callback == sub.Transport.Callback {
+			s.mu.Unlock()
+			writeJSON(w, http.StatusConflict, map[string]string{"message": "subscription already exists"})
+			return
+		}
+	}
+	sub.ID = rand.Text()
+	sub.Status = "webhook_callback_verification_pending"
+	sub.CreatedAt = time.Now().UTC()
+	s.subs[sub.ID] = &sub
+	out := sub
+	s.mu.Unlock()
+
+	go s.verify(sub)
+	out.Transport.Secret = ""
+	writeJSON(w, http.StatusAccepted, map[string]interface{}{"data": []subscription{out}, "total": 1})
+}
+
+// verify sends the challenge; the callback must echo it back with a 200.
+func (s *standin) verify(sub subscription) {
+	challenge := rand.Text()
+	resp, err := s.deliver(sub, twitch.MessageVerification, map[string]interface{}{"challenge": challenge})
+	newStatus := "enabled"
+	if err != nil || resp.StatusCode != http.StatusOK || resp.Body != challenge {
+		newStatus = "webhook_callback_verification_failed"
+	}
+	log.Printf("eventsub: subscription %s (%s for %s): %s", sub.ID, sub.Type, sub.Condition["broadcaster_user_id"], newStatus)
+
+	s.mu.Lock()
+	if stored, ok := s.subs[sub.ID]; ok {
+		stored.Status = newStatus
+	}
+	s.mu.Unlock()
+}
+
+// listSubscriptions supports the user_id filter the Core uses.
+func (s *standin) listSubscriptions(w http.ResponseWriter, r *http.Request) {
+	if !s.appAuthorized(w, r) {
+		return
+	}
+	userID := r.URL.Query().Get("user_id")
+	data := []subscription{}
+	s.mu.Lock()
+	for _, sub := range s.subs {
+		if userID == "" || sub.Condition["broadcaster_user_id"] == userID {
+			out := *sub
+			out.Transport.Secret = ""
+			data = append(data, out)
+		}
+	}
+	s.mu.Unlock()
+	writeJSON(w, http.StatusOK, map[string]interface{}{"data": data, "total": len(data)})
+}
+
+func (s *standin) deleteSubscription(w http.ResponseWriter, r *http.Request) {
+	if !s.appAuthorized(w, r) {
+		return
+	}
+	id := r.URL.Query().Get("id")
+	s.mu.Lock()
+	_, ok := s.subs[id]
+	delete(s.subs, id)
+	s.mu.Unlock()
+	if !ok {
+		writeJSON(w, http.StatusNotFound, map[string]string{"message": "subscription not found"})
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// triggerStream handles POST /standin/streams/{userID}/{online|offline} by
+// notifying every enabled subscription for it, and reports what each callback said.
+func (s *standin) triggerStream(w http.ResponseWriter, r *http.Request) {
+	userID := r.PathValue("userID")
+	var typ string
+	switch r.PathValue("state") {
+	case "online":
+		typ = twitch.StreamOnline
+	case "offline":
+		typ = twitch.StreamOffline
+	default:
+		http.Error(w, "state must be online or offline", http.StatusBadRequest)
+		return
+	}
+
+	login := s.loginFor(userID)
+	event := map[string]interface{}{
+		"broadcaster_user_id":    userID,
+		"broadcaster_user_login": login,
+		"broadcaster_user_name":  login,
+	}
+	if typ == twitch.StreamOnline {
+		event["id"] = rand.Text()
+		event["type"] = "live"
+		event["started_at"] = time.Now().UTC().Format(time.RFC3339)
+	}
+
+	var targets []subscription
+	s.mu.Lock()
+	for _, sub := range s.subs {
+		if sub.Type == typ && sub.Status == "enabled" && sub.Condition["broadcaster_user_id"] == userID {
+			targets = append(targets, *sub)
+		}
+	}
+	s.mu.Unlock()
+
+	results := []string{}
+	for _, sub := range targets {
+		resp, err := s.deliver(sub, twitch.MessageNotification, map[string]interface{}{"event": event})
+		if err != nil {
+			results = append(results, fmt.Sprintf("%s: %v", sub.Transport.Callback, err))
+			continue
+		}
+		results = append(results, fmt.Sprintf("%s: %d", sub.Transport.Callback, resp.StatusCode))
+	}
+	vod := s.recordStream(userID, login, typ == twitch.StreamOnline)
+	writeJSON(w, http.StatusOK, map[string]interface{}{"type": typ, "delivered": results, "vod_url": vod.URL})
+}
+
+func (s *standin) loginFor(userID string) string {
+	if userID == s.userID {
+		return s.login
+	}
+	return "user" + userID
+}
+
+// ==========================================
+// Videos
+// ==========================================
+
+// recordStream starts a past broadcast when a stream goes online and sets
+// its duration when it goes offline.
+func (s *standin) recordStream(userID, login string, online bool) *video {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if v, ok := s.liveVideos[userID]; ok {
+		if online {
+			return v
+		}
+		delete(s.liveVideos, userID)
+		v.Duration = time.Since(v.CreatedAt).Round(time.Second).String()
+		return v
+	}
+	v := s.newVideoLocked(userID, login, "archive", time.Now().UTC(), "0s")
+	if online {
+		s.liveVideos[userID] = v
+	}
+	return v
+}
+
+func (s *standin) newVideoLocked(userID, login, typ string, createdAt time.Time, duration string) *video {
+	s.nextVideo++
+	id := fmt.Sprint(s.nextVideo)
+	v := &video{
+		ID:        id,
+		UserID:    userID,
+		UserLogin: login,
+		Type:      typ,
+		CreatedAt: createdAt,
+		Duration:  duration,
+		URL:       "https://www.twitch.tv/videos/" + id,
+	}
+	s.videos[id] = v
+	return v
+}
+
+// getVideos supports the id filter the Core uses. Unknown IDs are a 404, like Helix.
+func (s *standin) getVideos(w http.ResponseWriter, r *http.Request) {
+	if !s.appAuthorized(w, r) {
+		return
+	}
+	s.mu.Lock()
+	v, ok := s.videos[r.URL.Query().Get("id")]
+	var out video
+	if ok {
+		out = *v
+		if s.liveVideos[v.UserID] == v {
+			out.Duration = time.Since(v.CreatedAt).Round(time.Second).String()
+		}
+	}
+	s.mu.Unlock()
+	if !ok {
+		writeJSON(w, http.StatusNotFound, map[string]string{"message": "video not found"})
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{"data": []video{out}})
+}
+
+// createVideo handles POST /standin/videos for faking any kind of video:
+// {"user_id", "type", "created_at", "duration"}, all optional.
+func (s *standin) createVideo(w http.ResponseWriter, r *http.Request) {
+	req := video{UserID: s.userID, Type: "archive", CreatedAt: time.Now().UTC(), Duration: "1h0m0s"}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && err != io.EOF {
+		http.Error(w, "bad video", http.StatusBadRequest)
+		return
+	}
+	s.mu.Lock()
+	v := s.newVideoLocked(req.UserID, s.loginFor(req.UserID), req.Type, req.CreatedAt, req.Duration)
+	s.mu.Unlock()
+	writeJSON(w, http.StatusOK, v)
+}
+
+// callbackResponse is what the webhook callback answered.
+type callbackResponse struct {
+	StatusCode int
+	Body       string
+}
+
+// deliver POSTs a signed EventSub message to the subscription's callback.
+func (s *standin) deliver(sub subscription, messageType string, fields map[string]interface{}) (*callbackResponse, error) {
+	secret := sub.Transport.Secret
+	sub.Transport.Secret = ""
+	fields["subscription"] = sub
+	body, err := json.Marshal(fields)
+	if err != nil {
+		return nil, err
+	}
+
+	id, ts := rand.Text(), time.Now().UTC().Format(time.RFC3339Nano)
+	req, err := http.NewRequest(http.MethodPost, sub.Transport.Callback, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(twitch.HeaderMessageID, id)
+	req.Header.Set(twitch.HeaderTimestamp, ts)
+	req.Header.Set(twitch.HeaderSignature, twitch.SignEventSub(secret, id, ts, body))
+	req.Header.Set(twitch.HeaderMessageType, messageType)
+	req.Header.Set("Twitch-Eventsub-Subscription-Type", sub.Type)
+	req.Header.Set("Twitch-Eventsub-Subscription-Version", sub.Version)
+
+	client := &http.Client{Timeout: 10 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	raw, _ := io.ReadAll(io.LimitReader(resp.Body, 1<<16))
+	return &callbackResponse{StatusCode: resp.StatusCode, Body: string(raw)}, nil
+}
+
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
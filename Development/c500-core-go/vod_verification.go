@@ -0,0 +1,86 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/url"
+	"strings"
+
+	"c500-core-go/internal/domain"
+)
+
+// TwitchVideos looks up videos on Twitch to verify live fulfillments.
+// This implementation lives in internal/integrations/twitch/client.go
+// (cmd/twitchstandin fakes Helix /videos for local runs).
+type TwitchVideos interface {
+	// GetVideo returns nil, nil if the video doesn't exist.
+	GetVideo(ctx context.Context, videoID string) (*domain.TwitchVideo, error)
+}
+
+// ParseTwitchVideoURL extracts the video ID from a twitch.tv/videos/<id> link
+// (or the old twitch.tv/<login>/v/<id> form).
+func ParseTwitchVideoURL(raw string) (string, bool) {
+	u, err := url.Parse(strings.TrimSpace(raw))
+	if err != nil || (u.Scheme != "https" && u.Scheme != "http") {
+		return "", false
+	}
+	switch strings.ToLower(u.Hostname()) {
+	case "twitch.tv", "www.twitch.tv", "m.twitch.tv":
+	default:
+		return "", false
+	}
+
+	parts := strings.Split(strings.Trim(u.Path, "/"), "/")
+	var id string
+	switch {
+	case len(parts) == 2 && parts[0] == "videos":
+		id = parts[1]
+	case len(parts) == 3 && parts[1] == "v":
+		id = parts[2]
+	default:
+		return "", false
+	}
+	if id == "" || strings.Trim(id, "0123456789") != "" {
+		return "", false
+	}
+	return id, true
+}
+
+// checkVOD returns why a VOD can't be trusted automatically, or "" if it is a
+// past broadcast from the seller's linked channel, streamed after the order
+// was paid.
+func (s *fulfillmentService) checkVOD(ctx context.Context, order *domain.Order, seller *domain.Builder, vodURL string) string {
+	videoID, ok := ParseTwitchVideoURL(vodURL)
+	if !ok {
+		return "not a Twitch video link"
+	}
+	if seller.Twitch == nil {
+		return "the builder has no linked Twitch channel"
+	}
+
+	video, err := s.videos.GetVideo(ctx, videoID)
+	if err != nil {
+		log.Printf("fulfillment: failed to look up twitch video %s for order %s: %v", videoID, order.ID, err)
+		return "Twitch couldn't be reached to check the video"
+	}
+	if video == nil {
+		return "the video doesn't exist on Twitch (deleted, or not public)"
+	}
+	if video.UserID != seller.Twitch.UserID {
+		return fmt.Sprintf("the video is from twitch.tv/%s, not the builder's channel twitch.tv/%s", video.UserLogin, seller.Twitch.Login)
+	}
+	if video.Type != domain.TwitchVideoArchive {
+		return fmt.Sprintf("the video is not a past broadcast (it is a %s)", video.Type)
+	}
+
+	// Orders are created when the payment succeeds.
+	paidAt := order.CreatedAt
+	if video.CreatedAt.Add(video.Duration).Before(paidAt) {
+		return "the stream ended before the order was paid"
+	}
+	if video.CreatedAt.Before(paidAt) {
+		return "the stream started before the order was paid"
+	}
+	return ""
+}
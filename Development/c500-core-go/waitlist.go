@@ -0,0 +1,13 @@
+package domain
+
+import "time"
+
+// WaitlistEntry is a buyer waiting for a drop they couldn't buy: it was
+// reserved by someone else's checkout, or not live yet. They're DMed once
+// when it becomes available, then the entry is removed.
+// Stored in the "waitlist" subcollection under drops/{dropID}, keyed by DiscordID.
+type WaitlistEntry struct {
+	DropID    string    `json:"drop_id" firestore:"drop_id"`
+	DiscordID string    `json:"discord_id" firestore:"discord_id"`
+	JoinedAt  time.Time `json:"joined_at" firestore:"joined_at"`
+}
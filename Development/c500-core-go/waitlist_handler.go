@@ -0,0 +1,68 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"c500-core-go/internal/service"
+)
+
+// WaitlistHandler lets buyers wait for a drop they couldn't buy yet.
+type WaitlistHandler struct {
+	waitlistService service.WaitlistService
+}
+
+// NewWaitlistHandler is the constructor.
+func NewWaitlistHandler(ws service.WaitlistService) *WaitlistHandler {
+	return &WaitlistHandler{
+		waitlistService: ws,
+	}
+}
+
+// RegisterRoutes connects the HTTP URLs to the handler functions.
+// This is called in main.go, on the internal group: joining DMs whichever
+// Discord user is in the body.
+func (h *WaitlistHandler) RegisterRoutes(router *gin.RouterGroup) {
+	router.POST("/drops/:dropID/waitlist", h.Join)
+}
+
+// ==========================================
+// Request/Response Structs (Data Contracts)
+// ==========================================
+
+// joinWaitlistRequest is sent by the bot when a buyer clicks "Join Waitlist".
+type joinWaitlistRequest struct {
+	DiscordID string `json:"discord_id" binding:"required"`
+}
+
+// ==========================================
+// Handler Functions
+// ==========================================
+
+// Join handles POST /api/v1/drops/:dropID/waitlist
+func (h *WaitlistHandler) Join(c *gin.Context) {
+	var req joinWaitlistRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	err := h.waitlistService.Join(c.Request.Context(), c.Param("dropID"), req.DiscordID)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrDropNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": "Drop not found"})
+		case errors.Is(err, service.ErrWaitlistClosed):
+			c.JSON(http.StatusConflict, gin.H{"error": "This drop has sold or been withdrawn"})
+		case errors.Is(err, service.ErrDropStillAvailable):
+			c.JSON(http.StatusConflict, gin.H{"error": "This drop is available right now, grab it!"})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to join waitlist"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"status": "waiting"})
+}
@@ -0,0 +1,176 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"c500-core-go/internal/domain"
+	"c500-core-go/internal/events"
+)
+
+var (
+	// ErrWaitlistClosed means the drop sold or was withdrawn, so it won't come back.
+	ErrWaitlistClosed = errors.New("this drop has sold or been withdrawn")
+	// ErrDropStillAvailable means there's nothing to wait for: it can be bought now.
+	ErrDropStillAvailable = errors.New("this drop is available right now")
+)
+
+// WaitlistRepository defines the DB operations for drop waitlists.
+// Implemented in internal/database/firestore.go
+type WaitlistRepository interface {
+	// AddToWaitlist saves the entry; joining twice keeps one entry.
+	AddToWaitlist(ctx context.Context, entry *domain.WaitlistEntry) error
+	// TakeWaitlist removes and returns everyone waiting for the drop, so
+	// each of them is notified once even if two instances race.
+	TakeWaitlist(ctx context.Context, dropID string) ([]domain.WaitlistEntry, error)
+}
+
+// WaitlistService defines the methods the waitlist handler uses.
+type WaitlistService interface {
+	Join(ctx context.Context, dropID, discordID string) error
+}
+
+// waitlistService is the concrete implementation holding business logic.
+type waitlistService struct {
+	waitlist  WaitlistRepository
+	drops     DropRepository
+	publisher events.Publisher
+}
+
+// NewWaitlistService constructor used in main.go.
+func NewWaitlistService(wr WaitlistRepository, dr DropRepository, publisher events.Publisher) *waitlistService {
+	return &waitlistService{
+		waitlist:  wr,
+		drops:     dr,
+		publisher: publisher,
+	}
+}
+
+// ==========================================
+// Business Logic
+// ==========================================
+
+// Join puts the buyer on the drop's waitlist. Only drops that may still
+// become buyable can be waited for: reserved ones (the checkout can expire)
+// and scheduled ones.
+func (s *waitlistService) Join(ctx context.Context, dropID, discordID string) error {
+	drop, err := s.drops.GetDropByID(ctx, dropID)
+	if err != nil {
+		return fmt.Errorf("failed to fetch drop: %w", ErrDropNotFound)
+	}
+
+	switch {
+	case drop.IsLive(time.Now().UTC()):
+		return ErrDropStillAvailable
+	case drop.Status != domain.StatusPending && drop.Status != domain.StatusScheduled:
+		return ErrWaitlistClosed
+	}
+
+	entry := &domain.WaitlistEntry{
+		DropID:    dropID,
+		DiscordID: discordID,
+		JoinedAt:  time.Now().UTC(),
+	}
+	if err := s.waitlist.AddToWaitlist(ctx, entry); err != nil {
+		return fmt.Errorf("failed to join waitlist: %w", err)
+	}
+	return nil
+}
+
+// NotifyAvailable hands the drop's waitlist to the bots to DM. The list is
+// cleared: it's first come, first served, and anyone who misses it can join
+// again if the drop gets reserved again.
+func (s *waitlistService) NotifyAvailable(ctx context.Context, drop *domain.Drop) error {
+	entries, err := s.waitlist.TakeWaitlist(ctx, drop.ID)
+	if err != nil {
+		return fmt.Errorf("failed to read waitlist: %w", err)
+	}
+	if len(entries) == 0 {
+		return nil
+	}
+
+	discordIDs := make([]string, 0, len(entries))
+	for _, e := range entries {
+		discordIDs = append(discordIDs, e.DiscordID)
+	}
+	data := map[string]interface{}{
+		"drop_id":           drop.ID,
+		"title":             drop.Title,
+		"price_in_cents":    drop.PriceInCents,
+		"seller_discord_id": drop.SellerDiscordID,
+		"discord_ids":       discordIDs,
+	}
+	if err := s.publisher.Publish(ctx, events.DropWaitlist, data); err != nil {
+		return fmt.Errorf("failed to publish waitlist event: %w", err)
+	}
+	return nil
+}
+
+// ==========================================
+// Hooks
+// ==========================================
+
+// waitlistDropRepository wraps a DropRepository so a drop that becomes
+// available again (an abandoned checkout was released) notifies its waitlist.
+type waitlistDropRepository struct {
+	DropRepository
+	waitlist *waitlistService
+}
+
+// NewWaitlistDropRepository constructor used in main.go. Give the result to
+// any service that can make a drop available instead of the bare repository.
+func NewWaitlistDropRepository(repo DropRepository, ws *waitlistService) DropRepository {
+	return &waitlistDropRepository{
+		DropRepository: repo,
+		waitlist:       ws,
+	}
+}
+
+// UpdateDropStatus saves the new status, then notifies the waitlist if the
+// drop can be bought again.
+func (r *waitlistDropRepository) UpdateDropStatus(ctx context.Context, dropID string, newStatus domain.DropStatus) error {
+	if err := r.DropRepository.UpdateDropStatus(ctx, dropID, newStatus); err != nil {
+		return err
+	}
+	if newStatus != domain.StatusAvailable {
+		return nil
+	}
+	drop, err := r.DropRepository.GetDropByID(ctx, dropID)
+	if err == nil {
+		err = r.waitlist.NotifyAvailable(ctx, drop)
+	}
+	if err != nil {
+		// The status IS saved; never fail the caller over a missed DM.
+		log.Printf("drop %s is available again but the waitlist wasn't notified: %v", dropID, err)
+	}
+	return nil
+}
+
+// waitlistAnnouncer wraps the fan-out so a scheduled drop going live also
+// notifies the people who asked to be told.
+type waitlistAnnouncer struct {
+	FanoutService
+	waitlist *waitlistService
+}
+
+// NewWaitlistAnnouncer constructor used in main.go, for the drop scheduler.
+func NewWaitlistAnnouncer(fanout FanoutService, ws *waitlistService) FanoutService {
+	return &waitlistAnnouncer{
+		FanoutService: fanout,
+		waitlist:      ws,
+	}
+}
+
+// AnnounceDrop announces the drop, then notifies the waitlist when it's the go-live.
+func (a *waitlistAnnouncer) AnnounceDrop(ctx context.Context, drop *domain.Drop, eventType string) error {
+	err := a.FanoutService.AnnounceDrop(ctx, drop, eventType)
+	if eventType == events.DropLive {
+		if werr := a.waitlist.NotifyAvailable(ctx, drop); werr != nil {
+			log.Printf("drop %s is live but the waitlist wasn't notified: %v", drop.ID, werr)
+		}
+	}
+	return err
+}
@@ -1,8 +1,10 @@
 package http
 
 import (
+	"context"
 	"encoding/json"
 	"io"
+	"log"
 	"net/http"
 	"os" // Needed to get the webhook secret from environment variables
 
@@ -97,6 +99,21 @@ func (h *WebhookHandler) HandleStripeWebhook(c *gin.Context) {
 			}
 		}()
 
+	case "checkout.session.expired":
+		// The buyer never paid. Release the drop so others (and its waitlist) can buy it.
+		var session stripe.CheckoutSession
+		if err := json.Unmarshal(event.Data.Raw, &session); err != nil {
+			c.Status(http.StatusBadRequest)
+			return
+		}
+		dropID := session.Metadata["drop_id"]
+
+		go func() {
+			if err := h.checkoutService.ReleaseCheckout(context.Background(), dropID); err != nil {
+				log.Printf("stripe webhook: failed to release drop %s after its checkout expired: %v", dropID, err)
+			}
+		}()
+
 	default:
 		// Handle other event types we don't care about (e.g., 'payment_intent.created').
 		// Just return 200 OK so Stripe knows we received it.
@@ -15,16 +15,20 @@ c500-web-go/
 │   ├── pages/              # Individual page content
 │   │   ├── home.html       # Landing page content
 │   │   ├── success.html    # "Thanks for your order!"
+│   │   ├── order.html      # A buyer's order: production steps, build updates + photos, tracking
 │   │   └── profile.html    # The complex one: renders custom builder content
 │   └── partials/           # Reusable components (e.g., navbar, footer)
 │       ├── nav.html
-│       └── footer.html
+│       ├── footer.html
+│       └── drop_specs.html # Typed drop specs (Layout, Switches, PCB...) from the Core API
 │
 └── internal/
     ├── config/             # Env vars (PORT, CORE_API_URL, INTERNAL_API_KEY)
     │   └── config.go
     ├── clients/            # Communicates with c500-core-go
-    │   └── core_client.go  # HTTP client to fetch builder data
+    │   └── core_client.go  # HTTP client to fetch builder data, drops, stats and order pages, and report page views
     └── handlers/           # The controllers that render HTML
         ├── static_handlers.go # Home, Success, Cancel pages
-        └── profile_handler.go # The complex handler for builder pages
+        ├── profile_handler.go # The complex handler for builder pages (+ signed revision previews, view counting)
+        ├── order_handler.go   # Buyer order pages (signed link from the bot's DMs)
+        └── shortcodes.go      # Expands [[drops]], [[sold count]], [[hit counter]]... into live widgets
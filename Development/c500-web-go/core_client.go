@@ -0,0 +1,281 @@
+package clients
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	ErrNotFound  = errors.New("not found")
+	ErrForbidden = errors.New("forbidden")
+)
+
+// CoreAPIClient talks to c500-core-go over its internal HTTP API.
+type CoreAPIClient struct {
+	baseURL string // e.g. http://core:8080/api/v1
+	apiKey  string
+	http    *http.Client
+}
+
+// NewCoreAPIClient is the constructor used in main.go.
+func NewCoreAPIClient(baseURL, apiKey string) *CoreAPIClient {
+	return &CoreAPIClient{
+		baseURL: strings.TrimRight(baseURL, "/"),
+		apiKey:  apiKey,
+		http:    &http.Client{Timeout: 5 * time.Second},
+	}
+}
+
+// PublicBuilderData is everything a profile page needs, already sanitized by the Core.
+type PublicBuilderData struct {
+	DiscordID   string
+	DisplayName string
+	// Slug is the builder's canonical username. The profile page redirects to it
+	// when the URL used an old slug, different casing or a Discord ID.
+	Slug           string
+	ProfileHTMLRaw string
+	ProfileCSSRaw  string
+	RevisionID     string
+	// TwitchLogin is the builder's verified Twitch channel, "" if not linked.
+	TwitchLogin string
+	// TwitchLive is true while that channel is streaming.
+	TwitchLive bool
+}
+
+// publicProfileResponse mirrors the Core's JSON.
+type publicProfileResponse struct {
+	DiscordID   string `json:"discord_id"`
+	DisplayName string `json:"display_name"`
+	Slug        string `json:"slug"`
+	Profile     struct {
+		HTML string `json:"html"`
+		CSS  string `json:"css"`
+	} `json:"profile_data"`
+	RevisionID  string `json:"revision_id"`
+	TwitchLogin string `json:"twitch_login"`
+	TwitchLive  bool   `json:"twitch_live"`
+}
+
+// GetPublicBuilderData fetches a builder's live (published) profile by slug.
+// The Core also accepts retired slugs (during their redirect grace period) and Discord IDs.
+func (c *CoreAPIClient) GetPublicBuilderData(ctx context.Context, slug string) (*PublicBuilderData, error) {
+	return c.getProfile(ctx, "/profiles/"+url.PathEscape(slug))
+}
+
+// GetProfilePreview fetches an unpublished revision. The Core checks the signed token,
+// which is bound to the builder's Discord ID (not their slug, which can change).
+func (c *CoreAPIClient) GetProfilePreview(ctx context.Context, discordID, revisionID, token string) (*PublicBuilderData, error) {
+	return c.getProfile(ctx, "/builders/"+url.PathEscape(discordID)+"/profile/revisions/"+
+		url.PathEscape(revisionID)+"?token="+url.QueryEscape(token))
+}
+
+func (c *CoreAPIClient) getProfile(ctx context.Context, path string) (*PublicBuilderData, error) {
+	var body publicProfileResponse
+	if err := c.getJSON(ctx, path, &body); err != nil {
+		return nil, err
+	}
+	return &PublicBuilderData{
+		DiscordID:      body.DiscordID,
+		DisplayName:    body.DisplayName,
+		Slug:           body.Slug,
+		ProfileHTMLRaw: body.Profile.HTML,
+		ProfileCSSRaw:  body.Profile.CSS,
+		RevisionID:     body.RevisionID,
+		TwitchLogin:    body.TwitchLogin,
+		TwitchLive:     body.TwitchLive,
+	}, nil
+}
+
+// DropSummary is the part of a drop the profile widgets show.
+type DropSummary struct {
+	ID           string   `json:"id"`
+	Title        string   `json:"title"`
+	PriceInCents int64    `json:"price_in_cents"`
+	ImageURLs    []string `json:"image_urls"`
+}
+
+// ListBuilderDrops returns a builder's drops that are live in the shop, newest first.
+func (c *CoreAPIClient) ListBuilderDrops(ctx context.Context, discordID string, limit int) ([]DropSummary, error) {
+	var body struct {
+		Drops []DropSummary `json:"drops"`
+	}
+	path := "/drops?seller=" + url.QueryEscape(discordID) + "&sort=newest&limit=" + strconv.Itoa(limit)
+	if err := c.getJSON(ctx, path, &body); err != nil {
+		return nil, err
+	}
+	return body.Drops, nil
+}
+
+// BuilderStats are the live numbers behind profile widgets.
+type BuilderStats struct {
+	SoldCount int64 `json:"sold_count"`
+}
+
+// GetBuilderStats fetches a builder's sales numbers.
+func (c *CoreAPIClient) GetBuilderStats(ctx context.Context, discordID string) (*BuilderStats, error) {
+	var stats BuilderStats
+	if err := c.getJSON(ctx, "/builders/"+url.PathEscape(discordID)+"/stats", &stats); err != nil {
+		return nil, err
+	}
+	return &stats, nil
+}
+
+// GuestbookEntry is one visible message on a builder's guestbook.
+type GuestbookEntry struct {
+	ID         string    `json:"id"`
+	AuthorName string    `json:"author_name"`
+	Message    string    `json:"message"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// ListGuestbook returns a builder's visible guestbook entries, newest first.
+func (c *CoreAPIClient) ListGuestbook(ctx context.Context, discordID string, limit int) ([]GuestbookEntry, error) {
+	var body struct {
+		Entries []GuestbookEntry `json:"entries"`
+	}
+	path := "/builders/" + url.PathEscape(discordID) + "/guestbook?limit=" + strconv.Itoa(limit)
+	if err := c.getJSON(ctx, path, &body); err != nil {
+		return nil, err
+	}
+	return body.Entries, nil
+}
+
+// PageView is one page render reported to the Core's analytics. The IP and
+// user agent are only used there to filter bots and count unique visitors;
+// the Core never stores them.
+type PageView struct {
+	BuilderID string `json:"builder_id,omitempty"`
+	DropID    string `json:"drop_id,omitempty"`
+	IP        string `json:"ip"`
+	UserAgent string `json:"user_agent"`
+}
+
+// RecordView reports a profile (BuilderID) or product page (DropID) view.
+func (c *CoreAPIClient) RecordView(ctx context.Context, view PageView) error {
+	return c.postJSON(ctx, "/analytics/views", view)
+}
+
+// GetHitCount returns a builder's all-time profile views, for the hit counter widget.
+func (c *CoreAPIClient) GetHitCount(ctx context.Context, discordID string) (int64, error) {
+	var body struct {
+		ProfileViews int64 `json:"profile_views"`
+	}
+	if err := c.getJSON(ctx, "/builders/"+url.PathEscape(discordID)+"/analytics/hits", &body); err != nil {
+		return 0, err
+	}
+	return body.ProfileViews, nil
+}
+
+// OrderPage is a buyer's view of their order: production timeline, progress
+// updates and carrier tracking.
+type OrderPage struct {
+	OrderID          string          `json:"order_id"`
+	DropID           string          `json:"drop_id"`
+	DropType         string          `json:"drop_type"`
+	ProductionStatus string          `json:"production_status"`
+	EscrowStatus     string          `json:"escrow_status"`
+	Carrier          string          `json:"carrier"`
+	TrackingNumber   string          `json:"tracking_number"`
+	TrackingURL      string          `json:"tracking_url"`
+	TrackingStatus   string          `json:"tracking_status"`
+	TrackingEvents   []TrackingEvent `json:"tracking_events"`
+	Timeline         []TimelineEntry `json:"timeline"`
+	UpdatesMuted     bool            `json:"updates_muted"`
+}
+
+// TimelineEntry is a production status change, or a progress update from the builder.
+type TimelineEntry struct {
+	Status    string    `json:"status"`
+	At        time.Time `json:"at"`
+	Note      string    `json:"note"`
+	PhotoURLs []string  `json:"photo_urls"`
+	Update    bool      `json:"update"`
+}
+
+// TrackingEvent is one carrier scan.
+type TrackingEvent struct {
+	At          time.Time `json:"at"`
+	Status      string    `json:"status"`
+	Description string    `json:"description"`
+	Location    string    `json:"location"`
+}
+
+// GetOrderPage fetches an order for its buyer. The Core checks the token from
+// the link the bot DMed them (ErrNotFound if it doesn't match).
+func (c *CoreAPIClient) GetOrderPage(ctx context.Context, orderID, token string) (*OrderPage, error) {
+	var page OrderPage
+	path := "/orders/" + url.PathEscape(orderID) + "/page?token=" + url.QueryEscape(token)
+	if err := c.getJSON(ctx, path, &page); err != nil {
+		return nil, err
+	}
+	return &page, nil
+}
+
+// postJSON performs an authenticated POST against the Core. Response bodies are ignored.
+func (c *CoreAPIClient) postJSON(ctx context.Context, path string, in interface{}) error {
+	payload, err := json.Marshal(in)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+path, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Internal-API-Key", c.apiKey)
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return fmt.Errorf("core api request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode >= 200 && resp.StatusCode < 300:
+		return nil
+	case resp.StatusCode == http.StatusNotFound:
+		return ErrNotFound
+	case resp.StatusCode == http.StatusForbidden:
+		return ErrForbidden
+	default:
+		return fmt.Errorf("core api returned %d", resp.StatusCode)
+	}
+}
+
+// getJSON performs an authenticated GET against the Core and decodes the response.
+func (c *CoreAPIClient) getJSON(ctx context.Context, path string, out interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+path, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("X-Internal-API-Key", c.apiKey)
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return fmt.Errorf("core api request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound:
+		return ErrNotFound
+	case http.StatusForbidden:
+		return ErrForbidden
+	default:
+		return fmt.Errorf("core api returned %d", resp.StatusCode)
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode core api response: %w", err)
+	}
+	return nil
+}
@@ -15,6 +15,7 @@ func main() {
 
 	// Init Handlers
 	profileHandler := handlers.NewProfileHandler(coreClient)
+	orderHandler := handlers.NewOrderHandler(coreClient)
 	// staticHandler := ...
 
 	r := gin.Default()
@@ -33,6 +34,11 @@ func main() {
 
 	// The public profile route
 	r.GET("/builder/:username", profileHandler.GetBuilderProfile)
+	// Unpublished revisions, behind a signed link from the bot
+	r.GET("/builder/:username/preview/:revisionID", profileHandler.GetProfilePreview)
+
+	// A buyer's order page, behind the signed link the bot DMs them
+	r.GET("/orders/:orderID", orderHandler.GetOrder)
 
 	// Stripe redirect routes
 	r.GET("/success", func(c *gin.Context) { /* render success.html */ })
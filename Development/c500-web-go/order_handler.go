@@ -0,0 +1,93 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"c500-web-go/internal/clients"
+)
+
+// OrderHandler renders a buyer's order page: where their build is, the
+// builder's progress updates and the carrier's tracking.
+type OrderHandler struct {
+	coreClient *clients.CoreAPIClient
+}
+
+// NewOrderHandler is the constructor used in main.go.
+func NewOrderHandler(coreClient *clients.CoreAPIClient) *OrderHandler {
+	return &OrderHandler{coreClient: coreClient}
+}
+
+// orderSteps are the production statuses in the order a build goes through them.
+var orderSteps = []struct{ Status, Label string }{
+	{"queued", "Queued"},
+	{"parts_ordered", "Parts ordered"},
+	{"in_production", "Building"},
+	{"shipped", "Shipped"},
+	{"delivered", "Delivered"},
+}
+
+// GetOrder handles GET /orders/:orderID?token=
+// The link comes from the buyer's Discord DMs; the token is what makes it theirs.
+func (h *OrderHandler) GetOrder(c *gin.Context) {
+	// Private to the buyer: keep it out of search results and shared caches.
+	c.Header("X-Robots-Tag", "noindex, nofollow")
+	c.Header("Cache-Control", "private, no-store")
+	c.Header("Referrer-Policy", "no-referrer")
+
+	order, err := h.coreClient.GetOrderPage(c.Request.Context(), c.Param("orderID"), c.Query("token"))
+	if err != nil {
+		c.HTML(http.StatusNotFound, "error.html", gin.H{"Message": "Order not found. Use the link from your Discord DMs."})
+		return
+	}
+
+	// Newest first reads better for a long build with many updates.
+	timeline := make([]clients.TimelineEntry, 0, len(order.Timeline))
+	for i := len(order.Timeline) - 1; i >= 0; i-- {
+		timeline = append(timeline, order.Timeline[i])
+	}
+	tracking := make([]clients.TrackingEvent, 0, len(order.TrackingEvents))
+	for i := len(order.TrackingEvents) - 1; i >= 0; i-- {
+		tracking = append(tracking, order.TrackingEvents[i])
+	}
+
+	c.HTML(http.StatusOK, "order.html", gin.H{
+		"Title":          "Your order",
+		"Order":          order,
+		"Steps":          orderSteps,
+		"StepReached":    stepReached(order),
+		"Timeline":       timeline,
+		"TrackingEvents": tracking,
+		"StatusLabel":    statusLabel,
+	})
+}
+
+// stepReached marks the steps the order has been through (or skipped past,
+// like parts_ordered for a ready-to-ship item).
+// Orders from before production tracking have no status and reach none.
+func stepReached(order *clients.OrderPage) map[string]bool {
+	reached := map[string]bool{}
+	for _, step := range orderSteps {
+		reached[step.Status] = true
+		if step.Status == order.ProductionStatus {
+			return reached
+		}
+	}
+	return map[string]bool{}
+}
+
+// statusLabel turns a status value (production or tracking) into words.
+func statusLabel(status string) string {
+	for _, step := range orderSteps {
+		if step.Status == status {
+			return step.Label
+		}
+	}
+	s := strings.ReplaceAll(status, "_", " ")
+	if s == "" {
+		return s
+	}
+	return strings.ToUpper(s[:1]) + s[1:]
+}
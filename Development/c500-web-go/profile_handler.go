@@ -1,8 +1,14 @@
 package handlers
 
 import (
+	"context"
+	"errors"
 	"html/template"
+	"log"
 	"net/http"
+	"net/url"
+	"strings"
+	"time"
 	"github.com/gin-gonic/gin"
 	"c500-web-go/internal/clients"
 )
@@ -11,6 +17,11 @@ type ProfileHandler struct {
 	coreClient *clients.CoreAPIClient
 }
 
+// NewProfileHandler is the constructor used in main.go.
+func NewProfileHandler(coreClient *clients.CoreAPIClient) *ProfileHandler {
+	return &ProfileHandler{coreClient: coreClient}
+}
+
 // GetBuilderProfile handles GET /builder/:username
 func (h *ProfileHandler) GetBuilderProfile(c *gin.Context) {
 	username := c.Param("username")
@@ -23,17 +34,110 @@ func (h *ProfileHandler) GetBuilderProfile(c *gin.Context) {
 		return
 	}
 
-	// 2. Prepare data for the template.
+	// Old slug, different casing or a raw Discord ID: send visitors (and search
+	// engines) to the canonical URL so shared links keep working after a rename.
+	if builderData.Slug != "" && builderData.Slug != username {
+		c.Redirect(http.StatusMovedPermanently, "/builder/"+url.PathEscape(builderData.Slug))
+		return
+	}
+
+	// Count the view (in the background; the page doesn't wait for analytics).
+	h.recordView(c, clients.PageView{BuilderID: builderData.DiscordID})
+
+	// 2. Expand widgets ([[drops]], [[guestbook]]...) from live Core data.
+	profileHTML, guestbookHTML := h.renderBody(c, builderData)
+
+	// 3. Prepare data for the template.
 	// CRITICAL STEP: Convert string data to template.HTML/CSS types.
 	// This tells the template engine: "Trust me, don't escape this."
 	data := gin.H{
 		"Title":          builderData.DisplayName + "'s Profile",
 		"BuilderName":    builderData.DisplayName,
-		// The Core API has already sanitized these strings.
-		"SafeCustomHTML": template.HTML(builderData.ProfileHTMLRaw),
+		// The Core API has already sanitized these strings (internal/sanitize):
+		// allowlisted HTML, and CSS scoped under .c500-profile with no url()/@import.
+		// Shortcodes like [[drops]] are expanded from our own templates (shortcodes.go).
+		"SafeCustomHTML": profileHTML,
+		"GuestbookHTML":  guestbookHTML,
 		"SafeCustomCSS":  template.CSS(builderData.ProfileCSSRaw),
+		"TwitchLogin":    builderData.TwitchLogin,
+		"TwitchLive":     builderData.TwitchLive,
+	}
+
+	// 4. Render the profile.html template with the prepared data
+	c.HTML(http.StatusOK, "profile.html", data)
+}
+
+// GetProfilePreview handles GET /builder/:username/preview/:revisionID?token=
+// It renders a revision that may not be published yet, so builders can check
+// a draft (or an old version before rolling back) exactly as it would look.
+func (h *ProfileHandler) GetProfilePreview(c *gin.Context) {
+	// Preview links are shared privately; keep them out of search results.
+	c.Header("X-Robots-Tag", "noindex, nofollow")
+	c.Header("Cache-Control", "private, no-store")
+
+	builderData, err := h.coreClient.GetProfilePreview(c.Request.Context(),
+		c.Param("username"), c.Param("revisionID"), c.Query("token"))
+	if err != nil {
+		if errors.Is(err, clients.ErrForbidden) {
+			c.HTML(http.StatusForbidden, "error.html", gin.H{"Message": "This preview link is invalid or has expired."})
+			return
+		}
+		c.HTML(http.StatusNotFound, "error.html", gin.H{"Message": "Preview not found."})
+		return
 	}
 
-	// 3. Render the profile.html template with the prepared data
+	// Same rendering as the live page; the Core sanitized revisions before saving them.
+	profileHTML, guestbookHTML := h.renderBody(c, builderData)
+	data := gin.H{
+		"Title":          builderData.DisplayName + "'s Profile (Preview)",
+		"BuilderName":    builderData.DisplayName,
+		"SafeCustomHTML": profileHTML,
+		"GuestbookHTML":  guestbookHTML,
+		"SafeCustomCSS":  template.CSS(builderData.ProfileCSSRaw),
+		"TwitchLogin":    builderData.TwitchLogin,
+		"TwitchLive":     builderData.TwitchLive,
+		"IsPreview":      true,
+		"RevisionID":     builderData.RevisionID,
+	}
 	c.HTML(http.StatusOK, "profile.html", data)
 }
+
+// renderBody expands shortcodes in the builder's HTML and renders the guestbook
+// below the profile, unless the builder placed it themselves with [[guestbook]].
+// The widget markup comes from our own auto-escaping templates (shortcodes.go).
+func (h *ProfileHandler) renderBody(c *gin.Context, builderData *clients.PublicBuilderData) (template.HTML, template.HTML) {
+	profileHTML, guestbookPlaced := expandShortcodes(c.Request.Context(), h.coreClient, builderData)
+	if guestbookPlaced {
+		return template.HTML(profileHTML), ""
+	}
+	return template.HTML(profileHTML), template.HTML(renderGuestbook(c.Request.Context(), h.coreClient, builderData))
+}
+
+// recordView reports a page view to the Core's analytics without cookies: the
+// Core only uses the IP and user agent to filter bots and count unique visitors.
+// HEAD requests and browser prefetches aren't views. Previews never call this.
+func (h *ProfileHandler) recordView(c *gin.Context, view clients.PageView) {
+	if c.Request.Method != http.MethodGet || isPrefetch(c.Request) {
+		return
+	}
+	view.IP = c.ClientIP()
+	view.UserAgent = c.Request.UserAgent()
+
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+		defer cancel()
+		if err := h.coreClient.RecordView(ctx, view); err != nil {
+			log.Printf("analytics: failed to record view of %s%s: %v", view.BuilderID, view.DropID, err)
+		}
+	}()
+}
+
+// isPrefetch reports whether the browser is only prefetching/prerendering the page.
+func isPrefetch(r *http.Request) bool {
+	for _, h := range []string{"Sec-Purpose", "Purpose", "X-Purpose", "X-Moz"} {
+		if strings.Contains(strings.ToLower(r.Header.Get(h)), "prefetch") {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,286 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"html/template"
+	"log"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"c500-web-go/internal/clients"
+)
+
+// Shortcodes let builders put live data in their otherwise static profile HTML:
+//
+//	[[drops]] or [[drops limit=4]]   their drops currently in the shop (max 12)
+//	[[sold count]]                   how many drops they've sold
+//	[[hit counter]]                  retro visitor counter (all-time profile views)
+//	[[live-status]]                  link to their verified Twitch channel, "LIVE" while streaming
+//	[[guestbook]]                    visitor guestbook (otherwise shown below the profile)
+//
+// Expansion happens here, after the Core has sanitized the HTML, and the markup
+// comes from our own templates (auto-escaped), never from builder input. Only
+// text between tags is expanded, so a shortcode typed into an attribute value
+// stays inert text. Unknown shortcodes are left as-is.
+var shortcodeRe = regexp.MustCompile(`\[\[\s*([a-z][a-z-]*)((?:\s+[a-z]+(?:=[0-9]+)?)*)\s*\]\]`)
+
+const (
+	// maxShortcodes caps how many widgets one page can expand, so a profile
+	// can't turn a single page view into a pile of Core requests or a huge page.
+	maxShortcodes = 8
+
+	defaultDropsWidget = 4
+	maxDropsWidget     = 12
+
+	guestbookEntries = 20
+)
+
+var widgetTemplates = template.Must(template.New("widgets").Funcs(template.FuncMap{
+	"price":  formatPrice,
+	"digits": counterDigits,
+}).Parse(`
+{{- define "drops" -}}
+<div class="c500-widget c500-widget-drops">
+{{- range . -}}
+<div class="c500-drop-card">
+{{- if .ImageURLs }}<img src="{{ index .ImageURLs 0 }}" alt="{{ .Title }}" loading="lazy">{{ end -}}
+<span class="c500-drop-title">{{ .Title }}</span> <span class="c500-drop-price">{{ price .PriceInCents }}</span>
+</div>
+{{- else -}}
+<p class="c500-widget-empty">No drops in the shop right now.</p>
+{{- end -}}
+</div>
+{{- end -}}
+{{- define "guestbook" -}}
+<section class="c500-widget c500-widget-guestbook">
+<h3 class="c500-guestbook-title">Guestbook</h3>
+<p class="c500-guestbook-sign">Sign it from Discord: <code>!guestbook sign {{ .Slug }} your message</code></p>
+{{- range .Entries -}}
+<div class="c500-guestbook-entry">
+<span class="c500-guestbook-author">{{ .AuthorName }}</span> <time class="c500-guestbook-date" datetime="{{ .CreatedAt.Format "2006-01-02" }}">{{ .CreatedAt.Format "Jan 2, 2006" }}</time>
+<p class="c500-guestbook-message">{{ .Message }}</p>
+</div>
+{{- else -}}
+<p class="c500-widget-empty">No entries yet. Be the first to sign!</p>
+{{- end -}}
+</section>
+{{- end -}}
+{{- define "sold-count" -}}
+<span class="c500-widget c500-widget-sold">{{ . }}</span>
+{{- end -}}
+{{- define "live-status" -}}
+<a class="c500-widget c500-widget-live{{ if .Live }} c500-live-now{{ end }}" href="https://www.twitch.tv/{{ .Login }}" rel="noopener" target="_blank">
+{{- if .Live }}<span class="c500-live-badge">LIVE</span> {{ end }}twitch.tv/{{ .Login }}</a>
+{{- end -}}
+{{- define "hit-counter" -}}
+<span class="c500-widget c500-widget-hits" title="{{ . }} visitors">
+{{- range digits . }}<span class="c500-hit-digit">{{ . }}</span>{{ end -}}
+</span>
+{{- end -}}
+`))
+
+// widgetSource fetches Core data for one page render, at most once per kind.
+type widgetSource struct {
+	ctx         context.Context
+	core        *clients.CoreAPIClient
+	discordID   string
+	slug        string
+	twitchLogin string
+	twitchLive  bool
+
+	drops        []clients.DropSummary
+	dropsFetched bool
+	dropsOK      bool
+	stats        *clients.BuilderStats
+	statsFetched bool
+
+	// guestbookPlaced records that the builder put [[guestbook]] in their page,
+	// so the default one below the profile is left out.
+	guestbookPlaced bool
+}
+
+// expandShortcodes replaces shortcodes in the builder's sanitized profile HTML
+// with widget markup. It also reports whether the page placed its own guestbook.
+func expandShortcodes(ctx context.Context, core *clients.CoreAPIClient, builder *clients.PublicBuilderData) (string, bool) {
+	sanitized := builder.ProfileHTMLRaw
+	if !strings.Contains(sanitized, "[[") {
+		return sanitized, false
+	}
+	src := newWidgetSource(ctx, core, builder)
+	expanded := 0
+
+	expandText := func(text string) string {
+		return shortcodeRe.ReplaceAllStringFunc(text, func(code string) string {
+			if expanded >= maxShortcodes {
+				return ""
+			}
+			m := shortcodeRe.FindStringSubmatch(code)
+			out, ok := src.render(m[1], strings.Fields(m[2]))
+			if !ok {
+				return code
+			}
+			expanded++
+			return out
+		})
+	}
+
+	// The Core's sanitizer re-serializes everything and escapes < and > inside
+	// attribute values, so every '<' starts a tag and the next '>' ends it.
+	var out strings.Builder
+	rest := sanitized
+	for rest != "" {
+		lt := strings.IndexByte(rest, '<')
+		if lt < 0 {
+			out.WriteString(expandText(rest))
+			break
+		}
+		out.WriteString(expandText(rest[:lt]))
+		gt := strings.IndexByte(rest[lt:], '>')
+		if gt < 0 {
+			out.WriteString(rest[lt:])
+			break
+		}
+		out.WriteString(rest[lt : lt+gt+1])
+		rest = rest[lt+gt+1:]
+	}
+	return out.String(), src.guestbookPlaced
+}
+
+// renderGuestbook renders the guestbook for the section below the profile.
+func renderGuestbook(ctx context.Context, core *clients.CoreAPIClient, builder *clients.PublicBuilderData) string {
+	out, _ := newWidgetSource(ctx, core, builder).render("guestbook", nil)
+	return out
+}
+
+func newWidgetSource(ctx context.Context, core *clients.CoreAPIClient, builder *clients.PublicBuilderData) *widgetSource {
+	slug := builder.Slug
+	if slug == "" {
+		slug = builder.DiscordID
+	}
+	return &widgetSource{ctx: ctx, core: core, discordID: builder.DiscordID, slug: slug,
+		twitchLogin: builder.TwitchLogin, twitchLive: builder.TwitchLive}
+}
+
+// render returns the markup for one shortcode, or false if it isn't one we know.
+// A widget whose data can't be loaded renders as nothing rather than failing the page.
+func (w *widgetSource) render(name string, args []string) (string, bool) {
+	switch name {
+	case "drops":
+		limit := defaultDropsWidget
+		for _, arg := range args {
+			if v, ok := strings.CutPrefix(arg, "limit="); ok {
+				n, err := strconv.Atoi(v)
+				if err != nil || n < 1 {
+					return "", false
+				}
+				limit = min(n, maxDropsWidget)
+			}
+		}
+		drops, ok := w.loadDrops()
+		if !ok {
+			return "", true
+		}
+		return execWidget("drops", drops[:min(limit, len(drops))]), true
+
+	case "sold":
+		if len(args) != 1 || args[0] != "count" {
+			return "", false
+		}
+		stats, ok := w.loadStats()
+		if !ok {
+			return "", true
+		}
+		return execWidget("sold-count", stats.SoldCount), true
+
+	case "hit":
+		if len(args) != 1 || args[0] != "counter" {
+			return "", false
+		}
+		hits, err := w.core.GetHitCount(w.ctx, w.discordID)
+		if err != nil {
+			log.Printf("profile widget: failed to load hit count for %s: %v", w.discordID, err)
+			return "", true
+		}
+		return execWidget("hit-counter", hits), true
+
+	case "live-status":
+		// Links the builder's verified channel, flagged while they're streaming.
+		// Nothing renders if they haven't linked Twitch.
+		if w.twitchLogin == "" {
+			return "", true
+		}
+		return execWidget("live-status", struct {
+			Login string
+			Live  bool
+		}{w.twitchLogin, w.twitchLive}), true
+
+	case "guestbook":
+		if w.guestbookPlaced {
+			return "", true // One guestbook per page.
+		}
+		w.guestbookPlaced = true
+		entries, err := w.core.ListGuestbook(w.ctx, w.discordID, guestbookEntries)
+		if err != nil {
+			log.Printf("profile widget: failed to load guestbook for %s: %v", w.discordID, err)
+			return "", true
+		}
+		return execWidget("guestbook", struct {
+			Slug    string
+			Entries []clients.GuestbookEntry
+		}{w.slug, entries}), true
+	}
+	return "", false
+}
+
+func (w *widgetSource) loadDrops() ([]clients.DropSummary, bool) {
+	if !w.dropsFetched {
+		w.dropsFetched = true
+		drops, err := w.core.ListBuilderDrops(w.ctx, w.discordID, maxDropsWidget)
+		if err != nil {
+			log.Printf("profile widget: failed to load drops for %s: %v", w.discordID, err)
+			return nil, false
+		}
+		w.drops, w.dropsOK = drops, true
+	}
+	return w.drops, w.dropsOK
+}
+
+func (w *widgetSource) loadStats() (*clients.BuilderStats, bool) {
+	if !w.statsFetched {
+		w.statsFetched = true
+		stats, err := w.core.GetBuilderStats(w.ctx, w.discordID)
+		if err != nil {
+			log.Printf("profile widget: failed to load stats for %s: %v", w.discordID, err)
+			return nil, false
+		}
+		w.stats = stats
+	}
+	return w.stats, w.stats != nil
+}
+
+func execWidget(name string, data interface{}) string {
+	var buf bytes.Buffer
+	if err := widgetTemplates.ExecuteTemplate(&buf, name, data); err != nil {
+		log.Printf("profile widget: render %s: %v", name, err)
+		return ""
+	}
+	return buf.String()
+}
+
+// formatPrice renders cents as dollars, e.g. 45000 -> "$450.00".
+func formatPrice(cents int64) string {
+	return fmt.Sprintf("$%d.%02d", cents/100, cents%100)
+}
+
+// hitCounterDigits is the minimum width of the hit counter, zero-padded like
+// the odometers on old homepages.
+const hitCounterDigits = 6
+
+// counterDigits splits n into zero-padded digits, e.g. 42 -> "000042".
+func counterDigits(n int64) []string {
+	s := fmt.Sprintf("%0*d", hitCounterDigits, n)
+	return strings.Split(s, "")
+}
@@ -0,0 +1,135 @@
+package main
+
+import (
+	"log"
+	"strconv"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// Published by the Core's stream service when a builder with a linked Twitch
+// channel goes live or stops streaming.
+const (
+	eventBuildStreamOnline  = "build.stream_online"
+	eventBuildStreamOffline = "build.stream_offline"
+)
+
+// Published by the Core's production service when a seller posts a progress
+// update on an order whose buyer hasn't muted them.
+const eventOrderProgressUpdate = "order.progress_update"
+
+const (
+	colorLive     = 0x9146FF // Twitch purple
+	colorProgress = 0xB5EAD7 // Pastel Mint
+)
+
+// buildStreamData is the payload of build.stream_* events: one entry per
+// commission order that's in production with the builder.
+type buildStreamData struct {
+	SellerDiscordID string `json:"seller_discord_id"`
+	SellerName      string `json:"seller_name"`
+	TwitchLogin     string `json:"twitch_login"`
+	StreamURL       string `json:"stream_url"`
+	Orders          []struct {
+		OrderID        string `json:"order_id"`
+		DropID         string `json:"drop_id"`
+		BuyerDiscordID string `json:"buyer_discord_id"`
+		// ProductionStarted is true for orders this stream moved into production.
+		ProductionStarted bool `json:"production_started"`
+	} `json:"orders"`
+}
+
+// notifyBuyers DMs each buyer about the builder's stream. Buyers with closed
+// DMs are skipped; there's no channel to fall back to.
+func notifyBuyers(s *discordgo.Session, eventType string, d buildStreamData) {
+	for _, o := range d.Orders {
+		embed := streamEmbed(eventType, d, o.OrderID, o.ProductionStarted)
+		ch, err := s.UserChannelCreate(o.BuyerDiscordID)
+		if err == nil {
+			_, err = s.ChannelMessageSendEmbed(ch.ID, embed)
+		}
+		if err != nil {
+			log.Printf("builds: failed to DM buyer %s about order %s: %v", o.BuyerDiscordID, o.OrderID, err)
+		}
+	}
+}
+
+func streamEmbed(eventType string, d buildStreamData, orderID string, started bool) *discordgo.MessageEmbed {
+	name := d.SellerName
+	if name == "" {
+		name = "Your builder"
+	}
+	embed := &discordgo.MessageEmbed{
+		Color:  colorLive,
+		Fields: []*discordgo.MessageEmbedField{{Name: "🧾 Order", Value: "`" + orderID + "`"}},
+		Footer: &discordgo.MessageEmbedFooter{Text: footerText},
+	}
+	if eventType == eventBuildStreamOnline {
+		embed.Title = "🔴 " + name + " is live!"
+		embed.URL = d.StreamURL
+		embed.Description = name + " is streaming on [twitch.tv/" + d.TwitchLogin + "](" + d.StreamURL + ") right now, come watch your commission being built."
+		if started {
+			embed.Description = "Your commission just went into production! " + embed.Description
+		}
+		return embed
+	}
+	embed.Title = "📺 " + name + "'s stream has ended"
+	embed.Color = colorClosed
+	embed.Description = "Your commission is still in production. You'll get a message when it ships."
+	return embed
+}
+
+// orderProgressData is the payload of order.progress_update events.
+type orderProgressData struct {
+	OrderID          string   `json:"order_id"`
+	DropID           string   `json:"drop_id"`
+	BuyerDiscordID   string   `json:"buyer_discord_id"`
+	SellerDiscordID  string   `json:"seller_discord_id"`
+	SellerName       string   `json:"seller_name"`
+	ProductionStatus string   `json:"production_status"`
+	Note             string   `json:"note"`
+	PhotoURLs        []string `json:"photo_urls"`
+	OrderURL         string   `json:"order_url"`
+}
+
+// notifyProgressUpdate DMs the buyer the seller's update. The Core doesn't
+// publish updates for buyers who muted them.
+func notifyProgressUpdate(s *discordgo.Session, d orderProgressData) {
+	ch, err := s.UserChannelCreate(d.BuyerDiscordID)
+	if err == nil {
+		_, err = s.ChannelMessageSendEmbed(ch.ID, progressEmbed(d))
+	}
+	if err != nil {
+		log.Printf("builds: failed to DM buyer %s the update on order %s: %v", d.BuyerDiscordID, d.OrderID, err)
+	}
+}
+
+func progressEmbed(d orderProgressData) *discordgo.MessageEmbed {
+	name := d.SellerName
+	if name == "" {
+		name = "Your builder"
+	}
+	embed := &discordgo.MessageEmbed{
+		Title:       "🛠️ Build update from " + name,
+		URL:         d.OrderURL,
+		Description: truncate(d.Note, 2000),
+		Color:       colorProgress,
+		Fields: []*discordgo.MessageEmbedField{
+			{Name: "🧾 Order", Value: "`" + d.OrderID + "`", Inline: true},
+			{Name: "📍 Status", Value: strings.ReplaceAll(d.ProductionStatus, "_", " "), Inline: true},
+		},
+		Footer: &discordgo.MessageEmbedFooter{Text: "!order mute " + d.OrderID + " to stop these updates • " + footerText},
+	}
+	// Discord shows one image per embed; the rest are on the order page.
+	if len(d.PhotoURLs) > 0 {
+		embed.Image = &discordgo.MessageEmbedImage{URL: d.PhotoURLs[0]}
+	}
+	if len(d.PhotoURLs) > 1 && d.OrderURL != "" {
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+			Name:  "📸 More photos",
+			Value: "[See all " + strconv.Itoa(len(d.PhotoURLs)) + " photos on your order page](" + d.OrderURL + ")",
+		})
+	}
+	return embed
+}
@@ -0,0 +1,127 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// Drop posts carry a [Purchase Securely] button. Clicking it reserves the
+// drop in the Core and hands the buyer their own Stripe Checkout link,
+// privately. If someone else got there first, they can join the waitlist
+// and are DMed when the drop can be bought again.
+
+const (
+	// buyButtonPrefix matches the Python bot's buttons, so drops it posted keep working.
+	buyButtonPrefix      = "buy_btn"
+	waitlistButtonPrefix = "waitlist"
+
+	eventDropWaitlist = "drop.waitlist"
+)
+
+// waitlistData is the payload of drop.waitlist (see waitlist_service.go in the Core).
+type waitlistData struct {
+	DropID          string   `json:"drop_id"`
+	Title           string   `json:"title"`
+	PriceInCents    int64    `json:"price_in_cents"`
+	SellerDiscordID string   `json:"seller_discord_id"`
+	DiscordIDs      []string `json:"discord_ids"`
+}
+
+// buyButtonRow is the component row under a drop post.
+func buyButtonRow(dropID string) discordgo.ActionsRow {
+	return discordgo.ActionsRow{Components: []discordgo.MessageComponent{
+		discordgo.Button{
+			Label:    buyButtonLabel,
+			Style:    discordgo.SuccessButton,
+			Emoji:    &discordgo.ComponentEmoji{Name: "🔒"},
+			CustomID: componentID(buyButtonPrefix, dropID),
+		},
+	}}
+}
+
+// buyButtonHandler and waitlistButtonHandler are registered in main.go.
+func buyButtonHandler() *ComponentHandler {
+	return &ComponentHandler{Ephemeral: true, Defer: true, Handler: handleBuyClick}
+}
+
+func waitlistButtonHandler() *ComponentHandler {
+	return &ComponentHandler{Ephemeral: true, Defer: true, Handler: handleWaitlistClick}
+}
+
+func handleBuyClick(c *CommandContext) {
+	dropID := c.Payload
+	// The Core finds the builder from the drop.
+	if err := c.Core.TrackEvent(c.Ctx, "", dropID, "discord_clicks"); err != nil {
+		log.Printf("checkout: failed to count click on drop %s: %v", dropID, err)
+	}
+
+	checkoutURL, err := c.Core.CreateCheckoutSession(c.Ctx, dropID, c.UserID())
+	if err != nil {
+		var apiErr *APIError
+		switch {
+		case errors.As(err, &apiErr) && apiErr.Status == 409 && apiErr.Message == "Drop is not live yet":
+			c.ReplyWithButtons("⏰ This drop isn't live yet. Want a DM the moment it is?", waitlistButton(dropID, "Notify Me"))
+		case errors.As(err, &apiErr) && apiErr.Status == 409:
+			c.ReplyWithButtons("⏳ **Too late!** Someone else is checking out this drop right now. "+
+				"If they don't finish, it comes back: join the waitlist and I'll DM you.", waitlistButton(dropID, "Join Waitlist"))
+		case errors.As(err, &apiErr) && apiErr.Status == 404:
+			c.Reply("❌ This listing appears to be invalid or removed.")
+		default:
+			c.ReplyError(err, "Couldn't start checkout")
+		}
+		return
+	}
+
+	c.ReplyWithButtons("**Click below to complete your purchase securely on Stripe.**\n"+
+		"*The drop is held for you while you check out. The link is yours only, don't share it.*",
+		discordgo.Button{Label: "Proceed to Secure Checkout", Style: discordgo.LinkButton, URL: checkoutURL, Emoji: &discordgo.ComponentEmoji{Name: "👉"}},
+	)
+}
+
+func waitlistButton(dropID, label string) discordgo.Button {
+	return discordgo.Button{
+		Label:    label,
+		Style:    discordgo.PrimaryButton,
+		Emoji:    &discordgo.ComponentEmoji{Name: "🔔"},
+		CustomID: componentID(waitlistButtonPrefix, dropID),
+	}
+}
+
+func handleWaitlistClick(c *CommandContext) {
+	if err := c.Core.JoinWaitlist(c.Ctx, c.Payload, c.UserID()); err != nil {
+		c.ReplyError(err, "Couldn't add you to the waitlist")
+		return
+	}
+	c.Reply("🔔 You're on the waitlist. I'll DM you if this drop can be bought, first come first served. Make sure your DMs are open!")
+}
+
+// notifyWaitlist DMs everyone who waited for the drop, with a buy button.
+func notifyWaitlist(ctx context.Context, s *discordgo.Session, d waitlistData) {
+	msg := &discordgo.MessageSend{
+		Embeds: []*discordgo.MessageEmbed{{
+			Title:       "🔔 It's available: " + d.Title,
+			Description: fmt.Sprintf("The drop you waited for can be bought now for **$%.2f**. First come, first served!", float64(d.PriceInCents)/100),
+			Color:       colorPrimary,
+			Fields:      []*discordgo.MessageEmbedField{{Name: "🛠️ Builder", Value: "<@" + d.SellerDiscordID + ">"}},
+			Footer:      &discordgo.MessageEmbedFooter{Text: footerText},
+		}},
+		Components: []discordgo.MessageComponent{buyButtonRow(d.DropID)},
+	}
+	for _, id := range d.DiscordIDs {
+		if ctx.Err() != nil {
+			return
+		}
+		ch, err := s.UserChannelCreate(id)
+		if err == nil {
+			_, err = s.ChannelMessageSendComplex(ch.ID, msg)
+		}
+		if err != nil {
+			// Closed DMs; nothing else to try.
+			log.Printf("waitlist: couldn't DM %s about drop %s: %v", id, d.DropID, err)
+		}
+	}
+}
@@ -0,0 +1,451 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"os"
+	"time"
+)
+
+// CoreClient talks to the C500 Core API (c500-core-go).
+type CoreClient struct {
+	baseURL string
+	apiKey  string
+	http    *http.Client
+}
+
+// NewCoreClient reads CORE_API_URL, defaulting to the local dev server, and
+// INTERNAL_API_KEY, which the Core requires before it trusts the Discord IDs
+// we send (moderation, profile edits).
+func NewCoreClient() *CoreClient {
+	base := os.Getenv("CORE_API_URL")
+	if base == "" {
+		base = "http://localhost:8080/api/v1"
+	}
+	return &CoreClient{
+		baseURL: base,
+		apiKey:  os.Getenv("INTERNAL_API_KEY"),
+		http:    &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// CoreEvent mirrors events.Event in the Core.
+type CoreEvent struct {
+	ID        string          `json:"id"`
+	Type      string          `json:"type"`
+	Data      json.RawMessage `json:"data"`
+	CreatedAt time.Time       `json:"created_at"`
+}
+
+// ListEvents fetches events after the given ID, oldest first.
+func (c *CoreClient) ListEvents(ctx context.Context, after string, limit int) ([]CoreEvent, error) {
+	q := url.Values{}
+	q.Set("limit", fmt.Sprint(limit))
+	if after != "" {
+		q.Set("after", after)
+	}
+
+	var out struct {
+		Events []CoreEvent `json:"events"`
+	}
+	if err := c.do(ctx, http.MethodGet, "/events?"+q.Encode(), nil, &out); err != nil {
+		return nil, err
+	}
+	return out.Events, nil
+}
+
+// RecordDropPost tells the Core which message a drop was posted as in a guild,
+// so the embed can be edited when the drop's status changes.
+func (c *CoreClient) RecordDropPost(ctx context.Context, dropID, guildID, channelID, messageID string) error {
+	body := map[string]string{"guild_id": guildID, "channel_id": channelID, "message_id": messageID}
+	return c.do(ctx, http.MethodPost, "/drops/"+url.PathEscape(dropID)+"/posts", body, nil)
+}
+
+// TrackEvent counts a Discord-side analytics event ("discord_impressions" or
+// "discord_clicks") for a builder and, if dropID is set, one of their drops.
+func (c *CoreClient) TrackEvent(ctx context.Context, builderID, dropID, metric string) error {
+	body := map[string]string{"builder_id": builderID, "drop_id": dropID, "metric": metric}
+	return c.do(ctx, http.MethodPost, "/analytics/events", body, nil)
+}
+
+// TwitchLink is a builder's linked Twitch channel.
+type TwitchLink struct {
+	Twitch struct {
+		UserID string `json:"user_id"`
+		Login  string `json:"login"`
+	} `json:"twitch"`
+	ChannelURL string `json:"channel_url"`
+}
+
+// GetTwitchLink returns the builder's linked channel (404 APIError if none).
+func (c *CoreClient) GetTwitchLink(ctx context.Context, builderID string) (*TwitchLink, error) {
+	var out TwitchLink
+	if err := c.do(ctx, http.MethodGet, "/builders/"+url.PathEscape(builderID)+"/twitch", nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// TwitchLinkURL is a one-time Twitch login link, bound to one builder.
+type TwitchLinkURL struct {
+	URL       string    `json:"url"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// StartTwitchLink asks the Core for a Twitch login link to DM to the builder.
+func (c *CoreClient) StartTwitchLink(ctx context.Context, builderID string) (*TwitchLinkURL, error) {
+	var out TwitchLinkURL
+	if err := c.do(ctx, http.MethodPost, "/builders/"+url.PathEscape(builderID)+"/twitch/link", nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// UnlinkTwitch removes the builder's Twitch link.
+func (c *CoreClient) UnlinkTwitch(ctx context.Context, builderID string) error {
+	return c.do(ctx, http.MethodDelete, "/builders/"+url.PathEscape(builderID)+"/twitch", nil, nil)
+}
+
+// GuildSubscription mirrors the Core's PUT /guilds/:guildID/subscription body.
+type GuildSubscription struct {
+	ChannelID  string   `json:"channel_id"`
+	AllDrops   bool     `json:"all_drops"`
+	BuilderIDs []string `json:"builder_ids,omitempty"`
+	DropTypes  []string `json:"drop_types,omitempty"`
+	UpdatedBy  string   `json:"updated_by"`
+}
+
+// PutSubscription opts a guild in to the drop fan-out (or changes its filters).
+func (c *CoreClient) PutSubscription(ctx context.Context, guildID string, sub GuildSubscription) error {
+	return c.do(ctx, http.MethodPut, "/guilds/"+url.PathEscape(guildID)+"/subscription", sub, nil)
+}
+
+// DeleteSubscription opts a guild out.
+func (c *CoreClient) DeleteSubscription(ctx context.Context, guildID string) error {
+	return c.do(ctx, http.MethodDelete, "/guilds/"+url.PathEscape(guildID)+"/subscription", nil, nil)
+}
+
+// ResolveSlug turns a builder's username (or Discord ID) into their Discord ID.
+func (c *CoreClient) ResolveSlug(ctx context.Context, slug string) (string, error) {
+	var out struct {
+		BuilderID string `json:"builder_id"`
+	}
+	if err := c.do(ctx, http.MethodGet, "/slugs/"+url.PathEscape(slug), nil, &out); err != nil {
+		return "", err
+	}
+	return out.BuilderID, nil
+}
+
+// GuestbookEntry mirrors domain.GuestbookEntry in the Core.
+type GuestbookEntry struct {
+	ID               string    `json:"id"`
+	BuilderID        string    `json:"builder_id"`
+	AuthorDiscordID  string    `json:"author_discord_id"`
+	AuthorName       string    `json:"author_name"`
+	Message          string    `json:"message"`
+	Status           string    `json:"status"`
+	ReportCount      int       `json:"report_count"`
+	LastReportReason string    `json:"last_report_reason"`
+	CreatedAt        time.Time `json:"created_at"`
+}
+
+// SignGuestbook leaves a message on a builder's profile page.
+func (c *CoreClient) SignGuestbook(ctx context.Context, builderID, authorID, authorName, message string) error {
+	body := map[string]string{"author_discord_id": authorID, "author_name": authorName, "message": message}
+	return c.do(ctx, http.MethodPost, "/builders/"+url.PathEscape(builderID)+"/guestbook", body, nil)
+}
+
+// ListGuestbook returns a builder's entries; includeHidden is for the builder's own view.
+func (c *CoreClient) ListGuestbook(ctx context.Context, builderID string, includeHidden bool) ([]GuestbookEntry, error) {
+	path := "/builders/" + url.PathEscape(builderID) + "/guestbook?limit=20"
+	if includeHidden {
+		path = "/builders/" + url.PathEscape(builderID) + "/guestbook/all?limit=20"
+	}
+	var out struct {
+		Entries []GuestbookEntry `json:"entries"`
+	}
+	if err := c.do(ctx, http.MethodGet, path, nil, &out); err != nil {
+		return nil, err
+	}
+	return out.Entries, nil
+}
+
+// SetGuestbookEntryHidden hides or unhides an entry on the owner's page.
+func (c *CoreClient) SetGuestbookEntryHidden(ctx context.Context, entryID, ownerID string, hidden bool) error {
+	body := map[string]interface{}{"owner_discord_id": ownerID, "hidden": hidden}
+	return c.do(ctx, http.MethodPost, "/guestbook/"+url.PathEscape(entryID)+"/hide", body, nil)
+}
+
+// DeleteGuestbookEntry permanently removes an entry from the owner's page.
+func (c *CoreClient) DeleteGuestbookEntry(ctx context.Context, entryID, ownerID string) error {
+	body := map[string]string{"owner_discord_id": ownerID}
+	return c.do(ctx, http.MethodDelete, "/guestbook/"+url.PathEscape(entryID), body, nil)
+}
+
+// ReportGuestbookEntry sends an entry to the moderation queue.
+func (c *CoreClient) ReportGuestbookEntry(ctx context.Context, entryID, reporterID, reason string) error {
+	body := map[string]string{"reporter_discord_id": reporterID, "reason": reason}
+	return c.do(ctx, http.MethodPost, "/guestbook/"+url.PathEscape(entryID)+"/report", body, nil)
+}
+
+// GuestbookModerationQueue lists reported entries. The Core checks moderatorID.
+func (c *CoreClient) GuestbookModerationQueue(ctx context.Context, moderatorID string) ([]GuestbookEntry, error) {
+	var out struct {
+		Entries []GuestbookEntry `json:"entries"`
+	}
+	if err := c.do(ctx, http.MethodGet, "/moderation/guestbook?moderator_id="+url.QueryEscape(moderatorID), nil, &out); err != nil {
+		return nil, err
+	}
+	return out.Entries, nil
+}
+
+// ModerateGuestbookEntry approves ("approve") or takes down ("remove") a reported entry.
+func (c *CoreClient) ModerateGuestbookEntry(ctx context.Context, entryID, moderatorID, action string) error {
+	body := map[string]string{"moderator_discord_id": moderatorID, "action": action}
+	return c.do(ctx, http.MethodPost, "/moderation/guestbook/"+url.PathEscape(entryID), body, nil)
+}
+
+// VODReviewOrder is an order whose VOD link failed the Core's Twitch checks.
+type VODReviewOrder struct {
+	ID              string `json:"id"`
+	DropID          string `json:"drop_id"`
+	SellerDiscordID string `json:"seller_discord_id"`
+	BuyerDiscordID  string `json:"buyer_discord_id"`
+	PriceInCents    int64  `json:"price_in_cents"`
+	VODLink         string `json:"vod_link"`
+	VODReview       struct {
+		Reason      string    `json:"reason"`
+		SubmittedAt time.Time `json:"submitted_at"`
+	} `json:"vod_review"`
+}
+
+// VODReviewQueue lists orders waiting for a moderator. The Core checks moderatorID.
+func (c *CoreClient) VODReviewQueue(ctx context.Context, moderatorID string) ([]VODReviewOrder, error) {
+	var out struct {
+		Orders []VODReviewOrder `json:"orders"`
+	}
+	if err := c.do(ctx, http.MethodGet, "/moderation/vods?moderator_id="+url.QueryEscape(moderatorID), nil, &out); err != nil {
+		return nil, err
+	}
+	return out.Orders, nil
+}
+
+// ReviewVOD approves ("approve", releases escrow) or rejects ("reject") a queued VOD.
+func (c *CoreClient) ReviewVOD(ctx context.Context, orderID, moderatorID, action string) error {
+	body := map[string]string{"moderator_discord_id": moderatorID, "action": action}
+	return c.do(ctx, http.MethodPost, "/moderation/vods/"+url.PathEscape(orderID), body, nil)
+}
+
+// OrderStatus is the part of an order's timeline the bot shows a buyer.
+type OrderStatus struct {
+	OrderID          string `json:"order_id"`
+	ProductionStatus string `json:"production_status"`
+	UpdatesMuted     bool   `json:"updates_muted"`
+	OrderURL         string `json:"order_url"`
+}
+
+// GetOrderStatus fetches an order for its buyer or seller. The Core checks viewerID.
+func (c *CoreClient) GetOrderStatus(ctx context.Context, orderID, viewerID string) (*OrderStatus, error) {
+	var out OrderStatus
+	path := "/orders/" + url.PathEscape(orderID) + "/timeline?viewer_id=" + url.QueryEscape(viewerID)
+	if err := c.do(ctx, http.MethodGet, path, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// SetOrderUpdatesMuted turns progress update DMs off (or back on) for the buyer.
+func (c *CoreClient) SetOrderUpdatesMuted(ctx context.Context, orderID, buyerID string, muted bool) error {
+	body := map[string]interface{}{"buyer_discord_id": buyerID, "muted": muted}
+	return c.do(ctx, http.MethodPut, "/orders/"+url.PathEscape(orderID)+"/updates/muted", body, nil)
+}
+
+// CreateCheckoutSession reserves the drop for the buyer and returns the
+// Stripe Checkout URL. It's a 409 APIError if the drop is reserved, sold or
+// not live yet.
+func (c *CoreClient) CreateCheckoutSession(ctx context.Context, dropID, buyerID string) (string, error) {
+	var out struct {
+		URL string `json:"url"`
+	}
+	body := map[string]string{"drop_id": dropID, "buyer_discord_id": buyerID}
+	if err := c.do(ctx, http.MethodPost, "/checkout/session", body, &out); err != nil {
+		return "", err
+	}
+	return out.URL, nil
+}
+
+// JoinWaitlist asks the Core to DM the buyer when the drop can be bought.
+func (c *CoreClient) JoinWaitlist(ctx context.Context, dropID, discordID string) error {
+	body := map[string]string{"discord_id": discordID}
+	return c.do(ctx, http.MethodPost, "/drops/"+url.PathEscape(dropID)+"/waitlist", body, nil)
+}
+
+// GetStripeOnboardingLink returns a one-time Stripe onboarding URL for the
+// seller, creating their builder record first if needed. It's a 409 APIError
+// if their Stripe account is already connected.
+func (c *CoreClient) GetStripeOnboardingLink(ctx context.Context, discordID, displayName string) (string, error) {
+	var out struct {
+		URL string `json:"url"`
+	}
+	body := map[string]string{"display_name": displayName}
+	if err := c.do(ctx, http.MethodPost, "/builders/"+url.PathEscape(discordID)+"/stripe/onboarding", body, &out); err != nil {
+		return "", err
+	}
+	return out.URL, nil
+}
+
+// UploadImage stores an image for the seller and returns its asset ID, to use
+// in a drop's image_asset_ids.
+func (c *CoreClient) UploadImage(ctx context.Context, ownerID, filename string, data []byte) (string, error) {
+	var buf bytes.Buffer
+	form := multipart.NewWriter(&buf)
+	form.WriteField("owner_discord_id", ownerID)
+	part, err := form.CreateFormFile("file", filename)
+	if err != nil {
+		return "", err
+	}
+	part.Write(data)
+	if err := form.Close(); err != nil {
+		return "", err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+"/assets/images", &buf)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", form.FormDataContentType())
+
+	var out struct {
+		ID string `json:"id"`
+	}
+	if err := c.send(req, &out); err != nil {
+		return "", err
+	}
+	return out.ID, nil
+}
+
+// CreateDropRequest mirrors domain.CreateDropRequest in the Core.
+type CreateDropRequest struct {
+	SellerDiscordID string     `json:"seller_discord_id"`
+	Title           string     `json:"title"`
+	PriceInCents    int64      `json:"price_in_cents"`
+	Type            string     `json:"type"`
+	Description     string     `json:"description,omitempty"`
+	ImageAssetIDs   []string   `json:"image_asset_ids"`
+	Spec            DropSpec   `json:"spec"`
+	GoLiveAt        *time.Time `json:"go_live_at,omitempty"`
+}
+
+// DropSpec mirrors domain.DropSpec: Kind plus the one matching sub-spec.
+// The Core validates and canonicalizes the values.
+type DropSpec struct {
+	Kind       string                 `json:"kind"`
+	Keyboard   map[string]string      `json:"keyboard,omitempty"`
+	KeycapSet  map[string]interface{} `json:"keycap_set,omitempty"`
+	SwitchPack map[string]interface{} `json:"switch_pack,omitempty"`
+}
+
+// CreatedDrop is the part of the Core's drop response the bot shows.
+type CreatedDrop struct {
+	ID       string     `json:"id"`
+	Title    string     `json:"title"`
+	Status   string     `json:"status"`
+	GoLiveAt *time.Time `json:"go_live_at"`
+}
+
+// CreateDrop lists a new drop for the seller.
+func (c *CoreClient) CreateDrop(ctx context.Context, req CreateDropRequest) (*CreatedDrop, error) {
+	var out CreatedDrop
+	if err := c.do(ctx, http.MethodPost, "/drops", req, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// FulfillmentResult is the Core's answer to a fulfillment: "success" (funds
+// released), "shipped" (commission still needs its VOD) or "in_review".
+type FulfillmentResult struct {
+	Status  string `json:"status"`
+	Message string `json:"message"`
+}
+
+// FulfillShip marks the seller's order shipped with a carrier tracking number.
+func (c *CoreClient) FulfillShip(ctx context.Context, orderID, sellerID, carrier, trackingNumber string) (*FulfillmentResult, error) {
+	var out FulfillmentResult
+	body := map[string]string{"seller_discord_id": sellerID, "carrier": carrier, "tracking_number": trackingNumber}
+	if err := c.do(ctx, http.MethodPost, "/orders/"+url.PathEscape(orderID)+"/fulfill/ship", body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// FulfillLive submits the Twitch VOD of a commission's build.
+func (c *CoreClient) FulfillLive(ctx context.Context, orderID, sellerID, vodURL string) (*FulfillmentResult, error) {
+	var out FulfillmentResult
+	body := map[string]string{"seller_discord_id": sellerID, "vod_url": vodURL}
+	if err := c.do(ctx, http.MethodPost, "/orders/"+url.PathEscape(orderID)+"/fulfill/live", body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// APIError is returned for any non-2xx response.
+type APIError struct {
+	Status  int
+	Message string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("core api %d: %s", e.Status, e.Message)
+}
+
+// do sends a JSON request and decodes a JSON response into out (if non-nil).
+func (c *CoreClient) do(ctx context.Context, method, path string, in, out interface{}) error {
+	var body *bytes.Reader
+	if in != nil {
+		raw, err := json.Marshal(in)
+		if err != nil {
+			return err
+		}
+		body = bytes.NewReader(raw)
+	} else {
+		body = bytes.NewReader(nil)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, body)
+	if err != nil {
+		return err
+	}
+	if in != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	return c.send(req, out)
+}
+
+// send runs the request and decodes the JSON response (or error) into out.
+func (c *CoreClient) send(req *http.Request, out interface{}) error {
+	req.Header.Set("X-Internal-API-Key", c.apiKey)
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		var apiErr struct {
+			Error string `json:"error"`
+		}
+		json.NewDecoder(resp.Body).Decode(&apiErr)
+		return &APIError{Status: resp.StatusCode, Message: apiErr.Error}
+	}
+	if out == nil || resp.StatusCode == http.StatusNoContent {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
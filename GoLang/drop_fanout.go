@@ -0,0 +1,246 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// Event types published by the Core (internal/events). Keep in sync.
+const (
+	eventDropPublished     = "drop.published"
+	eventDropGoingLiveSoon = "drop.going_live_soon"
+	eventDropLive          = "drop.live"
+)
+
+// C500 brand colors (same palette as the Python bot's embeds.py).
+const (
+	colorPrimary = 0xFFB7C5 // Sakura Pink
+	colorGold    = 0xFDFD96 // Pastel Yellow
+)
+
+const footerText = "C500 Collective • Cozy Builds & Community"
+
+// fanoutTarget is one subscribed guild's marketplace channel.
+type fanoutTarget struct {
+	GuildID   string `json:"guild_id"`
+	ChannelID string `json:"channel_id"`
+}
+
+// dropEventData is the payload of every drop.* event (see fanout_service.go in the Core).
+type dropEventData struct {
+	DropID          string `json:"drop_id"`
+	Title           string `json:"title"`
+	Description     string `json:"description"`
+	SellerDiscordID string `json:"seller_discord_id"`
+	// SellerTwitchLogin is the seller's verified Twitch channel, "" if not linked.
+	SellerTwitchLogin string     `json:"seller_twitch_login"`
+	PriceInCents      int64      `json:"price_in_cents"`
+	Type              string     `json:"type"`
+	ImageURLs         []string   `json:"image_urls"`
+	GoLiveAt          *time.Time `json:"go_live_at"`
+	SpecDisplay       []struct {
+		Label string `json:"label"`
+		Value string `json:"value"`
+	} `json:"spec_display"`
+	Targets []fanoutTarget `json:"targets"`
+}
+
+// DropFanout polls the Core's event outbox, posts each drop into every
+// subscribed guild's channel, hands status changes to the embed sync worker
+// and DMs buyers when their builder goes live.
+type DropFanout struct {
+	session    *discordgo.Session
+	core       *CoreClient
+	sync       *EmbedSyncWorker
+	interval   time.Duration
+	cursorFile string
+	cursor     string
+}
+
+// NewDropFanout constructor. The cursor (last handled event ID) is kept in a
+// file so a restart doesn't repost everything.
+func NewDropFanout(s *discordgo.Session, core *CoreClient, sync *EmbedSyncWorker) *DropFanout {
+	cursorFile := os.Getenv("EVENT_CURSOR_FILE")
+	if cursorFile == "" {
+		cursorFile = ".event_cursor"
+	}
+	f := &DropFanout{
+		session:    s,
+		core:       core,
+		sync:       sync,
+		interval:   5 * time.Second,
+		cursorFile: cursorFile,
+	}
+	if raw, err := os.ReadFile(cursorFile); err == nil {
+		f.cursor = strings.TrimSpace(string(raw))
+	}
+	return f
+}
+
+// Run polls until ctx is cancelled. Start it in a goroutine from main().
+func (f *DropFanout) Run(ctx context.Context) {
+	ticker := time.NewTicker(f.interval)
+	defer ticker.Stop()
+
+	for {
+		f.poll(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (f *DropFanout) poll(ctx context.Context) {
+	evs, err := f.core.ListEvents(ctx, f.cursor, 100)
+	if err != nil {
+		log.Printf("fanout: failed to poll events: %v", err)
+		return
+	}
+
+	for _, ev := range evs {
+		f.handle(ctx, ev)
+		// Advance past the event even if a post failed: a single broken guild
+		// (deleted channel, missing permission) must not block everyone else.
+		f.cursor = ev.ID
+	}
+	if len(evs) > 0 {
+		if err := os.WriteFile(f.cursorFile, []byte(f.cursor), 0o644); err != nil {
+			log.Printf("fanout: failed to save cursor: %v", err)
+		}
+	}
+}
+
+func (f *DropFanout) handle(ctx context.Context, ev CoreEvent) {
+	switch ev.Type {
+	case eventDropPublished, eventDropLive, eventDropGoingLiveSoon:
+	case eventDropStatusChanged:
+		var data statusChangedData
+		if err := json.Unmarshal(ev.Data, &data); err != nil {
+			log.Printf("fanout: bad payload for event %s: %v", ev.ID, err)
+			return
+		}
+		f.sync.Enqueue(data)
+		return
+	case eventBuildStreamOnline, eventBuildStreamOffline:
+		var data buildStreamData
+		if err := json.Unmarshal(ev.Data, &data); err != nil {
+			log.Printf("fanout: bad payload for event %s: %v", ev.ID, err)
+			return
+		}
+		notifyBuyers(f.session, ev.Type, data)
+		return
+	case eventDropWaitlist:
+		var data waitlistData
+		if err := json.Unmarshal(ev.Data, &data); err != nil {
+			log.Printf("fanout: bad payload for event %s: %v", ev.ID, err)
+			return
+		}
+		notifyWaitlist(ctx, f.session, data)
+		return
+	case eventOrderProgressUpdate:
+		var data orderProgressData
+		if err := json.Unmarshal(ev.Data, &data); err != nil {
+			log.Printf("fanout: bad payload for event %s: %v", ev.ID, err)
+			return
+		}
+		notifyProgressUpdate(f.session, data)
+		return
+	default:
+		return // Not ours.
+	}
+
+	var data dropEventData
+	if err := json.Unmarshal(ev.Data, &data); err != nil {
+		log.Printf("fanout: bad payload for event %s: %v", ev.ID, err)
+		return
+	}
+
+	for _, t := range data.Targets {
+		if ev.Type == eventDropGoingLiveSoon {
+			if _, err := f.session.ChannelMessageSendEmbed(t.ChannelID, countdownEmbed(data)); err != nil {
+				log.Printf("fanout: countdown for drop %s to guild %s failed: %v", data.DropID, t.GuildID, err)
+			}
+			continue
+		}
+
+		msg, err := f.session.ChannelMessageSendComplex(t.ChannelID, &discordgo.MessageSend{
+			Embeds:     []*discordgo.MessageEmbed{dropEmbed(data)},
+			Components: []discordgo.MessageComponent{buyButtonRow(data.DropID)},
+		})
+		if err != nil {
+			log.Printf("fanout: post of drop %s to guild %s failed: %v", data.DropID, t.GuildID, err)
+			continue
+		}
+		if err := f.core.RecordDropPost(ctx, data.DropID, t.GuildID, t.ChannelID, msg.ID); err != nil {
+			log.Printf("fanout: failed to record post of drop %s in guild %s: %v", data.DropID, t.GuildID, err)
+		}
+		if err := f.core.TrackEvent(ctx, data.SellerDiscordID, data.DropID, "discord_impressions"); err != nil {
+			log.Printf("fanout: failed to count impression of drop %s: %v", data.DropID, err)
+		}
+	}
+}
+
+// dropEmbed is the Go port of create_marketplace_drop_embed in the Python bot.
+func dropEmbed(d dropEventData) *discordgo.MessageEmbed {
+	typeEmoji, typeLabel := "📦", "Ready-to-Ship"
+	if d.Type == "commission" {
+		typeEmoji, typeLabel = "🎨", "Commission Slot"
+	}
+
+	fields := []*discordgo.MessageEmbedField{
+		{Name: "🏷️ Price", Value: fmt.Sprintf("**$%.2f**", float64(d.PriceInCents)/100), Inline: true},
+		{Name: typeEmoji + " Type", Value: typeLabel, Inline: true},
+	}
+	for _, s := range d.SpecDisplay {
+		fields = append(fields, &discordgo.MessageEmbedField{Name: s.Label, Value: s.Value, Inline: true})
+	}
+	fields = append(fields, &discordgo.MessageEmbedField{Name: "🛠️ Builder", Value: "<@" + d.SellerDiscordID + ">"})
+	if d.SellerTwitchLogin != "" {
+		fields = append(fields, &discordgo.MessageEmbedField{
+			Name:  "📺 Builds Live On",
+			Value: "[twitch.tv/" + d.SellerTwitchLogin + "](https://www.twitch.tv/" + d.SellerTwitchLogin + ")",
+		})
+	}
+	fields = append(fields, &discordgo.MessageEmbedField{Name: "🆔 Drop ID", Value: "`" + d.DropID + "`"})
+
+	embed := &discordgo.MessageEmbed{
+		Title:       "✨ New Drop: " + d.Title,
+		Description: d.Description,
+		Color:       colorPrimary,
+		Timestamp:   time.Now().UTC().Format(time.RFC3339),
+		Fields:      fields,
+		Footer:      &discordgo.MessageEmbedFooter{Text: footerText},
+	}
+	if len(d.ImageURLs) > 0 && strings.HasPrefix(d.ImageURLs[0], "http") {
+		embed.Image = &discordgo.MessageEmbedImage{URL: d.ImageURLs[0]}
+	}
+	return embed
+}
+
+// countdownEmbed is the "dropping in 1 hour" teaser.
+func countdownEmbed(d dropEventData) *discordgo.MessageEmbed {
+	desc := "Dropping soon!"
+	if d.GoLiveAt != nil {
+		// Discord renders <t:unix:R> as a live relative countdown in each viewer's timezone.
+		desc = fmt.Sprintf("Goes live <t:%d:R> (<t:%d:t>)", d.GoLiveAt.Unix(), d.GoLiveAt.Unix())
+	}
+	embed := &discordgo.MessageEmbed{
+		Title:       "⏰ Coming Up: " + d.Title,
+		Description: desc,
+		Color:       colorGold,
+		Footer:      &discordgo.MessageEmbedFooter{Text: footerText},
+	}
+	if len(d.ImageURLs) > 0 && strings.HasPrefix(d.ImageURLs[0], "http") {
+		embed.Thumbnail = &discordgo.MessageEmbedThumbnail{URL: d.ImageURLs[0]}
+	}
+	return embed
+}
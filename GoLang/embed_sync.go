@@ -0,0 +1,309 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+const eventDropStatusChanged = "drop.status_changed"
+
+// Drop statuses (domain.DropStatus in the Core).
+const (
+	dropStatusAvailable = "available"
+	dropStatusPending   = "pending"
+	dropStatusSold      = "sold"
+	dropStatusArchived  = "archived"
+)
+
+const (
+	colorReserved = 0xFDFD96 // Pastel Yellow
+	colorClosed   = 0x99AAB5 // Discord grey
+)
+
+// buyButtonLabel is the label of the purchase button while a drop can be bought.
+const buyButtonLabel = "Purchase Securely"
+
+// Edit pacing. Discord allows roughly 5 message edits per 5s per channel and
+// 50 requests/s per bot overall; we stay well under both and still honour any
+// 429 Discord sends back.
+const (
+	perChannelGap  = 1100 * time.Millisecond
+	globalGap      = 100 * time.Millisecond
+	maxEditRetries = 5
+)
+
+// statusChangedData is the payload of drop.status_changed (see fanout_service.go in the Core).
+type statusChangedData struct {
+	DropID   string `json:"drop_id"`
+	Status   string `json:"status"`
+	Messages []struct {
+		GuildID   string `json:"guild_id"`
+		ChannelID string `json:"channel_id"`
+		MessageID string `json:"message_id"`
+	} `json:"messages"`
+}
+
+// editJob is one message that must be brought in line with its drop's status.
+type editJob struct {
+	channelID string
+	messageID string
+	status    string
+	attempts  int
+	notBefore time.Time
+}
+
+// EmbedSyncWorker edits posted drop embeds when a drop's status changes.
+//
+// Jobs are keyed by message, so if a drop flips pending -> available -> pending
+// while a channel is rate limited, only the latest status is ever sent.
+type EmbedSyncWorker struct {
+	session *discordgo.Session
+
+	mu          sync.Mutex
+	queue       []string            // message keys, FIFO
+	jobs        map[string]*editJob // latest job per message key
+	lastChannel map[string]time.Time
+	wake        chan struct{}
+}
+
+// NewEmbedSyncWorker constructor. Start it with Run.
+func NewEmbedSyncWorker(s *discordgo.Session) *EmbedSyncWorker {
+	return &EmbedSyncWorker{
+		session:     s,
+		jobs:        make(map[string]*editJob),
+		lastChannel: make(map[string]time.Time),
+		wake:        make(chan struct{}, 1),
+	}
+}
+
+// Enqueue schedules an edit for every message in a drop.status_changed event.
+func (w *EmbedSyncWorker) Enqueue(data statusChangedData) {
+	w.mu.Lock()
+	for _, m := range data.Messages {
+		key := m.ChannelID + "/" + m.MessageID
+		if job, ok := w.jobs[key]; ok {
+			job.status = data.Status // Coalesce: newest status wins.
+			job.attempts = 0
+			continue
+		}
+		w.jobs[key] = &editJob{channelID: m.ChannelID, messageID: m.MessageID, status: data.Status}
+		w.queue = append(w.queue, key)
+	}
+	w.mu.Unlock()
+
+	select {
+	case w.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Run processes edits until ctx is cancelled.
+func (w *EmbedSyncWorker) Run(ctx context.Context) {
+	for {
+		job, wait := w.next()
+		if job == nil {
+			select {
+			case <-ctx.Done():
+				return
+			case <-w.wake:
+			case <-time.After(wait):
+			}
+			continue
+		}
+
+		w.edit(job)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(globalGap):
+		}
+	}
+}
+
+// next pops the first job that is allowed to run now. If none is, it returns
+// how long to sleep before checking again.
+func (w *EmbedSyncWorker) next() (*editJob, time.Duration) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	now := time.Now()
+	wait := time.Minute
+	for i, key := range w.queue {
+		job := w.jobs[key]
+		ready := job.notBefore
+		if last, ok := w.lastChannel[job.channelID]; ok && last.Add(perChannelGap).After(ready) {
+			ready = last.Add(perChannelGap)
+		}
+		if ready.After(now) {
+			if d := ready.Sub(now); d < wait {
+				wait = d
+			}
+			continue
+		}
+
+		w.queue = append(w.queue[:i], w.queue[i+1:]...)
+		delete(w.jobs, key)
+		w.lastChannel[job.channelID] = now
+		return job, 0
+	}
+	return nil, wait
+}
+
+// retry puts a failed job back, unless a newer status for the same message arrived meanwhile.
+func (w *EmbedSyncWorker) retry(job *editJob, after time.Duration) {
+	job.attempts++
+	if job.attempts > maxEditRetries {
+		log.Printf("embed sync: giving up on message %s in %s after %d attempts", job.messageID, job.channelID, job.attempts)
+		return
+	}
+	job.notBefore = time.Now().Add(after)
+
+	w.mu.Lock()
+	key := job.channelID + "/" + job.messageID
+	if _, exists := w.jobs[key]; !exists {
+		w.jobs[key] = job
+		w.queue = append(w.queue, key)
+	}
+	w.mu.Unlock()
+}
+
+func (w *EmbedSyncWorker) edit(job *editJob) {
+	msg, err := w.session.ChannelMessage(job.channelID, job.messageID)
+	if err == nil {
+		edit := restyleDropMessage(msg, job.status)
+		_, err = w.session.ChannelMessageEditComplex(edit)
+	}
+	if err == nil {
+		return
+	}
+
+	var restErr *discordgo.RESTError
+	if errors.As(err, &restErr) && restErr.Response != nil {
+		switch code := restErr.Response.StatusCode; {
+		case code == http.StatusTooManyRequests:
+			w.retry(job, retryAfter(restErr.Response))
+			return
+		case code == http.StatusNotFound, code == http.StatusForbidden:
+			// Message deleted or we lost access to the channel: nothing to fix.
+			return
+		case code >= 500:
+			w.retry(job, time.Duration(job.attempts+1)*5*time.Second)
+			return
+		}
+	}
+	log.Printf("embed sync: failed to edit message %s in %s: %v", job.messageID, job.channelID, err)
+}
+
+// retryAfter reads Discord's Retry-After header (seconds, may be fractional).
+func retryAfter(resp *http.Response) time.Duration {
+	if secs, err := strconv.ParseFloat(resp.Header.Get("Retry-After"), 64); err == nil && secs > 0 {
+		return time.Duration(secs * float64(time.Second))
+	}
+	return 5 * time.Second
+}
+
+// ==========================================
+// Restyling
+// ==========================================
+
+// Stamps put in front of the embed title. Only one is shown at a time.
+var statusStamps = map[string]string{
+	dropStatusPending:  "🔒 Reserved — ",
+	dropStatusSold:     "🚫 SOLD — ",
+	dropStatusArchived: "📦 No longer available — ",
+}
+
+// restyleDropMessage returns the edit that makes msg reflect status.
+func restyleDropMessage(msg *discordgo.Message, status string) *discordgo.MessageEdit {
+	embeds := make([]*discordgo.MessageEmbed, 0, len(msg.Embeds))
+	for i, e := range msg.Embeds {
+		if i == 0 {
+			title := e.Title
+			for _, stamp := range statusStamps {
+				title = strings.TrimPrefix(title, stamp)
+			}
+			e.Title = statusStamps[status] + title
+
+			switch status {
+			case dropStatusAvailable:
+				e.Color = colorPrimary
+			case dropStatusPending:
+				e.Color = colorReserved
+			default:
+				e.Color = colorClosed
+			}
+		}
+		embeds = append(embeds, e)
+	}
+
+	components := make([]discordgo.MessageComponent, 0, len(msg.Components))
+	for _, c := range msg.Components {
+		row, ok := asActionsRow(c)
+		if !ok {
+			components = append(components, c)
+			continue
+		}
+		for j, inner := range row.Components {
+			btn, ok := asButton(inner)
+			if !ok || btn.CustomID == "" {
+				continue // Link buttons (e.g. "View on site") stay as they are.
+			}
+			// A reserved drop can come back if the checkout expires, so its
+			// button offers the waitlist instead of going dead.
+			_, dropID, _ := strings.Cut(btn.CustomID, ":")
+			btn.Disabled = status != dropStatusAvailable && status != dropStatusPending
+			switch status {
+			case dropStatusAvailable:
+				btn.Label = buyButtonLabel
+				btn.CustomID = componentID(buyButtonPrefix, dropID)
+			case dropStatusPending:
+				btn.Label = "Reserved · Join Waitlist"
+				btn.CustomID = componentID(waitlistButtonPrefix, dropID)
+			case dropStatusSold:
+				btn.Label = "Sold"
+			default:
+				btn.Label = "Unavailable"
+			}
+			row.Components[j] = btn
+		}
+		components = append(components, row)
+	}
+
+	return &discordgo.MessageEdit{
+		ID:         msg.ID,
+		Channel:    msg.ChannelID,
+		Embeds:     &embeds,
+		Components: &components,
+	}
+}
+
+// Components decoded from the API come back as pointers, components we built
+// ourselves as values. Accept both.
+func asActionsRow(c discordgo.MessageComponent) (discordgo.ActionsRow, bool) {
+	switch v := c.(type) {
+	case discordgo.ActionsRow:
+		return v, true
+	case *discordgo.ActionsRow:
+		return *v, true
+	}
+	return discordgo.ActionsRow{}, false
+}
+
+func asButton(c discordgo.MessageComponent) (discordgo.Button, bool) {
+	switch v := c.(type) {
+	case discordgo.Button:
+		return v, true
+	case *discordgo.Button:
+		return *v, true
+	}
+	return discordgo.Button{}, false
+}
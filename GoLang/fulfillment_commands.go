@@ -0,0 +1,124 @@
+package main
+
+import (
+	"errors"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// /fulfill is how sellers deliver an order and get paid:
+//
+//	/fulfill ship order_id carrier tracking_number
+//	/fulfill live order_id vod_url     (commissions: the Twitch VOD of the build)
+//
+// The Core checks the seller owns the order, validates the tracking number
+// and verifies the VOD; its messages are passed on as they are written.
+
+// shippingCarriers mirrors the Core's carrier registry (domain/carrier.go).
+var shippingCarriers = []*discordgo.ApplicationCommandOptionChoice{
+	{Name: "USPS", Value: "usps"},
+	{Name: "UPS", Value: "ups"},
+	{Name: "FedEx", Value: "fedex"},
+	{Name: "DHL Express", Value: "dhl"},
+	{Name: "Canada Post", Value: "canada_post"},
+	{Name: "Royal Mail", Value: "royal_mail"},
+}
+
+func fulfillSlashCommand() *SlashCommand {
+	orderIDOption := &discordgo.ApplicationCommandOption{
+		Type:        discordgo.ApplicationCommandOptionString,
+		Name:        "order_id",
+		Description: "The order ID from your sale notification",
+		Required:    true,
+	}
+
+	return &SlashCommand{
+		Name:        "fulfill",
+		Description: "Deliver an order you sold",
+		Ephemeral:   true,
+		Defer:       true,
+		Subcommands: []*Subcommand{
+			{
+				Name:        "ship",
+				Description: "Mark an order shipped with its tracking number",
+				Options: []*discordgo.ApplicationCommandOption{
+					orderIDOption,
+					{
+						Type:        discordgo.ApplicationCommandOptionString,
+						Name:        "carrier",
+						Description: "Who is delivering it",
+						Required:    true,
+						Choices:     shippingCarriers,
+					},
+					{
+						Type:        discordgo.ApplicationCommandOptionString,
+						Name:        "tracking_number",
+						Description: "As printed on the label",
+						Required:    true,
+					},
+				},
+				Handler: func(c *CommandContext) {
+					orderID := c.String("order_id")
+					result, err := c.Core.FulfillShip(c.Ctx, orderID, c.UserID(), c.String("carrier"), c.String("tracking_number"))
+					if err != nil {
+						replyFulfillmentError(c, orderID, err)
+						return
+					}
+					if result.Status == "shipped" {
+						c.Reply("📦 " + result.Message + " Use `/fulfill live` once your build VOD is up.")
+						return
+					}
+					c.Reply("✅ " + result.Message)
+				},
+			},
+			{
+				Name:        "live",
+				Description: "Submit the Twitch VOD of a commission's build",
+				Options: []*discordgo.ApplicationCommandOption{
+					orderIDOption,
+					{
+						Type:        discordgo.ApplicationCommandOptionString,
+						Name:        "vod_url",
+						Description: "Link to the VOD on your Twitch channel",
+						Required:    true,
+					},
+				},
+				Handler: func(c *CommandContext) {
+					orderID := c.String("order_id")
+					result, err := c.Core.FulfillLive(c.Ctx, orderID, c.UserID(), c.String("vod_url"))
+					if err != nil {
+						replyFulfillmentError(c, orderID, err)
+						return
+					}
+					if result.Status == "in_review" {
+						c.Reply("🔎 " + result.Message)
+						return
+					}
+					c.Reply("✅ " + result.Message)
+				},
+			},
+		},
+	}
+}
+
+// replyFulfillmentError explains the Core's refusals in terms of what the
+// seller typed.
+func replyFulfillmentError(c *CommandContext, orderID string, err error) {
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		c.ReplyError(err, "Couldn't fulfill that order")
+		return
+	}
+	switch apiErr.Status {
+	case 404:
+		c.Reply("❌ Order `" + orderID + "` wasn't found. Check the ID in your sale notification.")
+	case 403:
+		c.Reply("❌ Order `" + orderID + "` isn't one of your sales, so you can't fulfill it.")
+	case 400:
+		c.Reply("❌ " + apiErr.Message)
+	case 409:
+		c.Reply("⏳ " + apiErr.Message)
+	default:
+		c.ReplyError(err, "Couldn't fulfill that order")
+	}
+}
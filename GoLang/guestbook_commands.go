@@ -0,0 +1,196 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// handleGuestbookCommand handles guestbooks on c500.store builder pages.
+// Signing happens here (not on the website) so every entry is tied to a
+// Discord account:
+//
+//	!guestbook sign <@builder|username> <message>
+//	!guestbook report <entry id> [reason]
+//
+// Builders manage their own guestbook:
+//
+//	!guestbook list
+//	!guestbook hide|unhide|delete <entry id>
+//
+// C500 moderators (MODERATOR_DISCORD_IDS in the Core) work the report queue:
+//
+//	!guestbook queue
+//	!guestbook approve|remove <entry id>
+func handleGuestbookCommand(s *discordgo.Session, m *discordgo.MessageCreate, core *CoreClient) {
+	args := strings.Fields(m.Content)
+	if len(args) < 2 {
+		s.ChannelMessageSend(m.ChannelID, "Usage: `!guestbook sign <@builder|username> <message>`, `list`, `hide|unhide|delete|report <id>`")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	switch sub := strings.ToLower(args[1]); sub {
+	case "sign":
+		if len(args) < 4 {
+			s.ChannelMessageSend(m.ChannelID, "Usage: `!guestbook sign <@builder|username> <message>`")
+			return
+		}
+		builderID, err := resolveBuilder(ctx, core, m, args[2])
+		if err != nil {
+			s.ChannelMessageSend(m.ChannelID, "❌ I couldn't find a builder called `"+args[2]+"`.")
+			return
+		}
+		message := restAfterArgs(m.Content, 3)
+
+		name := m.Author.Username
+		if m.Member != nil && m.Member.Nick != "" {
+			name = m.Member.Nick
+		}
+		if err := core.SignGuestbook(ctx, builderID, m.Author.ID, name, message); err != nil {
+			replyGuestbookError(s, m, err, "Couldn't sign the guestbook")
+			return
+		}
+		// Delete the command so the message isn't duplicated in chat; the entry lives on the site.
+		s.ChannelMessageDelete(m.ChannelID, m.ID)
+		s.ChannelMessageSend(m.ChannelID, "📖 <@"+m.Author.ID+"> signed <@"+builderID+">'s guestbook!")
+
+	case "list":
+		entries, err := core.ListGuestbook(ctx, m.Author.ID, true)
+		if err != nil {
+			replyGuestbookError(s, m, err, "Couldn't load your guestbook")
+			return
+		}
+		if len(entries) == 0 {
+			s.ChannelMessageSend(m.ChannelID, "Your guestbook is empty so far.")
+			return
+		}
+		var b strings.Builder
+		for _, e := range entries {
+			flag := ""
+			if e.Status == "hidden" {
+				flag = " *(hidden)*"
+			}
+			fmt.Fprintf(&b, "`%s` **%s**%s: %s\n", e.ID, e.AuthorName, flag, truncate(e.Message, 120))
+		}
+		sendPrivately(s, m, b.String())
+
+	case "hide", "unhide", "delete", "report":
+		if len(args) < 3 {
+			s.ChannelMessageSend(m.ChannelID, "Usage: `!guestbook "+sub+" <entry id>`")
+			return
+		}
+		entryID := args[2]
+		var err error
+		switch sub {
+		case "hide", "unhide":
+			err = core.SetGuestbookEntryHidden(ctx, entryID, m.Author.ID, sub == "hide")
+		case "delete":
+			err = core.DeleteGuestbookEntry(ctx, entryID, m.Author.ID)
+		case "report":
+			err = core.ReportGuestbookEntry(ctx, entryID, m.Author.ID, strings.Join(args[3:], " "))
+		}
+		if err != nil {
+			replyGuestbookError(s, m, err, "Couldn't update that entry")
+			return
+		}
+		if sub == "report" {
+			s.ChannelMessageSend(m.ChannelID, "🚩 Thanks, a moderator will take a look.")
+			return
+		}
+		s.ChannelMessageSend(m.ChannelID, "✅ Done.")
+
+	case "queue":
+		entries, err := core.GuestbookModerationQueue(ctx, m.Author.ID)
+		if err != nil {
+			replyGuestbookError(s, m, err, "Couldn't load the moderation queue")
+			return
+		}
+		if len(entries) == 0 {
+			sendPrivately(s, m, "The guestbook moderation queue is empty. 🎉")
+			return
+		}
+		var b strings.Builder
+		for _, e := range entries {
+			fmt.Fprintf(&b, "`%s` on <@%s>'s page by **%s** (%d reports, last: %s)\n> %s\n",
+				e.ID, e.BuilderID, e.AuthorName, e.ReportCount, e.LastReportReason, truncate(e.Message, 200))
+		}
+		sendPrivately(s, m, b.String())
+
+	case "approve", "remove":
+		if len(args) < 3 {
+			s.ChannelMessageSend(m.ChannelID, "Usage: `!guestbook "+sub+" <entry id>`")
+			return
+		}
+		if err := core.ModerateGuestbookEntry(ctx, args[2], m.Author.ID, sub); err != nil {
+			replyGuestbookError(s, m, err, "Couldn't moderate that entry")
+			return
+		}
+		s.ChannelMessageSend(m.ChannelID, "✅ Done.")
+
+	default:
+		s.ChannelMessageSend(m.ChannelID, "Unknown subcommand. Try `sign`, `list`, `hide`, `unhide`, `delete` or `report`.")
+	}
+}
+
+// resolveBuilder accepts a mention or a c500.store username.
+func resolveBuilder(ctx context.Context, core *CoreClient, m *discordgo.MessageCreate, arg string) (string, error) {
+	for _, u := range m.Mentions {
+		if strings.Contains(arg, u.ID) {
+			return u.ID, nil
+		}
+	}
+	return core.ResolveSlug(ctx, strings.Trim(arg, "<@!>"))
+}
+
+// replyGuestbookError shows the Core's reason for 4xx errors (rate limits,
+// filter rejections, permissions) and a generic message otherwise.
+func replyGuestbookError(s *discordgo.Session, m *discordgo.MessageCreate, err error, fallback string) {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) && apiErr.Status >= 400 && apiErr.Status < 500 && apiErr.Message != "" {
+		s.ChannelMessageSend(m.ChannelID, "❌ "+apiErr.Message)
+		return
+	}
+	s.ChannelMessageSend(m.ChannelID, "❌ "+fallback+", please try again later.")
+}
+
+// sendPrivately DMs the author, falling back to the channel if their DMs are closed.
+func sendPrivately(s *discordgo.Session, m *discordgo.MessageCreate, content string) {
+	content = truncate(content, 1900)
+	if ch, err := s.UserChannelCreate(m.Author.ID); err == nil {
+		if _, err := s.ChannelMessageSend(ch.ID, content); err == nil {
+			return
+		}
+	}
+	s.ChannelMessageSend(m.ChannelID, content)
+}
+
+// restAfterArgs returns the text after the first n whitespace-separated
+// arguments, keeping the original spacing and newlines of the rest.
+func restAfterArgs(content string, n int) string {
+	rest := strings.TrimSpace(content)
+	for i := 0; i < n && rest != ""; i++ {
+		end := strings.IndexFunc(rest, unicode.IsSpace)
+		if end < 0 {
+			return ""
+		}
+		rest = strings.TrimSpace(rest[end:])
+	}
+	return rest
+}
+
+// truncate shortens s to at most n runes, adding an ellipsis when cut.
+func truncate(s string, n int) string {
+	r := []rune(s)
+	if len(r) <= n {
+		return s
+	}
+	return string(r[:n-1]) + "…"
+}
@@ -1,11 +1,13 @@
 package main
 
 import (
+    "context"
     "flag"
     "fmt"
     "log"
     "os"
     "os/signal"
+    "strings"
     "syscall"
 
     "github.com/bwmarrin/discordgo"
@@ -15,6 +17,7 @@ import (
 // Variables used for command line parameters
 var (
     Token string
+    Core  *CoreClient
 )
 
 func init() {
@@ -36,11 +39,35 @@ func main() {
         log.Fatalf("error creating Discord session: %v", err)
     }
 
+    Core = NewCoreClient()
+
+    // Slash commands, uploaded to Discord on ready (see slash_commands.go)
+    registerSlashCommands(
+        pingSlashCommand(),
+        orderSlashCommand(),
+        c500SlashCommand(),
+        fulfillSlashCommand(),
+    )
+    registerComponentHandler(dropModalPrefix, dropModalHandler())
+    registerComponentHandler(buyButtonPrefix, buyButtonHandler())
+    registerComponentHandler(waitlistButtonPrefix, waitlistButtonHandler())
+
+    // Mentor applications from the mentor portal (see mentor_applications.go)
+    mentorIntake, err := NewMentorIntakeFromEnv(context.Background(), dg)
+    if err != nil {
+        log.Fatalf("error starting mentor intake: %v", err)
+    }
+    if mentorIntake != nil {
+        registerComponentHandler(mentorReviewPrefix, mentorIntake.ReviewHandler())
+    } else {
+        log.Println("MENTOR_INTAKE_TOKEN not set: mentor application intake is off")
+    }
+
     // 2. Register Handlers
     // We will add these functions in the next step
     dg.AddHandler(ready)
     dg.AddHandler(messageCreate)
-    dg.AddHandler(interactionCreate) // For button clicks
+    dg.AddHandler(interactionCreate) // Slash commands, buttons and modals
 
     // 3. Open Connection
     // We only need the 'Intents' to read messages and see guild members
@@ -51,14 +78,27 @@ func main() {
         log.Fatalf("error opening connection: %v", err)
     }
 
+    // 4. Start the drop fan-out poller (posts new drops to every subscribed server)
+    //    and the worker that keeps posted embeds in sync with drop status
+    ctx, cancel := context.WithCancel(context.Background())
+    defer cancel()
+    embedSync := NewEmbedSyncWorker(dg)
+    go embedSync.Run(ctx)
+    go NewDropFanout(dg, Core, embedSync).Run(ctx)
+
+    // 5. Accept mentor applications from the mentor portal
+    if mentorIntake != nil {
+        go mentorIntake.ListenAndServe(ctx)
+    }
+
     fmt.Println("C500 Bot is now running. Press CTRL-C to exit.")
 
-    // 4. Wait for Termination Signal (Keep app running)
+    // 6. Wait for Termination Signal (Keep app running)
     sc := make(chan os.Signal, 1)
     signal.Notify(sc, syscall.SIGINT, syscall.SIGTERM, os.Interrupt)
     <-sc
 
-    // 5. Cleanly Close
+    // 7. Cleanly Close
     dg.Close()
 }
 
@@ -67,6 +107,20 @@ func main() {
 func ready(s *discordgo.Session, event *discordgo.Ready) {
     // Set the "Playing" status
     s.UpdateGameStatus(0, "CSS Layout | Level 00")
+
+    // Ready fires again after reconnects; overwriting with the same set is harmless.
+    syncSlashCommands(s, event.User.ID)
+}
+
+// pingSlashCommand is /ping, the slash version of !ping.
+func pingSlashCommand() *SlashCommand {
+    return &SlashCommand{
+        Name:        "ping",
+        Description: "Check that the C500 bot is online",
+        Handler: func(c *CommandContext) {
+            c.Reply("Pong! C500 Systems Online.")
+        },
+    }
 }
 
 func messageCreate(s *discordgo.Session, m *discordgo.MessageCreate) {
@@ -79,4 +133,29 @@ func messageCreate(s *discordgo.Session, m *discordgo.MessageCreate) {
     if m.Content == "!ping" {
         s.ChannelMessageSend(m.ChannelID, "Pong! C500 Systems Online.")
     }
+
+    // Server admins opt in/out of the federated drop feed
+    if strings.HasPrefix(m.Content, "!marketplace") {
+        handleMarketplaceCommand(s, m, Core)
+    }
+
+    // Guestbooks on c500.store builder pages
+    if strings.HasPrefix(m.Content, "!guestbook") {
+        handleGuestbookCommand(s, m, Core)
+    }
+
+    // Builders link the Twitch channel they build on
+    if strings.HasPrefix(m.Content, "!twitch") {
+        handleTwitchCommand(s, m, Core)
+    }
+
+    // Moderators check live-fulfillment VODs that failed verification
+    if strings.HasPrefix(m.Content, "!vodreview") {
+        handleVODReviewCommand(s, m, Core)
+    }
+
+    // Buyers get their order page link and mute build update DMs
+    if strings.HasPrefix(m.Content, "!order") {
+        handleOrderCommand(s, m, Core)
+    }
 }
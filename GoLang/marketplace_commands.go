@@ -0,0 +1,67 @@
+package main
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// handleMarketplaceCommand handles the server-admin commands that opt a guild
+// in or out of the federated drop feed:
+//
+//	!marketplace subscribe                 -> every drop, posted in this channel
+//	!marketplace subscribe @builder ...    -> only drops from those builders
+//	!marketplace subscribe rts|commission  -> only that drop type (combinable)
+//	!marketplace unsubscribe
+func handleMarketplaceCommand(s *discordgo.Session, m *discordgo.MessageCreate, core *CoreClient) {
+	args := strings.Fields(m.Content)
+	if len(args) < 2 || m.GuildID == "" {
+		s.ChannelMessageSend(m.ChannelID, "Usage: `!marketplace subscribe [@builder ...] [rts|commission]` or `!marketplace unsubscribe`")
+		return
+	}
+
+	// Only people who can manage the server may change where drops are posted.
+	perms, err := s.UserChannelPermissions(m.Author.ID, m.ChannelID)
+	if err != nil || perms&discordgo.PermissionManageServer == 0 {
+		s.ChannelMessageSend(m.ChannelID, "You need the **Manage Server** permission to do that.")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	switch args[1] {
+	case "subscribe":
+		sub := GuildSubscription{ChannelID: m.ChannelID, UpdatedBy: m.Author.ID}
+		for _, u := range m.Mentions {
+			sub.BuilderIDs = append(sub.BuilderIDs, u.ID)
+		}
+		for _, a := range args[2:] {
+			switch strings.ToLower(a) {
+			case "rts":
+				sub.DropTypes = append(sub.DropTypes, "ready_to_ship")
+			case "commission":
+				sub.DropTypes = append(sub.DropTypes, "commission")
+			}
+		}
+		sub.AllDrops = len(sub.BuilderIDs) == 0 && len(sub.DropTypes) == 0
+
+		if err := core.PutSubscription(ctx, m.GuildID, sub); err != nil {
+			s.ChannelMessageSend(m.ChannelID, "❌ Couldn't save the subscription, please try again later.")
+			return
+		}
+		s.ChannelMessageSend(m.ChannelID, "✅ C500 drops will now be posted in <#"+m.ChannelID+">.")
+
+	case "unsubscribe":
+		if err := core.DeleteSubscription(ctx, m.GuildID); err != nil {
+			s.ChannelMessageSend(m.ChannelID, "❌ Couldn't remove the subscription, please try again later.")
+			return
+		}
+		s.ChannelMessageSend(m.ChannelID, "👋 This server will no longer receive C500 drops.")
+
+	default:
+		s.ChannelMessageSend(m.ChannelID, "Unknown subcommand. Try `subscribe` or `unsubscribe`.")
+	}
+}
@@ -0,0 +1,419 @@
+package main
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"github.com/bwmarrin/discordgo"
+	"google.golang.org/api/iterator"
+)
+
+// Mentor applications come from the mentor portal's form. The portal POSTs
+// each one to the bot's intake endpoint; the bot saves it to Firestore and
+// posts a review card with Approve/Deny buttons in the moderators' channel.
+// A decision is recorded once (with who made it), approved applicants get
+// the Mentor role, and either way the applicant is DMed.
+//
+// Configuration:
+//
+//	MENTOR_INTAKE_TOKEN        shared secret the portal sends as "Authorization: Bearer <token>"
+//	MENTOR_INTAKE_ADDR         listen address for the intake endpoint (default ":8081")
+//	MENTOR_REVIEW_CHANNEL_ID   where review cards are posted
+//	MENTOR_ROLE_ID             role given to approved mentors
+//	GCP_PROJECT_ID             Firestore project (same as the Core)
+
+const (
+	mentorApplicationsCollection = "mentor_applications"
+	mentorReviewPrefix           = "mentor_review"
+
+	mentorStatusPending  = "pending"
+	mentorStatusApproved = "approved"
+	mentorStatusDenied   = "denied"
+
+	maxMentorApplicationBytes = 64 << 10
+)
+
+const colorDenied = 0xFF6961 // Pastel Red
+
+// MentorApplication is one application from the mentor portal, stored in
+// the "mentor_applications" collection keyed by ID.
+type MentorApplication struct {
+	ID            string    `json:"id" firestore:"id"`
+	DiscordID     string    `json:"discord_id" firestore:"discord_id"`
+	DiscordName   string    `json:"discord_name" firestore:"discord_name"`
+	TwitchURL     string    `json:"twitch_url" firestore:"twitch_url"`
+	Philosophy    string    `json:"philosophy" firestore:"philosophy"`
+	WorkLink      string    `json:"work_link" firestore:"work_link"`
+	TeachingLevel string    `json:"teaching_level" firestore:"teaching_level"`
+	Timezone      string    `json:"timezone" firestore:"timezone"`
+	Availability  string    `json:"availability" firestore:"availability"`
+	CanDoLive     bool      `json:"can_do_live" firestore:"can_do_live"`
+	EnvDesktop    bool      `json:"env_desktop" firestore:"env_desktop"`
+	EnvMobile     bool      `json:"env_mobile" firestore:"env_mobile"`
+	Status        string    `json:"status" firestore:"status"` // pending, approved, denied
+	SubmittedAt   time.Time `json:"submitted_at" firestore:"submitted_at"`
+
+	// Review, set once by the moderator who clicked Approve or Deny.
+	ReviewedBy      string     `json:"reviewed_by,omitempty" firestore:"reviewed_by,omitempty"`
+	ReviewedAt      *time.Time `json:"reviewed_at,omitempty" firestore:"reviewed_at,omitempty"`
+	ReviewChannelID string     `json:"review_channel_id,omitempty" firestore:"review_channel_id,omitempty"`
+	ReviewMessageID string     `json:"review_message_id,omitempty" firestore:"review_message_id,omitempty"`
+}
+
+var errAlreadyReviewed = errors.New("application was already reviewed")
+
+// MentorIntake receives applications and handles their review buttons.
+type MentorIntake struct {
+	session         *discordgo.Session
+	fs              *firestore.Client
+	token           string
+	reviewChannelID string
+	mentorRoleID    string
+}
+
+// NewMentorIntakeFromEnv returns nil (intake off) when MENTOR_INTAKE_TOKEN
+// isn't set. Called from main.go.
+func NewMentorIntakeFromEnv(ctx context.Context, s *discordgo.Session) (*MentorIntake, error) {
+	token := os.Getenv("MENTOR_INTAKE_TOKEN")
+	if token == "" {
+		return nil, nil
+	}
+	m := &MentorIntake{
+		session:         s,
+		token:           token,
+		reviewChannelID: os.Getenv("MENTOR_REVIEW_CHANNEL_ID"),
+		mentorRoleID:    os.Getenv("MENTOR_ROLE_ID"),
+	}
+	if m.reviewChannelID == "" || m.mentorRoleID == "" {
+		return nil, errors.New("MENTOR_REVIEW_CHANNEL_ID and MENTOR_ROLE_ID are required when MENTOR_INTAKE_TOKEN is set")
+	}
+	fs, err := firestore.NewClient(ctx, os.Getenv("GCP_PROJECT_ID"))
+	if err != nil {
+		return nil, fmt.Errorf("firestore: %w", err)
+	}
+	m.fs = fs
+	return m, nil
+}
+
+// ListenAndServe runs the intake endpoint until ctx is cancelled.
+func (m *MentorIntake) ListenAndServe(ctx context.Context) {
+	addr := os.Getenv("MENTOR_INTAKE_ADDR")
+	if addr == "" {
+		addr = ":8081"
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/mentor-applications", m.handleSubmit)
+	srv := &http.Server{Addr: addr, Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+
+	go func() {
+		<-ctx.Done()
+		srv.Close()
+	}()
+	log.Printf("mentor intake: listening on %s", addr)
+	if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		log.Printf("mentor intake: server stopped: %v", err)
+	}
+}
+
+// ReviewHandler handles the Approve/Deny buttons. Registered in main.go.
+func (m *MentorIntake) ReviewHandler() *ComponentHandler {
+	return &ComponentHandler{Ephemeral: true, Defer: true, Handler: m.handleReview}
+}
+
+// ==========================================
+// Intake
+// ==========================================
+
+// handleSubmit handles POST /mentor-applications from the mentor portal.
+func (m *MentorIntake) handleSubmit(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "use POST"})
+		return
+	}
+	given := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if subtle.ConstantTimeCompare([]byte(given), []byte(m.token)) != 1 {
+		writeJSON(w, http.StatusUnauthorized, map[string]string{"error": "invalid token"})
+		return
+	}
+
+	var app MentorApplication
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxMentorApplicationBytes)).Decode(&app); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid JSON"})
+		return
+	}
+	app.DiscordID = strings.TrimSpace(app.DiscordID)
+	if !isSnowflake(app.DiscordID) || strings.TrimSpace(app.DiscordName) == "" || strings.TrimSpace(app.Philosophy) == "" {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "discord_id, discord_name and philosophy are required"})
+		return
+	}
+
+	ctx := r.Context()
+	pending, err := m.hasPendingApplication(ctx, app.DiscordID)
+	if err != nil {
+		log.Printf("mentor intake: pending check for %s failed: %v", app.DiscordID, err)
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to save application"})
+		return
+	}
+	if pending {
+		writeJSON(w, http.StatusConflict, map[string]string{"error": "you already have an application waiting for review"})
+		return
+	}
+
+	// The ID is needed for the buttons, so the card is posted first and the
+	// application saved after; a failed save takes the card down again.
+	ref := m.fs.Collection(mentorApplicationsCollection).NewDoc()
+	app.ID = ref.ID
+	app.Status = mentorStatusPending
+	app.SubmittedAt = time.Now().UTC()
+	app.ReviewedBy, app.ReviewedAt = "", nil
+
+	card, err := m.session.ChannelMessageSendComplex(m.reviewChannelID, &discordgo.MessageSend{
+		Content:    "📄 **New mentor application**",
+		Embeds:     []*discordgo.MessageEmbed{mentorApplicationEmbed(&app)},
+		Components: []discordgo.MessageComponent{mentorReviewButtons(app.ID)},
+	})
+	if err != nil {
+		log.Printf("mentor intake: failed to post review card: %v", err)
+		writeJSON(w, http.StatusBadGateway, map[string]string{"error": "failed to post application for review"})
+		return
+	}
+	app.ReviewChannelID, app.ReviewMessageID = card.ChannelID, card.ID
+
+	if _, err := ref.Create(ctx, app); err != nil {
+		log.Printf("mentor intake: failed to save application %s: %v", app.ID, err)
+		m.session.ChannelMessageDelete(card.ChannelID, card.ID)
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to save application"})
+		return
+	}
+	writeJSON(w, http.StatusCreated, map[string]string{"id": app.ID, "status": app.Status})
+}
+
+func (m *MentorIntake) hasPendingApplication(ctx context.Context, discordID string) (bool, error) {
+	iter := m.fs.Collection(mentorApplicationsCollection).
+		Where("discord_id", "==", discordID).
+		Where("status", "==", mentorStatusPending).
+		Limit(1).
+		Documents(ctx)
+	defer iter.Stop()
+	_, err := iter.Next()
+	if err == iterator.Done {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+func mentorReviewButtons(appID string) discordgo.ActionsRow {
+	return discordgo.ActionsRow{Components: []discordgo.MessageComponent{
+		discordgo.Button{Label: "Approve Mentor", Style: discordgo.SuccessButton, CustomID: componentID(mentorReviewPrefix, "approve:"+appID)},
+		discordgo.Button{Label: "Deny", Style: discordgo.DangerButton, CustomID: componentID(mentorReviewPrefix, "deny:"+appID)},
+	}}
+}
+
+func mentorApplicationEmbed(app *MentorApplication) *discordgo.MessageEmbed {
+	orDash := func(s string) string {
+		if s = strings.TrimSpace(s); s == "" {
+			return "—"
+		}
+		return truncate(s, 1024)
+	}
+	yesNo := func(b bool) string {
+		if b {
+			return "Yes"
+		}
+		return "No"
+	}
+	var devices []string
+	if app.EnvDesktop {
+		devices = append(devices, "Desktop")
+	}
+	if app.EnvMobile {
+		devices = append(devices, "Mobile")
+	}
+
+	return &discordgo.MessageEmbed{
+		Title:       truncate(app.DiscordName, 200) + " applied to be a mentor",
+		Description: "**Teaching philosophy**\n" + truncate(app.Philosophy, 3500),
+		Color:       colorPrimary,
+		Fields: []*discordgo.MessageEmbedField{
+			{Name: "Applicant", Value: "<@" + app.DiscordID + ">", Inline: true},
+			{Name: "Teaching Level", Value: orDash(app.TeachingLevel), Inline: true},
+			{Name: "Timezone", Value: orDash(app.Timezone), Inline: true},
+			{Name: "Availability", Value: orDash(app.Availability), Inline: true},
+			{Name: "Live Sessions", Value: yesNo(app.CanDoLive), Inline: true},
+			{Name: "Devices", Value: orDash(strings.Join(devices, ", ")), Inline: true},
+			{Name: "Twitch", Value: orDash(app.TwitchURL)},
+			{Name: "Work", Value: orDash(app.WorkLink)},
+		},
+		Footer:    &discordgo.MessageEmbedFooter{Text: "Application " + app.ID},
+		Timestamp: app.SubmittedAt.Format(time.RFC3339),
+	}
+}
+
+// ==========================================
+// Review
+// ==========================================
+
+func (m *MentorIntake) handleReview(c *CommandContext) {
+	// Approving hands out a role, so reviewers need to be able to do that by hand too.
+	if !c.hasPermissions(discordgo.PermissionManageRoles) {
+		c.Reply("You need the **Manage Roles** permission to review mentor applications.")
+		return
+	}
+	action, appID, _ := strings.Cut(c.Payload, ":")
+	var newStatus string
+	switch action {
+	case "approve":
+		newStatus = mentorStatusApproved
+	case "deny":
+		newStatus = mentorStatusDenied
+	default:
+		// Only our own buttons send this; never approve on a malformed ID.
+		log.Printf("mentor review: unknown action %q for application %s", action, appID)
+		c.Reply("❌ Unknown review action.")
+		return
+	}
+
+	app, err := m.decide(c.Ctx, appID, newStatus, c.UserID())
+	if errors.Is(err, errAlreadyReviewed) {
+		m.closeCard(c, app, "")
+		c.Reply(fmt.Sprintf("This application was already %s by <@%s>.", app.Status, app.ReviewedBy))
+		return
+	}
+	if err != nil {
+		log.Printf("mentor review: failed to record decision on %s: %v", appID, err)
+		c.Reply("❌ Couldn't record the decision, please try again.")
+		return
+	}
+
+	note := ""
+	if newStatus == mentorStatusApproved {
+		if err := c.Session.GuildMemberRoleAdd(c.Interaction.GuildID, app.DiscordID, m.mentorRoleID); err != nil {
+			log.Printf("mentor review: failed to give %s the Mentor role: %v", app.DiscordID, err)
+			note = "⚠️ Couldn't assign the Mentor role (are they still in the server?). Please add it by hand."
+		}
+	}
+	if err := m.notifyApplicant(app); err != nil {
+		log.Printf("mentor review: couldn't DM %s: %v", app.DiscordID, err)
+		if note != "" {
+			note += "\n"
+		}
+		note += "⚠️ Couldn't DM the applicant (their DMs are closed)."
+	}
+
+	m.closeCard(c, app, note)
+	if newStatus == mentorStatusApproved {
+		c.Reply("✅ Approved <@" + app.DiscordID + "> as a mentor.")
+		return
+	}
+	c.Reply("Denied the application from <@" + app.DiscordID + ">.")
+}
+
+// decide records the decision in a transaction, so two moderators clicking
+// at once can't both decide. errAlreadyReviewed comes with the stored application.
+func (m *MentorIntake) decide(ctx context.Context, appID, newStatus, moderatorID string) (*MentorApplication, error) {
+	ref := m.fs.Collection(mentorApplicationsCollection).Doc(appID)
+	var app MentorApplication
+	err := m.fs.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		snap, err := tx.Get(ref)
+		if err != nil {
+			return err
+		}
+		if err := snap.DataTo(&app); err != nil {
+			return err
+		}
+		if app.Status != mentorStatusPending {
+			return errAlreadyReviewed
+		}
+		now := time.Now().UTC()
+		app.Status, app.ReviewedBy, app.ReviewedAt = newStatus, moderatorID, &now
+		return tx.Update(ref, []firestore.Update{
+			{Path: "status", Value: newStatus},
+			{Path: "reviewed_by", Value: moderatorID},
+			{Path: "reviewed_at", Value: now},
+		})
+	})
+	return &app, err
+}
+
+func (m *MentorIntake) notifyApplicant(app *MentorApplication) error {
+	msg := "🎉 **Welcome to the C500 mentor team!** Your application was approved and you now have the Mentor role. " +
+		"Keep an eye on the mentor channels for your first office hours."
+	if app.Status == mentorStatusDenied {
+		msg = "Thanks for applying to mentor at C500. We're not able to take you on right now, " +
+			"but we'd love for you to stay in the community and apply again later."
+	}
+	ch, err := m.session.UserChannelCreate(app.DiscordID)
+	if err != nil {
+		return err
+	}
+	_, err = m.session.ChannelMessageSend(ch.ID, msg)
+	return err
+}
+
+// closeCard stamps the decision on the review card and removes its buttons.
+func (m *MentorIntake) closeCard(c *CommandContext, app *MentorApplication, note string) {
+	msg := c.Interaction.Message
+	if msg == nil {
+		return
+	}
+	stamp := "✅ **Approved**"
+	color := colorProgress
+	if app.Status == mentorStatusDenied {
+		stamp, color = "🚫 **Denied**", colorDenied
+	}
+	content := fmt.Sprintf("%s by <@%s>", stamp, app.ReviewedBy)
+	if app.ReviewedAt != nil {
+		content += fmt.Sprintf(" <t:%d:R>", app.ReviewedAt.Unix())
+	}
+	if note != "" {
+		content += "\n" + note
+	}
+
+	embeds := msg.Embeds
+	if len(embeds) > 0 {
+		embeds[0].Color = color
+	}
+	components := []discordgo.MessageComponent{}
+	_, err := m.session.ChannelMessageEditComplex(&discordgo.MessageEdit{
+		ID:         msg.ID,
+		Channel:    msg.ChannelID,
+		Content:    &content,
+		Embeds:     &embeds,
+		Components: &components,
+	})
+	if err != nil {
+		log.Printf("mentor review: failed to update card for %s: %v", app.ID, err)
+	}
+}
+
+// ==========================================
+// Helpers
+// ==========================================
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+// isSnowflake reports whether s looks like a Discord ID.
+func isSnowflake(s string) bool {
+	if len(s) < 15 || len(s) > 21 {
+		return false
+	}
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
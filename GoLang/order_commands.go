@@ -0,0 +1,124 @@
+package main
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// handleOrderCommand is for buyers following a build. Sellers' progress
+// updates arrive as DMs; buyers can mute them per order:
+//
+//	!order <order id>
+//	!order mute|unmute <order id>
+func handleOrderCommand(s *discordgo.Session, m *discordgo.MessageCreate, core *CoreClient) {
+	args := strings.Fields(m.Content)
+	if args[0] != "!order" {
+		return // Some other command that starts with "!order".
+	}
+	if len(args) < 2 {
+		s.ChannelMessageSend(m.ChannelID, "Usage: `!order <order id>` for your order page, `!order mute|unmute <order id>` for build update DMs")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	switch sub := strings.ToLower(args[1]); sub {
+	case "mute", "unmute":
+		if len(args) < 3 {
+			s.ChannelMessageSend(m.ChannelID, "Usage: `!order "+sub+" <order id>`")
+			return
+		}
+		if err := core.SetOrderUpdatesMuted(ctx, args[2], m.Author.ID, sub == "mute"); err != nil {
+			replyGuestbookError(s, m, err, "Couldn't change your update settings")
+			return
+		}
+		if sub == "mute" {
+			sendPrivately(s, m, "🔕 Build updates for `"+args[2]+"` muted. They're still on your order page; `!order unmute "+args[2]+"` to get DMs again.")
+			return
+		}
+		sendPrivately(s, m, "🔔 You'll get build updates for `"+args[2]+"` in your DMs again.")
+
+	default:
+		// The link is private to the buyer, so it only ever goes to their DMs
+		// (sendPrivately would fall back to the channel).
+		order, err := core.GetOrderStatus(ctx, args[1], m.Author.ID)
+		if err != nil {
+			replyGuestbookError(s, m, err, "Couldn't load that order")
+			return
+		}
+		msg := "🧾 Order `" + order.OrderID + "`: " + strings.ReplaceAll(order.ProductionStatus, "_", " ")
+		if order.OrderURL != "" {
+			msg += "\nTimeline, photos and tracking: " + order.OrderURL
+		}
+		if order.UpdatesMuted {
+			msg += "\n🔕 Build update DMs are muted (`!order unmute " + order.OrderID + "`)."
+		}
+		ch, err := s.UserChannelCreate(m.Author.ID)
+		if err == nil {
+			_, err = s.ChannelMessageSend(ch.ID, msg)
+		}
+		if err != nil {
+			s.ChannelMessageSend(m.ChannelID, "❌ I couldn't DM you your order link. Open your DMs and try again.")
+		}
+	}
+}
+
+// orderSlashCommand is the slash version of !order. Its replies are
+// ephemeral, so the private order link can be shown right in the channel.
+func orderSlashCommand() *SlashCommand {
+	orderIDOption := []*discordgo.ApplicationCommandOption{{
+		Type:        discordgo.ApplicationCommandOptionString,
+		Name:        "order_id",
+		Description: "The order ID from your receipt or build update DMs",
+		Required:    true,
+	}}
+	setMuted := func(muted bool) func(c *CommandContext) {
+		return func(c *CommandContext) {
+			orderID := c.String("order_id")
+			if err := c.Core.SetOrderUpdatesMuted(c.Ctx, orderID, c.UserID(), muted); err != nil {
+				c.ReplyError(err, "Couldn't change your update settings")
+				return
+			}
+			if muted {
+				c.Reply("🔕 Build updates for `" + orderID + "` muted. They're still on your order page.")
+				return
+			}
+			c.Reply("🔔 You'll get build updates for `" + orderID + "` in your DMs again.")
+		}
+	}
+
+	return &SlashCommand{
+		Name:        "order",
+		Description: "Follow an order you bought",
+		Ephemeral:   true,
+		Defer:       true,
+		Subcommands: []*Subcommand{
+			{
+				Name:        "view",
+				Description: "Show where your order is, with a link to its page",
+				Options:     orderIDOption,
+				Handler: func(c *CommandContext) {
+					order, err := c.Core.GetOrderStatus(c.Ctx, c.String("order_id"), c.UserID())
+					if err != nil {
+						c.ReplyError(err, "Couldn't load that order")
+						return
+					}
+					msg := "🧾 Order `" + order.OrderID + "`: " + strings.ReplaceAll(order.ProductionStatus, "_", " ")
+					if order.OrderURL != "" {
+						msg += "\nTimeline, photos and tracking: " + order.OrderURL
+					}
+					if order.UpdatesMuted {
+						msg += "\n🔕 Build update DMs are muted (`/order unmute`)."
+					}
+					c.Reply(msg)
+				},
+			},
+			{Name: "mute", Description: "Stop build update DMs for an order", Options: orderIDOption, Handler: setMuted(true)},
+			{Name: "unmute", Description: "Get build update DMs for an order again", Options: orderIDOption, Handler: setMuted(false)},
+		},
+	}
+}
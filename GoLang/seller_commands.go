@@ -0,0 +1,400 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// /c500 is for sellers: connecting Stripe and listing drops.
+//
+//	/c500 setup                          -> private Stripe onboarding link
+//	/c500 drop type kind image [image2 image3]
+//	                                     -> opens the drop form (title, price, spec...)
+//
+// Discord modals can't hold file inputs, so the images come in as command
+// options and wait in pendingDrops until the form is submitted.
+
+const (
+	dropModalPrefix = "c500drop"
+	// maxDropImageBytes matches the Core's upload limit.
+	maxDropImageBytes = 15 << 20
+	// pendingDropTTL is how long an opened drop form stays submittable.
+	pendingDropTTL = 30 * time.Minute
+)
+
+// pendingDrop is what the seller picked in /c500 drop, waiting for the form.
+type pendingDrop struct {
+	sellerID  string
+	dropType  string
+	kind      string
+	images    []*discordgo.MessageAttachment
+	expiresAt time.Time
+}
+
+var (
+	pendingDropsMu sync.Mutex
+	pendingDrops   = map[string]*pendingDrop{}
+)
+
+// imageClient downloads attachments from Discord's CDN.
+var imageClient = &http.Client{Timeout: 20 * time.Second}
+
+func c500SlashCommand() *SlashCommand {
+	return &SlashCommand{
+		Name:        "c500",
+		Description: "Sell on C500",
+		Ephemeral:   true,
+		Subcommands: []*Subcommand{
+			{
+				Name:        "setup",
+				Description: "Connect Stripe so you can get paid for your drops",
+				Defer:       true,
+				Handler:     handleC500Setup,
+			},
+			{
+				Name:        "drop",
+				Description: "List a new drop",
+				Options: []*discordgo.ApplicationCommandOption{
+					{
+						Type:        discordgo.ApplicationCommandOptionString,
+						Name:        "type",
+						Description: "Ready to ship, or built to order",
+						Required:    true,
+						Choices: []*discordgo.ApplicationCommandOptionChoice{
+							{Name: "Ready to ship", Value: "ready_to_ship"},
+							{Name: "Commission", Value: "commission"},
+						},
+					},
+					{
+						Type:        discordgo.ApplicationCommandOptionString,
+						Name:        "kind",
+						Description: "What you're selling",
+						Required:    true,
+						Choices: []*discordgo.ApplicationCommandOptionChoice{
+							{Name: "Keyboard", Value: "keyboard"},
+							{Name: "Keycap set", Value: "keycap_set"},
+							{Name: "Switch pack", Value: "switch_pack"},
+						},
+					},
+					{Type: discordgo.ApplicationCommandOptionAttachment, Name: "image", Description: "Main photo", Required: true},
+					{Type: discordgo.ApplicationCommandOptionAttachment, Name: "image2", Description: "Another photo"},
+					{Type: discordgo.ApplicationCommandOptionAttachment, Name: "image3", Description: "Another photo"},
+				},
+				Handler: handleC500Drop,
+			},
+		},
+	}
+}
+
+// dropModalHandler handles the submitted drop form. Registered in main.go.
+func dropModalHandler() *ComponentHandler {
+	return &ComponentHandler{Ephemeral: true, Defer: true, Handler: handleDropModalSubmit}
+}
+
+// ==========================================
+// /c500 setup
+// ==========================================
+
+// handleC500Setup replies with the seller's Stripe link. The reply is
+// ephemeral: anyone holding the link could finish onboarding as them.
+func handleC500Setup(c *CommandContext) {
+	link, err := c.Core.GetStripeOnboardingLink(c.Ctx, c.UserID(), c.displayName())
+	if err != nil {
+		c.ReplyError(err, "Couldn't create your Stripe link")
+		return
+	}
+	c.Reply("💳 Click here to connect Stripe: " + link + "\nThe link is just for you and only works once. Run `/c500 setup` again if it expires.")
+}
+
+// displayName is the member's name as shown in the server, for new builder records.
+func (c *CommandContext) displayName() string {
+	if m := c.Interaction.Member; m != nil {
+		if m.Nick != "" {
+			return m.Nick
+		}
+		if m.User != nil {
+			return displayNameOf(m.User)
+		}
+	}
+	if c.Interaction.User != nil {
+		return displayNameOf(c.Interaction.User)
+	}
+	return ""
+}
+
+func displayNameOf(u *discordgo.User) string {
+	if u.GlobalName != "" {
+		return u.GlobalName
+	}
+	return u.Username
+}
+
+// ==========================================
+// /c500 drop
+// ==========================================
+
+func handleC500Drop(c *CommandContext) {
+	pending := &pendingDrop{
+		sellerID:  c.UserID(),
+		dropType:  c.String("type"),
+		kind:      c.String("kind"),
+		expiresAt: time.Now().Add(pendingDropTTL),
+	}
+	for _, name := range []string{"image", "image2", "image3"} {
+		img := c.Attachment(name)
+		if img == nil {
+			continue
+		}
+		if !strings.HasPrefix(img.ContentType, "image/") {
+			c.Reply("❌ `" + img.Filename + "` isn't an image. Attach JPEG, PNG, GIF or WebP photos.")
+			return
+		}
+		if img.Size > maxDropImageBytes {
+			c.Reply("❌ `" + img.Filename + "` is too big: images must be 15 MB or smaller.")
+			return
+		}
+		pending.images = append(pending.images, img)
+	}
+
+	// The interaction ID is unique and short enough for a custom ID.
+	nonce := c.Interaction.ID
+	pendingDropsMu.Lock()
+	for k, p := range pendingDrops {
+		if time.Now().After(p.expiresAt) {
+			delete(pendingDrops, k)
+		}
+	}
+	pendingDrops[nonce] = pending
+	pendingDropsMu.Unlock()
+
+	c.Modal(componentID(dropModalPrefix, nonce), "New drop",
+		&discordgo.TextInput{CustomID: "title", Label: "Title", Style: discordgo.TextInputShort, Required: true, MaxLength: 100},
+		&discordgo.TextInput{CustomID: "price", Label: "Price (USD)", Style: discordgo.TextInputShort, Required: true, Placeholder: "e.g. 350 or 349.99", MaxLength: 12},
+		&discordgo.TextInput{CustomID: "spec", Label: "Spec, one \"field: value\" per line", Style: discordgo.TextInputParagraph, Required: true, Placeholder: specPlaceholders[pending.kind], MaxLength: 1000},
+		&discordgo.TextInput{CustomID: "description", Label: "Description", Style: discordgo.TextInputParagraph, MaxLength: 2000},
+		&discordgo.TextInput{CustomID: "go_live", Label: "Go live at (UTC, empty for now)", Style: discordgo.TextInputShort, Placeholder: "e.g. 2026-11-01 18:00", MaxLength: 25},
+	)
+}
+
+func handleDropModalSubmit(c *CommandContext) {
+	pendingDropsMu.Lock()
+	pending := pendingDrops[c.Payload]
+	if pending != nil && pending.sellerID == c.UserID() {
+		delete(pendingDrops, c.Payload)
+	}
+	pendingDropsMu.Unlock()
+	if pending == nil || pending.sellerID != c.UserID() || time.Now().After(pending.expiresAt) {
+		c.Reply("❌ This drop form expired. Run `/c500 drop` again.")
+		return
+	}
+
+	price, err := parsePriceCents(c.ModalValue("price"))
+	if err != nil {
+		c.Reply("❌ " + err.Error())
+		return
+	}
+	spec, err := parseDropSpec(pending.kind, c.ModalValue("spec"))
+	if err != nil {
+		c.Reply("❌ " + err.Error())
+		return
+	}
+	var goLiveAt *time.Time
+	if raw := c.ModalValue("go_live"); raw != "" {
+		t, err := parseGoLive(raw)
+		if err != nil {
+			c.Reply("❌ " + err.Error())
+			return
+		}
+		goLiveAt = &t
+	}
+
+	req := CreateDropRequest{
+		SellerDiscordID: pending.sellerID,
+		Title:           c.ModalValue("title"),
+		PriceInCents:    price,
+		Type:            pending.dropType,
+		Description:     c.ModalValue("description"),
+		Spec:            spec,
+		GoLiveAt:        goLiveAt,
+	}
+	for _, img := range pending.images {
+		assetID, err := uploadAttachment(c.Ctx, c.Core, pending.sellerID, img)
+		if err != nil {
+			c.ReplyError(err, "Couldn't upload `"+img.Filename+"`")
+			return
+		}
+		req.ImageAssetIDs = append(req.ImageAssetIDs, assetID)
+	}
+
+	drop, err := c.Core.CreateDrop(c.Ctx, req)
+	if err != nil {
+		c.ReplyError(err, "Couldn't create your drop")
+		return
+	}
+	if drop.GoLiveAt != nil {
+		c.Reply(fmt.Sprintf("🗓️ **%s** is scheduled and goes live <t:%d:R>. Drop ID: `%s`", drop.Title, drop.GoLiveAt.Unix(), drop.ID))
+		return
+	}
+	c.Reply(fmt.Sprintf("🚀 **%s** is live! Drop ID: `%s`", drop.Title, drop.ID))
+}
+
+// uploadAttachment copies a Discord attachment into the seller's Core assets.
+func uploadAttachment(ctx context.Context, core *CoreClient, sellerID string, img *discordgo.MessageAttachment) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, img.URL, nil)
+	if err != nil {
+		return "", err
+	}
+	resp, err := imageClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("downloading %s: %s", img.Filename, resp.Status)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxDropImageBytes+1))
+	if err != nil {
+		return "", err
+	}
+	return core.UploadImage(ctx, sellerID, img.Filename, data)
+}
+
+// ==========================================
+// Form Parsing
+// ==========================================
+
+// specPlaceholders show the fields each kind of drop takes.
+var specPlaceholders = map[string]string{
+	"keyboard":    "Layout: 65%\nCase: Aluminum\nPlate: FR4\nSwitches: Gateron Oil King\nSolder: hot swap",
+	"keycap_set":  "Name: GMK Olivia++\nProfile: Cherry\nMaterial: ABS\nKits: Base, Novelties",
+	"switch_pack": "Switch: Gateron Oil King\nType: Linear\nQuantity: 70\nLube: 205g0\nFilmed: yes",
+}
+
+// specFields are the field names the form accepts per kind, mapped to the
+// Core's JSON keys.
+var specFields = map[string]map[string]string{
+	"keyboard": {
+		"layout": "layout", "case": "case", "plate": "plate", "switches": "switches",
+		"stabilizers": "stabilizers", "stabs": "stabilizers", "lube": "lube",
+		"keycaps": "keycaps", "solder": "solder",
+	},
+	"keycap_set":  {"name": "name", "profile": "profile", "material": "material", "kits": "kits"},
+	"switch_pack": {"switch": "switch", "type": "type", "quantity": "quantity", "lube": "lube", "filmed": "filmed"},
+}
+
+// parseDropSpec reads "field: value" lines into the spec for kind. Values
+// are checked (and canonicalized) by the Core.
+func parseDropSpec(kind, text string) (DropSpec, error) {
+	spec := DropSpec{Kind: kind}
+	fields := specFields[kind]
+	values := map[string]string{}
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		name, value, ok := strings.Cut(line, ":")
+		key, known := fields[strings.ToLower(strings.TrimSpace(name))]
+		if !ok || !known {
+			return spec, fmt.Errorf("Couldn't read spec line %q. Write one `field: value` per line, like:\n%s", line, specPlaceholders[kind])
+		}
+		values[key] = strings.TrimSpace(value)
+	}
+
+	switch kind {
+	case "keyboard":
+		if solder, ok := values["solder"]; ok {
+			switch strings.NewReplacer(" ", "", "-", "", "_", "").Replace(strings.ToLower(solder)) {
+			case "hotswap":
+				values["solder"] = "hot_swap"
+			case "soldered", "solder":
+				values["solder"] = "soldered"
+			default:
+				return spec, fmt.Errorf("Solder must be \"hot swap\" or \"soldered\"")
+			}
+		}
+		spec.Keyboard = values
+	case "keycap_set":
+		spec.KeycapSet = map[string]interface{}{}
+		for k, v := range values {
+			spec.KeycapSet[k] = v
+		}
+		if kits, ok := values["kits"]; ok {
+			var list []string
+			for _, kit := range strings.Split(kits, ",") {
+				if kit = strings.TrimSpace(kit); kit != "" {
+					list = append(list, kit)
+				}
+			}
+			spec.KeycapSet["kits"] = list
+		}
+	case "switch_pack":
+		spec.SwitchPack = map[string]interface{}{}
+		for k, v := range values {
+			spec.SwitchPack[k] = v
+		}
+		if q, ok := values["quantity"]; ok {
+			n, err := strconv.Atoi(q)
+			if err != nil || n <= 0 {
+				return spec, fmt.Errorf("Quantity must be a whole number of switches")
+			}
+			spec.SwitchPack["quantity"] = n
+		}
+		if f, ok := values["filmed"]; ok {
+			switch strings.ToLower(f) {
+			case "yes", "y", "true":
+				spec.SwitchPack["filmed"] = true
+			case "no", "n", "false":
+				spec.SwitchPack["filmed"] = false
+			default:
+				return spec, fmt.Errorf("Filmed must be yes or no")
+			}
+		}
+	}
+	return spec, nil
+}
+
+// parsePriceCents reads "350", "$349.99" or "1,200" as cents.
+func parsePriceCents(s string) (int64, error) {
+	s = strings.NewReplacer("$", "", ",", "", " ", "").Replace(s)
+	dollars, cents, hasCents := strings.Cut(s, ".")
+	d, err := strconv.ParseInt(dollars, 10, 64)
+	if err != nil || d < 0 {
+		return 0, fmt.Errorf("Price must be a dollar amount, like 350 or 349.99")
+	}
+	var c int64
+	if hasCents {
+		if len(cents) == 1 {
+			cents += "0"
+		}
+		if len(cents) != 2 {
+			return 0, fmt.Errorf("Price must be a dollar amount, like 350 or 349.99")
+		}
+		if c, err = strconv.ParseInt(cents, 10, 64); err != nil || c < 0 {
+			return 0, fmt.Errorf("Price must be a dollar amount, like 350 or 349.99")
+		}
+	}
+	total := d*100 + c
+	if total <= 0 {
+		return 0, fmt.Errorf("Price must be more than $0")
+	}
+	return total, nil
+}
+
+// parseGoLive reads a UTC time like "2026-11-01 18:00" (or RFC 3339).
+func parseGoLive(s string) (time.Time, error) {
+	for _, layout := range []string{"2006-01-02 15:04", "2006-01-02T15:04", time.RFC3339} {
+		if t, err := time.ParseInLocation(layout, s, time.UTC); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("Go live time must look like 2026-11-01 18:00 (UTC)")
+}
@@ -0,0 +1,540 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// Slash commands replace the "!" prefix commands: Discord shows them in the
+// command picker with typed options and autocomplete, and they don't need the
+// privileged message content intent.
+//
+// A command is declared once as a SlashCommand and registered in main.go.
+// On ready, syncSlashCommands uploads every declaration to Discord, and
+// interactionCreate routes invocations back to the handlers here.
+
+// slashInteractionTimeout bounds a handler's Core calls. Deferred responses
+// stay editable for 15 minutes, so this is about the Core, not Discord.
+const slashInteractionTimeout = 30 * time.Second
+
+// SlashCommand declares one top-level application command.
+type SlashCommand struct {
+	Name        string
+	Description string
+	// Options are the command's own options. Leave empty when it has Subcommands.
+	Options     []*discordgo.ApplicationCommandOption
+	Subcommands []*Subcommand
+
+	// Permissions hides the command from members without all of them (server
+	// admins can change that in Integrations), and is checked again on every
+	// run because those overrides can also grant it.
+	Permissions int64
+	// GuildOnly commands aren't offered in DMs.
+	GuildOnly bool
+	// Ephemeral replies are only shown to the member who ran the command.
+	Ephemeral bool
+	// Defer acknowledges the interaction before the handler runs. Discord
+	// wants an answer within 3 seconds; anything that calls the Core defers.
+	Defer bool
+
+	Handler      func(c *CommandContext)
+	Autocomplete AutocompleteFunc
+}
+
+// Subcommand is one "/command sub" of a SlashCommand. Its Permissions are
+// checked in addition to the command's.
+type Subcommand struct {
+	Name        string
+	Description string
+	Options     []*discordgo.ApplicationCommandOption
+	Permissions int64
+	// Defer defers just this subcommand, for commands where another
+	// subcommand opens a modal.
+	Defer bool
+
+	Handler      func(c *CommandContext)
+	Autocomplete AutocompleteFunc
+}
+
+// AutocompleteFunc suggests values for the option the member is typing in.
+// Discord shows at most 25.
+type AutocompleteFunc func(c *CommandContext, option string, typed string) []*discordgo.ApplicationCommandOptionChoice
+
+// ComponentHandler handles a button click or modal submit whose custom ID
+// starts with the prefix it was registered under ("prefix:payload").
+type ComponentHandler struct {
+	Ephemeral bool
+	Defer     bool
+	Handler   func(c *CommandContext)
+}
+
+var (
+	slashCommands     = map[string]*SlashCommand{}
+	componentHandlers = map[string]*ComponentHandler{}
+)
+
+// registerSlashCommands adds commands to the set synced on ready. Called from main.go.
+func registerSlashCommands(cmds ...*SlashCommand) {
+	for _, cmd := range cmds {
+		slashCommands[cmd.Name] = cmd
+	}
+}
+
+// registerComponentHandler routes buttons and modals with custom IDs like
+// "prefix:payload" to h. Called from main.go.
+func registerComponentHandler(prefix string, h *ComponentHandler) {
+	componentHandlers[prefix] = h
+}
+
+// componentID builds a custom ID for registerComponentHandler. Discord limits
+// custom IDs to 100 characters.
+func componentID(prefix, payload string) string {
+	return prefix + ":" + payload
+}
+
+// ==========================================
+// Syncing
+// ==========================================
+
+// syncSlashCommands uploads the registered commands. Global commands can take
+// a while to reach every client, so SLASH_COMMAND_GUILD_IDS (comma-separated)
+// syncs them to just those guilds instead, which is instant: use it while
+// developing. Bulk overwrite also deletes commands that were removed here.
+func syncSlashCommands(s *discordgo.Session, appID string) {
+	cmds := make([]*discordgo.ApplicationCommand, 0, len(slashCommands))
+	for _, cmd := range slashCommands {
+		cmds = append(cmds, cmd.applicationCommand())
+	}
+
+	guildIDs := strings.FieldsFunc(os.Getenv("SLASH_COMMAND_GUILD_IDS"), func(r rune) bool { return r == ',' || r == ' ' })
+	if len(guildIDs) == 0 {
+		if _, err := s.ApplicationCommandBulkOverwrite(appID, "", cmds); err != nil {
+			log.Printf("slash commands: global sync failed: %v", err)
+			return
+		}
+		log.Printf("slash commands: synced %d commands globally", len(cmds))
+		return
+	}
+	for _, guildID := range guildIDs {
+		if _, err := s.ApplicationCommandBulkOverwrite(appID, guildID, cmds); err != nil {
+			log.Printf("slash commands: sync to guild %s failed: %v", guildID, err)
+			continue
+		}
+		log.Printf("slash commands: synced %d commands to guild %s", len(cmds), guildID)
+	}
+}
+
+func (cmd *SlashCommand) applicationCommand() *discordgo.ApplicationCommand {
+	ac := &discordgo.ApplicationCommand{
+		Type:        discordgo.ChatApplicationCommand,
+		Name:        cmd.Name,
+		Description: cmd.Description,
+		Options:     cmd.Options,
+	}
+	if cmd.Permissions != 0 {
+		perms := cmd.Permissions
+		ac.DefaultMemberPermissions = &perms
+	}
+	if cmd.GuildOnly {
+		inDMs := false
+		ac.DMPermission = &inDMs
+	}
+	for _, sub := range cmd.Subcommands {
+		ac.Options = append(ac.Options, &discordgo.ApplicationCommandOption{
+			Type:        discordgo.ApplicationCommandOptionSubCommand,
+			Name:        sub.Name,
+			Description: sub.Description,
+			Options:     sub.Options,
+		})
+	}
+	return ac
+}
+
+// ==========================================
+// Routing
+// ==========================================
+
+// handleSlashInteraction routes slash commands, autocomplete and registered
+// components/modals. It reports false for interactions it doesn't know, so
+// interactionCreate can try its older handlers.
+func handleSlashInteraction(s *discordgo.Session, i *discordgo.InteractionCreate, core *CoreClient) bool {
+	switch i.Type {
+	case discordgo.InteractionApplicationCommand, discordgo.InteractionApplicationCommandAutocomplete:
+		data := i.ApplicationCommandData()
+		cmd, ok := slashCommands[data.Name]
+		if !ok {
+			return false
+		}
+		c := newCommandContext(s, i, core, cmd.Ephemeral)
+		defer c.cancel()
+		handler, autocomplete, perms, deferResponse := cmd.Handler, cmd.Autocomplete, cmd.Permissions, cmd.Defer
+		c.options = data.Options
+		if len(cmd.Subcommands) > 0 {
+			if len(data.Options) == 0 {
+				return true
+			}
+			sub := cmd.subcommand(data.Options[0].Name)
+			if sub == nil {
+				return true
+			}
+			c.Subcommand = sub.Name
+			c.options = data.Options[0].Options
+			handler, autocomplete, perms = sub.Handler, sub.Autocomplete, perms|sub.Permissions
+			deferResponse = deferResponse || sub.Defer
+		}
+
+		if i.Type == discordgo.InteractionApplicationCommandAutocomplete {
+			c.autocomplete(autocomplete)
+			return true
+		}
+		if cmd.GuildOnly && i.GuildID == "" {
+			c.Reply("That command only works in a server.")
+			return true
+		}
+		if !c.hasPermissions(perms) {
+			c.ephemeral = true
+			c.Reply("You don't have permission to do that.")
+			return true
+		}
+		c.run(deferResponse, handler)
+		return true
+
+	case discordgo.InteractionMessageComponent, discordgo.InteractionModalSubmit:
+		var customID string
+		if i.Type == discordgo.InteractionModalSubmit {
+			customID = i.ModalSubmitData().CustomID
+		} else {
+			customID = i.MessageComponentData().CustomID
+		}
+		prefix, payload, _ := strings.Cut(customID, ":")
+		h, ok := componentHandlers[prefix]
+		if !ok {
+			return false
+		}
+		c := newCommandContext(s, i, core, h.Ephemeral)
+		defer c.cancel()
+		c.Payload = payload
+		c.run(h.Defer, h.Handler)
+		return true
+	}
+	return false
+}
+
+func (cmd *SlashCommand) subcommand(name string) *Subcommand {
+	for _, sub := range cmd.Subcommands {
+		if sub.Name == name {
+			return sub
+		}
+	}
+	return nil
+}
+
+// ==========================================
+// CommandContext
+// ==========================================
+
+// CommandContext is what handlers get: the interaction, its options and ways
+// to answer it that work the same whether or not it was deferred.
+type CommandContext struct {
+	Ctx         context.Context
+	Session     *discordgo.Session
+	Interaction *discordgo.InteractionCreate
+	Core        *CoreClient
+	// Subcommand is the subcommand that was run, "" for plain commands.
+	Subcommand string
+	// Payload is what followed "prefix:" in a component or modal custom ID.
+	Payload string
+
+	options   []*discordgo.ApplicationCommandInteractionDataOption
+	ephemeral bool
+	deferred  bool
+	responded bool
+	cancel    context.CancelFunc
+}
+
+func newCommandContext(s *discordgo.Session, i *discordgo.InteractionCreate, core *CoreClient, ephemeral bool) *CommandContext {
+	ctx, cancel := context.WithTimeout(context.Background(), slashInteractionTimeout)
+	return &CommandContext{
+		Ctx:         ctx,
+		Session:     s,
+		Interaction: i,
+		Core:        core,
+		ephemeral:   ephemeral,
+		cancel:      cancel,
+	}
+}
+
+// run acknowledges the interaction if asked to and calls the handler. A
+// handler that panics still gets the member an answer instead of
+// "The application did not respond".
+func (c *CommandContext) run(deferResponse bool, handler func(c *CommandContext)) {
+	if deferResponse {
+		err := c.Session.InteractionRespond(c.Interaction.Interaction, &discordgo.InteractionResponse{
+			Type: discordgo.InteractionResponseDeferredChannelMessageWithSource,
+			Data: &discordgo.InteractionResponseData{Flags: c.flags()},
+		})
+		if err != nil {
+			log.Printf("slash commands: failed to defer %s: %v", c.name(), err)
+			return
+		}
+		c.deferred = true
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("slash commands: %s panicked: %v", c.name(), r)
+			c.Reply("❌ Something went wrong, please try again later.")
+		}
+	}()
+	if handler != nil {
+		handler(c)
+	}
+	if !c.responded {
+		c.Reply("✅ Done.")
+	}
+}
+
+// UserID is who ran the command (Member in servers, User in DMs).
+func (c *CommandContext) UserID() string {
+	if c.Interaction.Member != nil && c.Interaction.Member.User != nil {
+		return c.Interaction.Member.User.ID
+	}
+	if c.Interaction.User != nil {
+		return c.Interaction.User.ID
+	}
+	return ""
+}
+
+// hasPermissions checks the member's permissions in the channel the command
+// was run in (Discord resolves them, including channel overwrites).
+func (c *CommandContext) hasPermissions(perms int64) bool {
+	if perms == 0 {
+		return true
+	}
+	if c.Interaction.Member == nil {
+		return false
+	}
+	have := c.Interaction.Member.Permissions
+	return have&discordgo.PermissionAdministrator != 0 || have&perms == perms
+}
+
+func (c *CommandContext) name() string {
+	if c.Interaction.Type == discordgo.InteractionApplicationCommand {
+		name := "/" + c.Interaction.ApplicationCommandData().Name
+		if c.Subcommand != "" {
+			name += " " + c.Subcommand
+		}
+		return name
+	}
+	return "component " + c.Payload
+}
+
+func (c *CommandContext) flags() discordgo.MessageFlags {
+	if c.ephemeral {
+		return discordgo.MessageFlagsEphemeral
+	}
+	return 0
+}
+
+// --- Options ---
+
+func (c *CommandContext) option(name string) *discordgo.ApplicationCommandInteractionDataOption {
+	for _, opt := range c.options {
+		if opt.Name == name {
+			return opt
+		}
+	}
+	return nil
+}
+
+// String returns a string option, "" if it wasn't given.
+func (c *CommandContext) String(name string) string {
+	if opt := c.option(name); opt != nil {
+		return strings.TrimSpace(opt.StringValue())
+	}
+	return ""
+}
+
+// Int returns an integer option, 0 if it wasn't given.
+func (c *CommandContext) Int(name string) int64 {
+	if opt := c.option(name); opt != nil {
+		return opt.IntValue()
+	}
+	return 0
+}
+
+// Number returns a number option, 0 if it wasn't given.
+func (c *CommandContext) Number(name string) float64 {
+	if opt := c.option(name); opt != nil {
+		return opt.FloatValue()
+	}
+	return 0
+}
+
+// Bool returns a boolean option and whether it was given.
+func (c *CommandContext) Bool(name string) (bool, bool) {
+	if opt := c.option(name); opt != nil {
+		return opt.BoolValue(), true
+	}
+	return false, false
+}
+
+// User returns a user option, nil if it wasn't given.
+func (c *CommandContext) User(name string) *discordgo.User {
+	if opt := c.option(name); opt != nil {
+		return opt.UserValue(c.Session)
+	}
+	return nil
+}
+
+// Channel returns a channel option, nil if it wasn't given.
+func (c *CommandContext) Channel(name string) *discordgo.Channel {
+	if opt := c.option(name); opt != nil {
+		return opt.ChannelValue(c.Session)
+	}
+	return nil
+}
+
+// Attachment returns a file option, nil if it wasn't given.
+func (c *CommandContext) Attachment(name string) *discordgo.MessageAttachment {
+	opt := c.option(name)
+	if opt == nil {
+		return nil
+	}
+	resolved := c.Interaction.ApplicationCommandData().Resolved
+	if resolved == nil {
+		return nil
+	}
+	id, _ := opt.Value.(string)
+	return resolved.Attachments[id]
+}
+
+// ModalValue returns a text input from a submitted modal.
+func (c *CommandContext) ModalValue(customID string) string {
+	if c.Interaction.Type != discordgo.InteractionModalSubmit {
+		return ""
+	}
+	for _, row := range c.Interaction.ModalSubmitData().Components {
+		actions, ok := row.(*discordgo.ActionsRow)
+		if !ok {
+			continue
+		}
+		for _, comp := range actions.Components {
+			if input, ok := comp.(*discordgo.TextInput); ok && input.CustomID == customID {
+				return strings.TrimSpace(input.Value)
+			}
+		}
+	}
+	return ""
+}
+
+// --- Responses ---
+
+// Reply answers with a message. After the first answer, further replies are
+// follow-up messages.
+func (c *CommandContext) Reply(content string) {
+	c.send(&discordgo.InteractionResponseData{Content: truncate(content, 2000)})
+}
+
+// ReplyEmbed answers with an embed.
+func (c *CommandContext) ReplyEmbed(embed *discordgo.MessageEmbed) {
+	c.send(&discordgo.InteractionResponseData{Embeds: []*discordgo.MessageEmbed{embed}})
+}
+
+// ReplyWithButtons answers with a message and one row of buttons (link
+// buttons, or ones with a registered component prefix).
+func (c *CommandContext) ReplyWithButtons(content string, buttons ...discordgo.Button) {
+	row := discordgo.ActionsRow{}
+	for _, b := range buttons {
+		row.Components = append(row.Components, b)
+	}
+	c.send(&discordgo.InteractionResponseData{
+		Content:    truncate(content, 2000),
+		Components: []discordgo.MessageComponent{row},
+	})
+}
+
+// ReplyError shows the Core's message for 4xx errors (they're written for
+// users), and a generic one otherwise.
+func (c *CommandContext) ReplyError(err error, fallback string) {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) && apiErr.Status >= 400 && apiErr.Status < 500 && apiErr.Message != "" {
+		c.Reply("❌ " + apiErr.Message)
+		return
+	}
+	log.Printf("slash commands: %s: %v", c.name(), err)
+	c.Reply("❌ " + fallback + ", please try again later.")
+}
+
+// Modal opens a form. It must be the first answer, so commands that open
+// one can't set Defer.
+func (c *CommandContext) Modal(customID, title string, inputs ...*discordgo.TextInput) {
+	rows := make([]discordgo.MessageComponent, 0, len(inputs))
+	for _, input := range inputs {
+		rows = append(rows, discordgo.ActionsRow{Components: []discordgo.MessageComponent{input}})
+	}
+	err := c.Session.InteractionRespond(c.Interaction.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseModal,
+		Data: &discordgo.InteractionResponseData{CustomID: customID, Title: title, Components: rows},
+	})
+	if err != nil {
+		log.Printf("slash commands: failed to open modal for %s: %v", c.name(), err)
+	}
+	c.responded = true
+}
+
+func (c *CommandContext) send(data *discordgo.InteractionResponseData) {
+	var err error
+	switch {
+	case c.responded:
+		_, err = c.Session.FollowupMessageCreate(c.Interaction.Interaction, true, &discordgo.WebhookParams{
+			Content:    data.Content,
+			Embeds:     data.Embeds,
+			Components: data.Components,
+			Flags:      c.flags(),
+		})
+	case c.deferred:
+		_, err = c.Session.InteractionResponseEdit(c.Interaction.Interaction, &discordgo.WebhookEdit{
+			Content:    &data.Content,
+			Embeds:     &data.Embeds,
+			Components: &data.Components,
+		})
+	default:
+		data.Flags = c.flags()
+		err = c.Session.InteractionRespond(c.Interaction.Interaction, &discordgo.InteractionResponse{
+			Type: discordgo.InteractionResponseChannelMessageWithSource,
+			Data: data,
+		})
+	}
+	if err != nil {
+		log.Printf("slash commands: failed to answer %s: %v", c.name(), err)
+	}
+	c.responded = true
+}
+
+func (c *CommandContext) autocomplete(fn AutocompleteFunc) {
+	var choices []*discordgo.ApplicationCommandOptionChoice
+	for _, opt := range c.options {
+		if opt.Focused && fn != nil {
+			// Partial input arrives as a string even for number options.
+			choices = fn(c, opt.Name, fmt.Sprint(opt.Value))
+			break
+		}
+	}
+	if len(choices) > 25 {
+		choices = choices[:25]
+	}
+	err := c.Session.InteractionRespond(c.Interaction.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionApplicationCommandAutocompleteResult,
+		Data: &discordgo.InteractionResponseData{Choices: choices},
+	})
+	if err != nil {
+		log.Printf("slash commands: autocomplete for %s failed: %v", c.name(), err)
+	}
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// handleTwitchCommand links a builder's Twitch channel to their C500 profile.
+// The channel shows on their profile page and drop embeds once linked.
+//
+//	!twitch          show the linked channel
+//	!twitch link     DM a Twitch login link (also used to switch channels)
+//	!twitch unlink   remove the link
+func handleTwitchCommand(s *discordgo.Session, m *discordgo.MessageCreate, core *CoreClient) {
+	args := strings.Fields(m.Content)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	sub := ""
+	if len(args) > 1 {
+		sub = strings.ToLower(args[1])
+	}
+	switch sub {
+	case "":
+		link, err := core.GetTwitchLink(ctx, m.Author.ID)
+		if err != nil {
+			var apiErr *APIError
+			if errors.As(err, &apiErr) && apiErr.Status == http.StatusNotFound {
+				s.ChannelMessageSend(m.ChannelID, "You haven't linked a Twitch channel yet. Run `!twitch link` to link one.")
+				return
+			}
+			replyGuestbookError(s, m, err, "Couldn't load your Twitch link")
+			return
+		}
+		s.ChannelMessageSend(m.ChannelID, "📺 Linked to **"+link.ChannelURL+"**")
+
+	case "link":
+		link, err := core.StartTwitchLink(ctx, m.Author.ID)
+		if err != nil {
+			replyGuestbookError(s, m, err, "Couldn't start linking Twitch")
+			return
+		}
+		// The link is bound to this Discord account: never post it in a channel.
+		ch, err := s.UserChannelCreate(m.Author.ID)
+		if err == nil {
+			_, err = s.ChannelMessageSend(ch.ID, fmt.Sprintf("📺 Log in to Twitch with the channel you build on to link it to your C500 profile:\n%s\n"+
+				"This link expires <t:%d:R>. Don't share it.", link.URL, link.ExpiresAt.Unix()))
+		}
+		if err != nil {
+			s.ChannelMessageSend(m.ChannelID, "❌ I couldn't DM you. Enable DMs from server members and try again.")
+			return
+		}
+		s.ChannelMessageSend(m.ChannelID, "📬 Check your DMs for the Twitch link.")
+
+	case "unlink":
+		if err := core.UnlinkTwitch(ctx, m.Author.ID); err != nil {
+			replyGuestbookError(s, m, err, "Couldn't unlink Twitch")
+			return
+		}
+		s.ChannelMessageSend(m.ChannelID, "✅ Twitch unlinked. Run `!twitch link` any time to link a channel again.")
+
+	default:
+		s.ChannelMessageSend(m.ChannelID, "Usage: `!twitch`, `!twitch link` or `!twitch unlink`")
+	}
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// handleVODReviewCommand lets C500 moderators (MODERATOR_DISCORD_IDS in the
+// Core) work the queue of live fulfillments whose VOD the Core couldn't verify
+// against the builder's Twitch channel. Escrow stays held until they decide:
+//
+//	!vodreview
+//	!vodreview approve|reject <order id>
+func handleVODReviewCommand(s *discordgo.Session, m *discordgo.MessageCreate, core *CoreClient) {
+	args := strings.Fields(m.Content)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	if len(args) < 2 {
+		orders, err := core.VODReviewQueue(ctx, m.Author.ID)
+		if err != nil {
+			replyGuestbookError(s, m, err, "Couldn't load the VOD review queue")
+			return
+		}
+		if len(orders) == 0 {
+			sendPrivately(s, m, "No VODs are waiting for review. 🎉")
+			return
+		}
+		var b strings.Builder
+		for _, o := range orders {
+			fmt.Fprintf(&b, "`%s` by <@%s> for <@%s> ($%.2f): %s\n> ⚠️ %s\n",
+				o.ID, o.SellerDiscordID, o.BuyerDiscordID, float64(o.PriceInCents)/100, o.VODLink, o.VODReview.Reason)
+		}
+		b.WriteString("\n`!vodreview approve <order id>` releases the funds, `reject` asks the builder for a real VOD.")
+		sendPrivately(s, m, b.String())
+		return
+	}
+
+	switch sub := strings.ToLower(args[1]); sub {
+	case "approve", "reject":
+		if len(args) < 3 {
+			s.ChannelMessageSend(m.ChannelID, "Usage: `!vodreview "+sub+" <order id>`")
+			return
+		}
+		if err := core.ReviewVOD(ctx, args[2], m.Author.ID, sub); err != nil {
+			replyGuestbookError(s, m, err, "Couldn't review that VOD")
+			return
+		}
+		if sub == "approve" {
+			s.ChannelMessageSend(m.ChannelID, "✅ VOD approved, funds released to the builder.")
+			return
+		}
+		s.ChannelMessageSend(m.ChannelID, "🚫 VOD rejected. The builder needs to submit a VOD from their stream.")
+
+	default:
+		s.ChannelMessageSend(m.ChannelID, "Usage: `!vodreview` to see the queue, `!vodreview approve|reject <order id>`")
+	}
+}
@@ -0,0 +1,66 @@
+var loadSessionsOnce sync.Once
+
+// ready runs when the bot connects (and again after a full reconnect).
+// Register it next to messageCreate: dg.AddHandler(ready)
+func ready(s *discordgo.Session, r *discordgo.Ready) {
+    loadSessionsOnce.Do(func() {
+        if err := sessions.Load(); err != nil {
+            log.Printf("officehours: failed to load saved sessions: %v", err)
+        }
+    })
+    reconcileSessions(s, r)
+}
+
+// reconcileSessions checks each saved session against Discord, since stages
+// may have closed (or been deleted) while the bot was offline:
+//   - stage still live: keep it, `!officehours end` works as before
+//   - stage closed but the channel is still there: delete the orphan
+//   - channel gone, or the bot left the server: just forget it
+func reconcileSessions(s *discordgo.Session, r *discordgo.Ready) {
+    inGuild := map[string]bool{}
+    for _, g := range r.Guilds {
+        inGuild[g.ID] = true
+    }
+
+    for _, session := range sessions.All() {
+        if !inGuild[session.GuildID] {
+            forgetSession(session, "the bot is no longer in the server")
+            continue
+        }
+
+        if _, err := s.Channel(session.StageChannelID); err != nil {
+            if isNotFound(err) {
+                forgetSession(session, "the stage channel was deleted")
+            } else {
+                // Keep it and try again on the next ready.
+                log.Printf("officehours: couldn't check stage %s in guild %s: %v", session.StageChannelID, session.GuildID, err)
+            }
+            continue
+        }
+
+        _, err := s.StageInstance(session.StageChannelID)
+        if err == nil {
+            log.Printf("officehours: resumed session %q in guild %s", session.Topic, session.GuildID)
+            continue
+        }
+        if !isNotFound(err) {
+            log.Printf("officehours: couldn't check stage %s in guild %s: %v", session.StageChannelID, session.GuildID, err)
+            continue
+        }
+
+        // The stage went offline without us: remove the leftover channel.
+        if _, err := s.ChannelDelete(session.StageChannelID); err != nil && !isNotFound(err) {
+            log.Printf("officehours: failed to delete orphaned stage %s in guild %s: %v", session.StageChannelID, session.GuildID, err)
+            continue
+        }
+        forgetSession(session, "the stage closed while the bot was offline")
+        s.ChannelMessageSend(session.AnnounceChannelID, "✅ Office Hours on **"+session.Topic+"** has ended.")
+    }
+}
+
+func forgetSession(session officeHoursSession, reason string) {
+    log.Printf("officehours: ending session %q in guild %s: %s", session.Topic, session.GuildID, reason)
+    if err := sessions.Delete(session.GuildID, session.StageChannelID); err != nil {
+        log.Printf("officehours: failed to save ended session for guild %s: %v", session.GuildID, err)
+    }
+}
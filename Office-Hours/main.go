@@ -1,8 +1,7 @@
 // ... (imports)
 
-// Global state to track the active session (Simple version)
-// In production, use a database or map if managing multiple servers
-var activeStageChannelID string
+// Sessions are tracked per server in sessions.go, and saved to disk so a
+// restart doesn't orphan a live stage (see reconcileSessions in Ready.go).
 
 func handleOfficeHours(s *discordgo.Session, m *discordgo.MessageCreate) {
     // 1. Parse the Command
@@ -13,13 +12,19 @@ func handleOfficeHours(s *discordgo.Session, m *discordgo.MessageCreate) {
     }
     action := args[1]
 
+    // Sessions belong to a server; there's no stage to open in DMs.
+    if m.GuildID == "" {
+        s.ChannelMessageSend(m.ChannelID, "Office Hours can only be run from a server.")
+        return
+    }
+
     // 2. START SESSION
     if action == "start" {
-        if activeStageChannelID != "" {
+        if !sessions.Reserve(m.GuildID) {
             s.ChannelMessageSend(m.ChannelID, "⚠️ A session is already active!")
             return
         }
-        
+
         topic := "General Q&A"
         if len(args) > 2 {
             topic = args[2]
@@ -40,6 +45,7 @@ func handleOfficeHours(s *discordgo.Session, m *discordgo.MessageCreate) {
         })
 
         if err != nil {
+            sessions.Release(m.GuildID)
             s.ChannelMessageSend(m.ChannelID, "Error creating stage: "+err.Error())
             return
         }
@@ -55,7 +61,18 @@ func handleOfficeHours(s *discordgo.Session, m *discordgo.MessageCreate) {
             s.ChannelMessageSend(m.ChannelID, "Channel created, but failed to go LIVE: "+err.Error())
         }
 
-        activeStageChannelID = channel.ID
+        // Save it before announcing: if this fails, a restart couldn't find the stage.
+        err = sessions.Put(officeHoursSession{
+            GuildID:           m.GuildID,
+            StageChannelID:    channel.ID,
+            Topic:             topic,
+            HostID:            m.Author.ID,
+            AnnounceChannelID: m.ChannelID,
+            StartedAt:         time.Now().UTC(),
+        })
+        if err != nil {
+            log.Printf("officehours: failed to save session for guild %s: %v", m.GuildID, err)
+        }
 
         // C. Post the Announcement Embed
         embed := &discordgo.MessageEmbed{
@@ -69,19 +86,24 @@ func handleOfficeHours(s *discordgo.Session, m *discordgo.MessageCreate) {
 
     // 3. END SESSION
     if action == "end" {
-        if activeStageChannelID == "" {
+        // Only this server's session; other servers keep theirs.
+        session := sessions.Get(m.GuildID)
+        if session == nil {
             s.ChannelMessageSend(m.ChannelID, "No active session to end.")
             return
         }
 
         // Delete the channel (This automatically kills the Stage Instance)
-        _, err := s.ChannelDelete(activeStageChannelID)
-        if err != nil {
+        // If someone already deleted it by hand, the session is over anyway.
+        _, err := s.ChannelDelete(session.StageChannelID)
+        if err != nil && !isNotFound(err) {
             s.ChannelMessageSend(m.ChannelID, "Failed to close stage: "+err.Error())
             return
         }
 
+        if err := sessions.Delete(m.GuildID, session.StageChannelID); err != nil {
+            log.Printf("officehours: failed to save ended session for guild %s: %v", m.GuildID, err)
+        }
         s.ChannelMessageSend(m.ChannelID, "✅ Office Hours concluded. Thanks for joining!")
-        activeStageChannelID = ""
     }
 }
@@ -0,0 +1,164 @@
+// ... (imports: encoding/json, errors, net/http, os, path/filepath, sync, time)
+
+// officeHoursSession is one live Office Hours stage. Each server can have one.
+type officeHoursSession struct {
+    GuildID           string    `json:"guild_id"`
+    StageChannelID    string    `json:"stage_channel_id"`
+    Topic             string    `json:"topic"`
+    HostID            string    `json:"host_id"`
+    AnnounceChannelID string    `json:"announce_channel_id"`
+    StartedAt         time.Time `json:"started_at"`
+}
+
+// sessionStore keeps the live sessions per guild and saves them to a JSON
+// file, so a restart can find (and clean up) the stages it left open.
+type sessionStore struct {
+    mu       sync.Mutex
+    path     string
+    sessions map[string]*officeHoursSession // guild ID -> session
+    starting map[string]bool                // guilds creating a stage right now
+}
+
+// sessions is shared by the command and ready handlers.
+var sessions = newSessionStore(sessionsFilePath())
+
+func sessionsFilePath() string {
+    if p := os.Getenv("OFFICE_HOURS_STATE_FILE"); p != "" {
+        return p
+    }
+    return "officehours_sessions.json"
+}
+
+func newSessionStore(path string) *sessionStore {
+    return &sessionStore{
+        path:     path,
+        sessions: map[string]*officeHoursSession{},
+        starting: map[string]bool{},
+    }
+}
+
+// Load reads the saved sessions. A missing file means none were running.
+func (st *sessionStore) Load() error {
+    st.mu.Lock()
+    defer st.mu.Unlock()
+
+    data, err := os.ReadFile(st.path)
+    if errors.Is(err, os.ErrNotExist) {
+        return nil
+    }
+    if err != nil {
+        return err
+    }
+    var saved []*officeHoursSession
+    if err := json.Unmarshal(data, &saved); err != nil {
+        return err
+    }
+    st.sessions = map[string]*officeHoursSession{}
+    for _, sess := range saved {
+        st.sessions[sess.GuildID] = sess
+    }
+    return nil
+}
+
+// Reserve claims the guild for a new session, so two `start`s can't both
+// create a stage. It returns false if the guild already has one.
+// Follow with Put on success or Release on failure.
+func (st *sessionStore) Reserve(guildID string) bool {
+    st.mu.Lock()
+    defer st.mu.Unlock()
+
+    if st.sessions[guildID] != nil || st.starting[guildID] {
+        return false
+    }
+    st.starting[guildID] = true
+    return true
+}
+
+// Release drops a reservation that didn't become a session.
+func (st *sessionStore) Release(guildID string) {
+    st.mu.Lock()
+    defer st.mu.Unlock()
+    delete(st.starting, guildID)
+}
+
+// Get returns a copy of the guild's session, or nil.
+func (st *sessionStore) Get(guildID string) *officeHoursSession {
+    st.mu.Lock()
+    defer st.mu.Unlock()
+
+    if sess := st.sessions[guildID]; sess != nil {
+        c := *sess
+        return &c
+    }
+    return nil
+}
+
+// All returns copies of every session, for reconciling.
+func (st *sessionStore) All() []officeHoursSession {
+    st.mu.Lock()
+    defer st.mu.Unlock()
+
+    all := make([]officeHoursSession, 0, len(st.sessions))
+    for _, sess := range st.sessions {
+        all = append(all, *sess)
+    }
+    return all
+}
+
+// Put saves the guild's session (and ends its reservation).
+func (st *sessionStore) Put(sess officeHoursSession) error {
+    st.mu.Lock()
+    defer st.mu.Unlock()
+
+    delete(st.starting, sess.GuildID)
+    st.sessions[sess.GuildID] = &sess
+    return st.save()
+}
+
+// Delete forgets the guild's session, but only if it's still the one on
+// stageChannelID (a new session may have started in the meantime).
+func (st *sessionStore) Delete(guildID, stageChannelID string) error {
+    st.mu.Lock()
+    defer st.mu.Unlock()
+
+    sess := st.sessions[guildID]
+    if sess == nil || sess.StageChannelID != stageChannelID {
+        return nil
+    }
+    delete(st.sessions, guildID)
+    return st.save()
+}
+
+// save writes the file atomically: a crash mid-write leaves the old one.
+// The caller holds st.mu.
+func (st *sessionStore) save() error {
+    saved := make([]*officeHoursSession, 0, len(st.sessions))
+    for _, sess := range st.sessions {
+        saved = append(saved, sess)
+    }
+    data, err := json.MarshalIndent(saved, "", "  ")
+    if err != nil {
+        return err
+    }
+
+    tmp, err := os.CreateTemp(filepath.Dir(st.path), ".officehours-*.tmp")
+    if err != nil {
+        return err
+    }
+    defer os.Remove(tmp.Name()) // no-op after the rename
+    if _, err := tmp.Write(data); err != nil {
+        tmp.Close()
+        return err
+    }
+    if err := tmp.Close(); err != nil {
+        return err
+    }
+    return os.Rename(tmp.Name(), st.path)
+}
+
+// isNotFound reports whether a Discord API call failed because the
+// channel or stage instance no longer exists.
+func isNotFound(err error) bool {
+    var restErr *discordgo.RESTError
+    return errors.As(err, &restErr) && restErr.Response != nil && restErr.Response.StatusCode == http.StatusNotFound
+}